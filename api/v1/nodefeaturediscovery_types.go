@@ -18,11 +18,44 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultServicePort is the port nfd-master serves on when
+	// OperandSpec.ServicePort is not set
+	DefaultServicePort = 12000
+	// DefaultOperandPort is the port the other NFD components serve
+	// on when it is not set in OperandSpec.Ports
+	DefaultOperandPort = 8080
+)
+
+// ManagementState tells whether and how the operator manages the operands
+// of an instance
+// +kubebuilder:validation:Enum=Managed;Unmanaged;Removed
+type ManagementState string
+
+const (
+	// Managed operands are reconciled by the operator
+	Managed ManagementState = "Managed"
+	// Unmanaged operands are left as they are, e.g. to debug them, and
+	// only the status of the instance is reported
+	Unmanaged ManagementState = "Unmanaged"
+	// Removed operands are deleted while the instance is kept
+	Removed ManagementState = "Removed"
 )
 
 // NodeFeatureDiscoverySpec defines the desired state of NodeFeatureDiscovery
 // +k8s:openapi-gen=true
 type NodeFeatureDiscoverySpec struct {
+	// ManagementState tells whether the operator reconciles the operands,
+	// Managed, leaves them as they are, Unmanaged, or deletes them while
+	// keeping the instance, Removed
+	// +optional
+	// +kubebuilder:default=Managed
+	ManagementState ManagementState `json:"managementState,omitempty"`
+
 	// +optional
 	Operand OperandSpec `json:"operand"`
 
@@ -34,8 +67,15 @@ type NodeFeatureDiscoverySpec struct {
 	// +optional
 	TopologyUpdater bool `json:"topologyUpdater"`
 
+	// TopologyUpdaterConfig describes configuration options for the
+	// NFD topology updater. It is only used when TopologyUpdater is set
+	// +optional
+	TopologyUpdaterConfig *TopologyUpdaterConfig `json:"topologyUpdaterConfig,omitempty"`
+
 	// Instance name. Used to separate annotation namespaces for
-	// multiple parallel deployments.
+	// multiple parallel deployments. When set, it is also appended to the
+	// names of all the objects generated for this instance, so it must be
+	// a valid DNS label.
 	// +optional
 	Instance string `json:"instance"`
 
@@ -63,6 +103,24 @@ type NodeFeatureDiscoverySpec struct {
 	// +optional
 	WorkerConfig ConfigMap `json:"workerConfig"`
 
+	// WorkerProfiles deploys one nfd-worker DaemonSet, with its own
+	// ConfigMap, per pool of nodes. A node must not be selected by more
+	// than one profile. When set, the single worker DaemonSet driven by
+	// Operand.WorkerNodeSelector is not deployed
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	WorkerProfiles []WorkerProfile `json:"workerProfiles,omitempty"`
+
+	// RulePresets enables by name curated NodeFeatureRules shipped with the
+	// operator, e.g. nvidia-gpu or sriov-nic. The operator creates one
+	// nfd.k8s-sigs.io NodeFeatureRule per preset in the namespace of the
+	// instance, upgrades it along with the operator and removes it when the
+	// preset is disabled
+	// +optional
+	// +listType=set
+	RulePresets []string `json:"rulePresets,omitempty"`
+
 	// PruneOnDelete defines whether the NFD-master prune should be
 	// enabled or not. If enabled, the Operator will deploy an NFD-Master prune
 	// job that will remove all NFD labels (and other NFD-managed assets such
@@ -92,13 +150,28 @@ type OperandSpec struct {
 	ImagePullPolicy string `json:"imagePullPolicy,omitempty"`
 
 	// ServicePort specifies the TCP port that nfd-master
-	// listens for incoming requests.
+	// listens for incoming requests, i.e. serves its health
+	// and metrics endpoints on [defaults to 12000]
 	// +kubebuilder:validation:Optional
 	ServicePort int `json:"servicePort"`
 
+	// Ports specifies the TCP ports that the other NFD components
+	// serve their health and metrics endpoints on
+	// +kubebuilder:validation:Optional
+	Ports OperandPorts `json:"ports,omitempty"`
+
 	// WorkerTolerations defines tolerations to be applied to the worker Daemonset
 	WorkerTolerations []corev1.Toleration `json:"workerTolerations,omitempty"`
 
+	// MasterReplicas is the number of nfd-master replicas. With more than one
+	// replica the masters elect a leader, are spread across the control-plane
+	// nodes and are protected by a PodDisruptionBudget. Only one instance of
+	// a namespace can run more than one replica, as the leader election lease
+	// of nfd-master is shared by the namespace [defaults to 1]
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MasterReplicas *int32 `json:"masterReplicas,omitempty"`
+
 	// MasterTolerations defines tolerations to be applied to the master deployment
 	MasterTolerations []corev1.Toleration `json:"masterTolerations,omitempty"`
 
@@ -119,12 +192,270 @@ type OperandSpec struct {
 
 	// GCTolerations defines tolerations to be applied to the GC deployment
 	GCTolerations []corev1.Toleration `json:"gcTolerations,omitempty"`
+
+	// TopologyUpdaterNodeSelector describes on which nodes the topology
+	// updater pod should be deployed.
+	TopologyUpdaterNodeSelector map[string]string `json:"topologyUpdaterNodeSelector,omitempty"`
+
+	// TopologyUpdaterTolerations defines tolerations to be applied to the
+	// topology updater Daemonset
+	TopologyUpdaterTolerations []corev1.Toleration `json:"topologyUpdaterTolerations,omitempty"`
+
+	// MasterResources defines the resource requirements of the nfd-master
+	// container [defaults to 100m CPU/128Mi memory requests and 300m CPU/4Gi memory limits]
+	// +optional
+	MasterResources *corev1.ResourceRequirements `json:"masterResources,omitempty"`
+
+	// WorkerResources defines the resource requirements of the nfd-worker
+	// container [defaults to 5m CPU/64Mi memory requests and 200m CPU/512Mi memory limits]
+	// +optional
+	WorkerResources *corev1.ResourceRequirements `json:"workerResources,omitempty"`
+
+	// GCResources defines the resource requirements of the nfd-gc
+	// container [defaults to none]
+	// +optional
+	GCResources *corev1.ResourceRequirements `json:"gcResources,omitempty"`
+
+	// TopologyUpdaterResources defines the resource requirements of the
+	// nfd-topology-updater container [defaults to none]
+	// +optional
+	TopologyUpdaterResources *corev1.ResourceRequirements `json:"topologyUpdaterResources,omitempty"`
+
+	// PruneResources defines the resource requirements of the prune job
+	// container [defaults to none]
+	// +optional
+	PruneResources *corev1.ResourceRequirements `json:"pruneResources,omitempty"`
+}
+
+// OperandPorts describes the health and metrics ports of the NFD components
+// other than nfd-master, whose port is set with ServicePort
+type OperandPorts struct {
+	// Worker is the port of nfd-worker. The worker runs in the host
+	// network namespace, so the port must be free on every node the worker
+	// is scheduled on [defaults to 8080]
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Worker int `json:"worker,omitempty"`
+
+	// GC is the port of nfd-gc [defaults to 8080]
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	GC int `json:"gc,omitempty"`
+
+	// TopologyUpdater is the port of nfd-topology-updater [defaults to 8080]
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	TopologyUpdater int `json:"topologyUpdater,omitempty"`
+}
+
+// WorkerProfile describes the nfd-worker deployed on a pool of nodes. The
+// settings that are not set default to the ones of the NodeFeatureDiscovery
+type WorkerProfile struct {
+	// Name of the profile. It is appended to the names of the worker
+	// DaemonSet and ConfigMap of the profile, so it must be a valid DNS label
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the pool
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// NodeAffinity selects the nodes of the pool together with NodeSelector.
+	// Its required terms replace the default worker node affinity
+	// +optional
+	NodeAffinity *corev1.NodeAffinity `json:"nodeAffinity,omitempty"`
+
+	// WorkerConfig is the worker configuration of the pool
+	// [defaults to spec.workerConfig]
+	// +optional
+	WorkerConfig *ConfigMap `json:"workerConfig,omitempty"`
+
+	// Tolerations defines tolerations to be applied to the worker pods of
+	// the pool [defaults to spec.operand.workerTolerations]
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName sets a specific priority class for the worker pods
+	// of the pool [defaults to spec.operand.workerPriorityClassName]
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Resources defines the resource requirements of the nfd-worker
+	// container of the pool [defaults to spec.operand.workerResources]
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // ConfigMap describes configuration options for the NFD worker
 type ConfigMap struct {
-	// BinaryData holds the NFD configuration file
+	// BinaryData holds the NFD configuration file. It is passed verbatim
+	// to nfd-worker and is mutually exclusive with Config and ConfigMapRef
+	// +optional
 	ConfigData string `json:"configData"`
+
+	// Config is the typed nfd-worker configuration, rendered by the operator
+	// into the NFD configuration file. It is mutually exclusive with
+	// ConfigData and ConfigMapRef
+	// +optional
+	Config *WorkerConfig `json:"config,omitempty"`
+
+	// ConfigMapRef references a key of a user-owned ConfigMap, in the
+	// namespace of the NodeFeatureDiscovery, holding the NFD configuration
+	// file. The ConfigMap is mounted as is in the worker pods instead of the
+	// one generated by the operator, and is mutually exclusive with
+	// ConfigData and Config
+	// +optional
+	ConfigMapRef *corev1.ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// TopologyUpdaterConfig describes the options of nfd-topology-updater, see
+// https://kubernetes-sigs.github.io/node-feature-discovery/stable/reference/topology-updater-commandline-reference.html
+type TopologyUpdaterConfig struct {
+	// SleepInterval is the delay between two updates of the
+	// NodeResourceTopology objects, as a Go duration [defaults to 3s]
+	// +optional
+	SleepInterval string `json:"sleepInterval,omitempty"`
+
+	// KubeletStateDir is the host directory holding the kubelet state
+	// files, whose changes trigger an update of the NodeResourceTopology
+	// objects [defaults to /var/lib/kubelet]
+	// +optional
+	KubeletStateDir string `json:"kubeletStateDir,omitempty"`
+
+	// PodResourcesSocketPath is the host path of the kubelet pod-resources
+	// socket [defaults to /var/lib/kubelet/pod-resources/kubelet.sock]
+	// +optional
+	PodResourcesSocketPath string `json:"podResourcesSocketPath,omitempty"`
+
+	// KubeletConfigFile is the host path of the kubelet configuration
+	// file. When unset, the configuration is read from the /configz
+	// endpoint of the kubelet
+	// +optional
+	KubeletConfigFile string `json:"kubeletConfigFile,omitempty"`
+
+	// ExcludeList lists, per node name, the resources that are not
+	// reported in the NodeResourceTopology objects. The "*" key applies
+	// to all the nodes
+	// +optional
+	ExcludeList map[string][]string `json:"excludeList,omitempty"`
+}
+
+// WorkerConfig describes the nfd-worker configuration file, see
+// https://kubernetes-sigs.github.io/node-feature-discovery/stable/reference/worker-configuration-reference.html
+type WorkerConfig struct {
+	// Core holds the options of the nfd-worker itself
+	// +optional
+	Core *WorkerCoreConfig `json:"core,omitempty"`
+
+	// Sources holds the per feature source settings
+	// +optional
+	Sources *WorkerSourcesConfig `json:"sources,omitempty"`
+}
+
+// WorkerCoreConfig describes the core section of the nfd-worker configuration
+type WorkerCoreConfig struct {
+	// SleepInterval is the delay between two feature discovery passes,
+	// as a Go duration (e.g. "60s")
+	// +optional
+	SleepInterval string `json:"sleepInterval,omitempty"`
+
+	// FeatureSources lists the feature sources to enable. "all" enables all
+	// of them, and a source prefixed with "-" is disabled
+	// +optional
+	FeatureSources []string `json:"featureSources,omitempty"`
+
+	// LabelSources lists the label sources to enable. "all" enables all
+	// of them, and a source prefixed with "-" is disabled
+	// +optional
+	LabelSources []string `json:"labelSources,omitempty"`
+
+	// LabelWhiteList is a regular expression that the names of the
+	// labels must match in order to be published
+	// +optional
+	LabelWhiteList string `json:"labelWhiteList,omitempty"`
+
+	// NoPublish disables publishing the discovered features to the API
+	// +optional
+	NoPublish bool `json:"noPublish,omitempty"`
+
+	// NoOwnerRefs disables setting the owner references of the NodeFeature
+	// objects created by the worker
+	// +optional
+	NoOwnerRefs bool `json:"noOwnerRefs,omitempty"`
+
+	// Klog holds the logging options of the worker, e.g. "v: 3"
+	// +optional
+	Klog map[string]string `json:"klog,omitempty"`
+}
+
+// WorkerSourcesConfig describes the sources section of the nfd-worker configuration
+type WorkerSourcesConfig struct {
+	// CPU holds the settings of the cpu feature source
+	// +optional
+	CPU *CPUSourceConfig `json:"cpu,omitempty"`
+
+	// Kernel holds the settings of the kernel feature source
+	// +optional
+	Kernel *KernelSourceConfig `json:"kernel,omitempty"`
+
+	// PCI holds the settings of the pci feature source
+	// +optional
+	PCI *DeviceSourceConfig `json:"pci,omitempty"`
+
+	// USB holds the settings of the usb feature source
+	// +optional
+	USB *DeviceSourceConfig `json:"usb,omitempty"`
+
+	// Custom holds the rules of the custom feature source
+	// +optional
+	Custom []v1alpha1.EmbeddedRule `json:"custom,omitempty"`
+}
+
+// CPUSourceConfig describes the settings of the cpu feature source
+type CPUSourceConfig struct {
+	// CPUID holds the cpuid attributes to publish
+	// +optional
+	CPUID *CPUIDConfig `json:"cpuid,omitempty"`
+}
+
+// CPUIDConfig describes the cpuid attributes published by the cpu feature source
+type CPUIDConfig struct {
+	// AttributeBlacklist lists the cpuid attributes that are not published
+	// +optional
+	AttributeBlacklist []string `json:"attributeBlacklist,omitempty"`
+
+	// AttributeWhitelist lists the only cpuid attributes that are published.
+	// It has priority over AttributeBlacklist
+	// +optional
+	AttributeWhitelist []string `json:"attributeWhitelist,omitempty"`
+}
+
+// KernelSourceConfig describes the settings of the kernel feature source
+type KernelSourceConfig struct {
+	// KconfigFile is the path of the kernel config file to read
+	// instead of the one of the running kernel
+	// +optional
+	KconfigFile string `json:"kconfigFile,omitempty"`
+
+	// ConfigOpts lists the kernel config options to publish
+	// +optional
+	ConfigOpts []string `json:"configOpts,omitempty"`
+}
+
+// DeviceSourceConfig describes the settings of the pci and usb feature sources
+type DeviceSourceConfig struct {
+	// DeviceClassWhitelist lists the device classes, as hexadecimal
+	// strings (e.g. "03"), of the devices to publish
+	// +optional
+	DeviceClassWhitelist []string `json:"deviceClassWhitelist,omitempty"`
+
+	// DeviceLabelFields lists the device fields used to build the
+	// published label names (e.g. "vendor")
+	// +optional
+	DeviceLabelFields []string `json:"deviceLabelFields,omitempty"`
 }
 
 // NodeFeatureDiscoveryStatus defines the observed state of NodeFeatureDiscovery
@@ -134,6 +465,141 @@ type NodeFeatureDiscoveryStatus struct {
 	//
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// MasterLeader is the nfd-master replica currently holding the
+	// leader election lease. It is only set when the master runs with
+	// more than one replica
+	//
+	// +optional
+	MasterLeader string `json:"masterLeader,omitempty"`
+
+	// Components reports the state of the workload of every component,
+	// each worker profile having its own. It is not set when the components
+	// are removed
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Components []ComponentStatus `json:"components,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the status was last
+	// reconciled for. The spec is not processed yet while it is lower than
+	// metadata.generation. It is not updated while the instance is Unmanaged
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// LastReconcileTime is when the operator last reconciled the spec without
+	// error. When nothing else in the status changes, it is updated at most
+	// every 5 minutes
+	//
+	// +optional
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// LastReconcileError is the error of the last reconcile, every failure
+	// on its own line, truncated. It is empty once a reconcile succeeds
+	//
+	// +optional
+	LastReconcileError string `json:"lastReconcileError,omitempty"`
+}
+
+// ComponentState is the state of the workload of a component
+// +kubebuilder:validation:Enum=Available;Progressing;Degraded
+type ComponentState string
+
+const (
+	// ComponentAvailable workloads have all their pods ready
+	ComponentAvailable ComponentState = "Available"
+	// ComponentProgressing workloads are rolling out their pods
+	ComponentProgressing ComponentState = "Progressing"
+	// ComponentDegraded workloads are missing or have no pod running
+	ComponentDegraded ComponentState = "Degraded"
+)
+
+// ComponentStatus is the observed state of the Deployment or DaemonSet of a
+// component
+type ComponentStatus struct {
+	// Name of the workload, e.g. nfd-master or nfd-worker-<profile>
+	Name string `json:"name"`
+
+	// Kind of the workload, Deployment or DaemonSet
+	Kind string `json:"kind"`
+
+	// State of the workload
+	State ComponentState `json:"state"`
+
+	// Reason is a CamelCase reason for a state other than Available
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message tells why the state is not Available
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Desired is the number of pods the workload should run: replicas for
+	// a Deployment, nodes for a DaemonSet
+	Desired int32 `json:"desired"`
+
+	// Ready is the number of ready pods
+	Ready int32 `json:"ready"`
+
+	// Updated is the number of pods running the latest pod template
+	Updated int32 `json:"updated"`
+
+	// Image of the operand container of the pod template
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// ObservedGeneration is the generation of the workload observed by its
+	// controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PodFailures summarizes, per reason, why the pods of a workload that is
+	// not Available fail
+	// +optional
+	// +listType=map
+	// +listMapKey=reason
+	PodFailures []PodFailure `json:"podFailures,omitempty"`
+}
+
+// PodFailureReason is why pods of a component fail
+// +kubebuilder:validation:Enum=CrashLoopBackOff;ImagePullBackOff;OOMKilled;AdmissionFailed;Unschedulable
+type PodFailureReason string
+
+const (
+	// PodCrashLoopBackOff pods have a container restarting in a loop
+	PodCrashLoopBackOff PodFailureReason = "CrashLoopBackOff"
+	// PodImagePullBackOff pods cannot pull the image of a container
+	PodImagePullBackOff PodFailureReason = "ImagePullBackOff"
+	// PodOOMKilled pods have a container killed for exceeding its memory
+	// limit
+	PodOOMKilled PodFailureReason = "OOMKilled"
+	// PodAdmissionFailed pods are rejected at admission, e.g. by the
+	// SecurityContextConstraints, and are not created
+	PodAdmissionFailed PodFailureReason = "AdmissionFailed"
+	// PodUnschedulable pods cannot be scheduled on their node
+	PodUnschedulable PodFailureReason = "Unschedulable"
+)
+
+// PodFailure is the number of pods of a component failing for a reason,
+// with examples of the nodes they run on
+type PodFailure struct {
+	// Reason of the failure
+	Reason PodFailureReason `json:"reason"`
+
+	// Count is the number of pods failing for the reason. Pods rejected at
+	// admission are not created and are counted once
+	Count int32 `json:"count"`
+
+	// Nodes lists some of the nodes of the failing pods
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// Message is the message of one of the failures, e.g. of the container
+	// or of the rejected creation
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -179,6 +645,125 @@ func (o *OperandSpec) ImagePolicy(pullPolicy string) corev1.PullPolicy {
 	return corev1.PullIfNotPresent
 }
 
+// MasterPort returns the port nfd-master serves on
+func (o *OperandSpec) MasterPort() int32 {
+	return portOrDefault(o.ServicePort, DefaultServicePort)
+}
+
+// MasterReplicaCount returns the number of nfd-master replicas
+func (o *OperandSpec) MasterReplicaCount() int32 {
+	if o.MasterReplicas == nil {
+		return 1
+	}
+	return *o.MasterReplicas
+}
+
+// MasterLeaderElection returns whether the nfd-master replicas need to
+// elect a leader, i.e. whether there is more than one of them
+func (o *OperandSpec) MasterLeaderElection() bool {
+	return o.MasterReplicaCount() > 1
+}
+
+// WorkerPort returns the port nfd-worker serves on
+func (o *OperandSpec) WorkerPort() int32 {
+	return portOrDefault(o.Ports.Worker, DefaultOperandPort)
+}
+
+// GCPort returns the port nfd-gc serves on
+func (o *OperandSpec) GCPort() int32 {
+	return portOrDefault(o.Ports.GC, DefaultOperandPort)
+}
+
+// TopologyUpdaterPort returns the port nfd-topology-updater serves on
+func (o *OperandSpec) TopologyUpdaterPort() int32 {
+	return portOrDefault(o.Ports.TopologyUpdater, DefaultOperandPort)
+}
+
+func portOrDefault(port, defaultPort int) int32 {
+	if port == 0 {
+		return int32(defaultPort)
+	}
+	return int32(port)
+}
+
+// GetManagementState returns the management state of the instance, Managed
+// when it is not set
+func (n *NodeFeatureDiscovery) GetManagementState() ManagementState {
+	if n.Spec.ManagementState == "" {
+		return Managed
+	}
+	return n.Spec.ManagementState
+}
+
+// ComponentName returns the name of the object generated for the given NFD
+// component (e.g. "nfd-worker"), suffixed with Spec.Instance when it is set so
+// that several NodeFeatureDiscovery instances can coexist in a cluster
+func (n *NodeFeatureDiscovery) ComponentName(component string) string {
+	if n.Spec.Instance == "" {
+		return component
+	}
+	return component + "-" + n.Spec.Instance
+}
+
+// WorkerProfileName returns the name of the worker DaemonSet and ConfigMap
+// of the given profile. The unnamed profile is the single worker deployed
+// when no profile is set
+func (n *NodeFeatureDiscovery) WorkerProfileName(profile string) string {
+	if profile == "" {
+		return n.ComponentName("nfd-worker")
+	}
+	return n.ComponentName("nfd-worker-" + profile)
+}
+
+// GetWorkerProfiles returns the worker profiles with their unset settings
+// defaulted. When no profile is set, it returns a single unnamed profile
+// built from the top-level worker settings
+func (n *NodeFeatureDiscovery) GetWorkerProfiles() []WorkerProfile {
+	if len(n.Spec.WorkerProfiles) == 0 {
+		return []WorkerProfile{{
+			NodeSelector:      n.Spec.Operand.WorkerNodeSelector,
+			WorkerConfig:      &n.Spec.WorkerConfig,
+			Tolerations:       n.Spec.Operand.WorkerTolerations,
+			PriorityClassName: n.Spec.Operand.WorkerPriorityClassName,
+			Resources:         n.Spec.Operand.WorkerResources,
+		}}
+	}
+
+	profiles := make([]WorkerProfile, 0, len(n.Spec.WorkerProfiles))
+	for _, p := range n.Spec.WorkerProfiles {
+		profile := *p.DeepCopy()
+		if profile.WorkerConfig == nil {
+			profile.WorkerConfig = &n.Spec.WorkerConfig
+		}
+		if profile.Tolerations == nil {
+			profile.Tolerations = n.Spec.Operand.WorkerTolerations
+		}
+		if profile.PriorityClassName == "" {
+			profile.PriorityClassName = n.Spec.Operand.WorkerPriorityClassName
+		}
+		if profile.Resources == nil {
+			profile.Resources = n.Spec.Operand.WorkerResources
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// WorkerConfigMapRefs returns the user-owned ConfigMaps referenced by the
+// worker configurations, top-level and per profile
+func (n *NodeFeatureDiscovery) WorkerConfigMapRefs() []corev1.ConfigMapKeySelector {
+	refs := []corev1.ConfigMapKeySelector{}
+	if n.Spec.WorkerConfig.ConfigMapRef != nil {
+		refs = append(refs, *n.Spec.WorkerConfig.ConfigMapRef)
+	}
+	for _, profile := range n.Spec.WorkerProfiles {
+		if profile.WorkerConfig != nil && profile.WorkerConfig.ConfigMapRef != nil {
+			refs = append(refs, *profile.WorkerConfig.ConfigMapRef)
+		}
+	}
+	return refs
+}
+
 // Data returns a valid ConfigMap name
 func (c *ConfigMap) Data() string {
 	return c.ConfigData
@@ -21,14 +21,92 @@ limitations under the License.
 package v1
 
 import (
+	"github.com/openshift/cluster-nfd-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUIDConfig) DeepCopyInto(out *CPUIDConfig) {
+	*out = *in
+	if in.AttributeBlacklist != nil {
+		in, out := &in.AttributeBlacklist, &out.AttributeBlacklist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttributeWhitelist != nil {
+		in, out := &in.AttributeWhitelist, &out.AttributeWhitelist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUIDConfig.
+func (in *CPUIDConfig) DeepCopy() *CPUIDConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUIDConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUSourceConfig) DeepCopyInto(out *CPUSourceConfig) {
+	*out = *in
+	if in.CPUID != nil {
+		in, out := &in.CPUID, &out.CPUID
+		*out = new(CPUIDConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUSourceConfig.
+func (in *CPUSourceConfig) DeepCopy() *CPUSourceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUSourceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentStatus) DeepCopyInto(out *ComponentStatus) {
+	*out = *in
+	if in.PodFailures != nil {
+		in, out := &in.PodFailures, &out.PodFailures
+		*out = make([]PodFailure, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentStatus.
+func (in *ComponentStatus) DeepCopy() *ComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMap) DeepCopyInto(out *ConfigMap) {
 	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(WorkerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMap.
@@ -41,6 +119,51 @@ func (in *ConfigMap) DeepCopy() *ConfigMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeviceSourceConfig) DeepCopyInto(out *DeviceSourceConfig) {
+	*out = *in
+	if in.DeviceClassWhitelist != nil {
+		in, out := &in.DeviceClassWhitelist, &out.DeviceClassWhitelist
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeviceLabelFields != nil {
+		in, out := &in.DeviceLabelFields, &out.DeviceLabelFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeviceSourceConfig.
+func (in *DeviceSourceConfig) DeepCopy() *DeviceSourceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DeviceSourceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelSourceConfig) DeepCopyInto(out *KernelSourceConfig) {
+	*out = *in
+	if in.ConfigOpts != nil {
+		in, out := &in.ConfigOpts, &out.ConfigOpts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelSourceConfig.
+func (in *KernelSourceConfig) DeepCopy() *KernelSourceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelSourceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeFeatureDiscovery) DeepCopyInto(out *NodeFeatureDiscovery) {
 	*out = *in
@@ -104,6 +227,11 @@ func (in *NodeFeatureDiscoveryList) DeepCopyObject() runtime.Object {
 func (in *NodeFeatureDiscoverySpec) DeepCopyInto(out *NodeFeatureDiscoverySpec) {
 	*out = *in
 	in.Operand.DeepCopyInto(&out.Operand)
+	if in.TopologyUpdaterConfig != nil {
+		in, out := &in.TopologyUpdaterConfig, &out.TopologyUpdaterConfig
+		*out = new(TopologyUpdaterConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExtraLabelNs != nil {
 		in, out := &in.ExtraLabelNs, &out.ExtraLabelNs
 		*out = make([]string, len(*in))
@@ -114,7 +242,19 @@ func (in *NodeFeatureDiscoverySpec) DeepCopyInto(out *NodeFeatureDiscoverySpec)
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.WorkerConfig = in.WorkerConfig
+	in.WorkerConfig.DeepCopyInto(&out.WorkerConfig)
+	if in.WorkerProfiles != nil {
+		in, out := &in.WorkerProfiles, &out.WorkerProfiles
+		*out = make([]WorkerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RulePresets != nil {
+		in, out := &in.RulePresets, &out.RulePresets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFeatureDiscoverySpec.
@@ -137,6 +277,17 @@ func (in *NodeFeatureDiscoveryStatus) DeepCopyInto(out *NodeFeatureDiscoveryStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]ComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastReconcileTime != nil {
+		in, out := &in.LastReconcileTime, &out.LastReconcileTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeFeatureDiscoveryStatus.
@@ -149,9 +300,25 @@ func (in *NodeFeatureDiscoveryStatus) DeepCopy() *NodeFeatureDiscoveryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperandPorts) DeepCopyInto(out *OperandPorts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandPorts.
+func (in *OperandPorts) DeepCopy() *OperandPorts {
+	if in == nil {
+		return nil
+	}
+	out := new(OperandPorts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperandSpec) DeepCopyInto(out *OperandSpec) {
 	*out = *in
+	out.Ports = in.Ports
 	if in.WorkerTolerations != nil {
 		in, out := &in.WorkerTolerations, &out.WorkerTolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -159,6 +326,11 @@ func (in *OperandSpec) DeepCopyInto(out *OperandSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MasterReplicas != nil {
+		in, out := &in.MasterReplicas, &out.MasterReplicas
+		*out = new(int32)
+		**out = **in
+	}
 	if in.MasterTolerations != nil {
 		in, out := &in.MasterTolerations, &out.MasterTolerations
 		*out = make([]corev1.Toleration, len(*in))
@@ -201,6 +373,45 @@ func (in *OperandSpec) DeepCopyInto(out *OperandSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TopologyUpdaterNodeSelector != nil {
+		in, out := &in.TopologyUpdaterNodeSelector, &out.TopologyUpdaterNodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TopologyUpdaterTolerations != nil {
+		in, out := &in.TopologyUpdaterTolerations, &out.TopologyUpdaterTolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MasterResources != nil {
+		in, out := &in.MasterResources, &out.MasterResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerResources != nil {
+		in, out := &in.WorkerResources, &out.WorkerResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCResources != nil {
+		in, out := &in.GCResources, &out.GCResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TopologyUpdaterResources != nil {
+		in, out := &in.TopologyUpdaterResources, &out.TopologyUpdaterResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PruneResources != nil {
+		in, out := &in.PruneResources, &out.PruneResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperandSpec.
@@ -212,3 +423,197 @@ func (in *OperandSpec) DeepCopy() *OperandSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodFailure) DeepCopyInto(out *PodFailure) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodFailure.
+func (in *PodFailure) DeepCopy() *PodFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(PodFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyUpdaterConfig) DeepCopyInto(out *TopologyUpdaterConfig) {
+	*out = *in
+	if in.ExcludeList != nil {
+		in, out := &in.ExcludeList, &out.ExcludeList
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyUpdaterConfig.
+func (in *TopologyUpdaterConfig) DeepCopy() *TopologyUpdaterConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyUpdaterConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerConfig) DeepCopyInto(out *WorkerConfig) {
+	*out = *in
+	if in.Core != nil {
+		in, out := &in.Core, &out.Core
+		*out = new(WorkerCoreConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = new(WorkerSourcesConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerConfig.
+func (in *WorkerConfig) DeepCopy() *WorkerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerCoreConfig) DeepCopyInto(out *WorkerCoreConfig) {
+	*out = *in
+	if in.FeatureSources != nil {
+		in, out := &in.FeatureSources, &out.FeatureSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LabelSources != nil {
+		in, out := &in.LabelSources, &out.LabelSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Klog != nil {
+		in, out := &in.Klog, &out.Klog
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerCoreConfig.
+func (in *WorkerCoreConfig) DeepCopy() *WorkerCoreConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerCoreConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerProfile) DeepCopyInto(out *WorkerProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeAffinity != nil {
+		in, out := &in.NodeAffinity, &out.NodeAffinity
+		*out = new(corev1.NodeAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerConfig != nil {
+		in, out := &in.WorkerConfig, &out.WorkerConfig
+		*out = new(ConfigMap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerProfile.
+func (in *WorkerProfile) DeepCopy() *WorkerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkerSourcesConfig) DeepCopyInto(out *WorkerSourcesConfig) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(CPUSourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kernel != nil {
+		in, out := &in.Kernel, &out.Kernel
+		*out = new(KernelSourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PCI != nil {
+		in, out := &in.PCI, &out.PCI
+		*out = new(DeviceSourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.USB != nil {
+		in, out := &in.USB, &out.USB
+		*out = new(DeviceSourceConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = make([]v1alpha1.EmbeddedRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkerSourcesConfig.
+func (in *WorkerSourcesConfig) DeepCopy() *WorkerSourcesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkerSourcesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// The types below mirror Rule and its matchers for the rules embedded in
+// other objects, the custom rules of the worker configuration and the rules
+// of the NodeFeatureRuleTemplates. Unlike the rules of the NodeFeatureRules
+// and NodeFeatureGroups, which are carried forward to nfd-master as is, their
+// unknown fields are pruned.
+
+// EmbeddedRule defines a rule for node customization such as labeling.
+type EmbeddedRule struct {
+	// Name of the rule.
+	Name string `json:"name"`
+
+	// Labels to create if the rule matches.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// LabelsTemplate specifies a template to expand for dynamically generating
+	// multiple labels. Data (after template expansion) must be keys with an
+	// optional value (<key>[=<value>]) separated by newlines.
+	// +optional
+	LabelsTemplate string `json:"labelsTemplate,omitempty"`
+
+	// Annotations to create if the rule matches.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Vars is the variables to store if the rule matches. Variables do not
+	// directly inflict any changes in the node object. However, they can be
+	// referenced from other rules enabling more complex rule hierarchies,
+	// without exposing intermediary output values as labels.
+	// +optional
+	Vars map[string]string `json:"vars,omitempty"`
+
+	// VarsTemplate specifies a template to expand for dynamically generating
+	// multiple variables. Data (after template expansion) must be keys with an
+	// optional value (<key>[=<value>]) separated by newlines.
+	// +optional
+	VarsTemplate string `json:"varsTemplate,omitempty"`
+
+	// Taints to create if the rule matches.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// ExtendedResources to create if the rule matches.
+	// +optional
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+
+	// MatchFeatures specifies a set of matcher terms all of which must match.
+	// +optional
+	MatchFeatures EmbeddedFeatureMatcher `json:"matchFeatures,omitempty"`
+
+	// MatchAny specifies a list of matchers one of which must match.
+	// +optional
+	MatchAny []EmbeddedMatchAnyElem `json:"matchAny,omitempty"`
+}
+
+// EmbeddedMatchAnyElem specifies one sub-matcher of MatchAny.
+type EmbeddedMatchAnyElem struct {
+	// MatchFeatures specifies a set of matcher terms all of which must match.
+	MatchFeatures EmbeddedFeatureMatcher `json:"matchFeatures"`
+}
+
+// EmbeddedFeatureMatcher specifies a set of feature matcher terms (i.e.
+// per-feature matchers), all of which must match.
+type EmbeddedFeatureMatcher []EmbeddedFeatureMatcherTerm
+
+// EmbeddedFeatureMatcherTerm defines requirements against one feature set.
+// All requirements (specified as MatchExpressions) are evaluated against each
+// element in the feature set.
+type EmbeddedFeatureMatcherTerm struct {
+	// Feature is the name of the feature set to match against.
+	Feature string `json:"feature"`
+	// MatchExpressions is the set of per-element expressions evaluated. These
+	// match against the value of the specified elements.
+	// +optional
+	MatchExpressions *EmbeddedMatchExpressionSet `json:"matchExpressions,omitempty"`
+	// MatchName in an expression that is matched against the name of each
+	// element in the feature set.
+	// +optional
+	MatchName *EmbeddedMatchExpression `json:"matchName,omitempty"`
+}
+
+// EmbeddedMatchExpressionSet contains a set of MatchExpressions, each of
+// which is evaluated against a set of input values.
+type EmbeddedMatchExpressionSet map[string]*EmbeddedMatchExpression
+
+// EmbeddedMatchExpression specifies an expression to evaluate against a set
+// of input values. It contains an operator that is applied when matching the
+// input and an array of values that the operator evaluates the input against.
+type EmbeddedMatchExpression struct {
+	// Op is the operator to be applied.
+	Op MatchOp `json:"op"`
+
+	// Value is the list of values that the operand evaluates the input
+	// against. Value should be empty if the operator is Exists, DoesNotExist,
+	// IsTrue or IsFalse. Value should contain exactly one element if the
+	// operator is Gt, Ge, Lt or Le and exactly two elements if the operator
+	// is GtLt or GeLe.
+	// In other cases Value should contain at least one element.
+	// +optional
+	Value MatchValue `json:"value,omitempty"`
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021. The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeFeatureFileSpec defines the desired state of NodeFeatureFile
+type NodeFeatureFileSpec struct {
+	// NodeSelector selects the nodes the feature files are installed on. An
+	// empty or missing selector selects all the nodes running nfd-worker.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Files are the feature files to install in the features.d directory of
+	// nfd-worker, keyed by file name. The content uses the format of the
+	// nfd-worker feature files.
+	// +kubebuilder:validation:MinProperties=1
+	// +kubebuilder:validation:XValidation:rule="self.all(f, f.matches('^[-._a-zA-Z0-9]+$') && !f.startsWith('.'))",message="file names must consist of alphanumeric characters, '-', '_' or '.' and must not start with '.'"
+	Files map[string]string `json:"files"`
+}
+
+// NodeFeatureFileStatus defines the observed state of NodeFeatureFile
+type NodeFeatureFileStatus struct {
+	// Nodes are the names of the nodes the feature files are installed on.
+	// +optional
+	// +listType=set
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// NodeFeatureFile resource holds static feature files that the operator
+// installs in the features.d directory of the nfd-worker pods running on the
+// selected nodes. NodeFeatureFiles are read from the namespace of the
+// NodeFeatureDiscovery instance.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nff,scope=Namespaced
+type NodeFeatureFile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeFeatureFileSpec   `json:"spec"`
+	Status NodeFeatureFileStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeFeatureFileList contains a list of NodeFeatureFile objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeFeatureFileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeFeatureFile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeFeatureFile{}, &NodeFeatureFileList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021. The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeFeatureGroupSpec defines the desired state of NodeFeatureGroup
+// +kubebuilder:pruning:PreserveUnknownFields
+type NodeFeatureGroupSpec struct {
+	// List of rules to evaluate to determine nodes that belong in this group.
+	Rules []GroupRule `json:"featureGroupRules"`
+}
+
+// NodeFeatureGroupStatus defines the observed state of NodeFeatureGroup
+type NodeFeatureGroupStatus struct {
+	// Nodes is a list of FeatureGroupNode in the cluster that match the featureGroupRules
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=name
+	Nodes []FeatureGroupNode `json:"nodes"`
+}
+
+// FeatureGroupNode is a node that matches the rules of a NodeFeatureGroup.
+type FeatureGroupNode struct {
+	// Name of the node.
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// NodeFeatureGroup resource holds Node pools by featureGroup
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient
+// +kubebuilder:resource:shortName=nfg,scope=Namespaced
+type NodeFeatureGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the rules to be evaluated.
+	Spec NodeFeatureGroupSpec `json:"spec"`
+	// Status of the NodeFeatureGroup after the most recent evaluation of the
+	// specification.
+	Status NodeFeatureGroupStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeFeatureGroupList contains a list of NodeFeatureGroup objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeFeatureGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeFeatureGroup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeFeatureGroup{}, &NodeFeatureGroupList{})
+}
+
+// GroupRule defines a rule for nodegroup filtering.
+// +kubebuilder:pruning:PreserveUnknownFields
+type GroupRule struct {
+	// Name of the rule.
+	Name string `json:"name"`
+
+	// Vars is the variables to store if the rule matches. Variables can be
+	// referenced from other rules enabling more complex rule hierarchies.
+	// +optional
+	Vars map[string]string `json:"vars,omitempty"`
+
+	// VarsTemplate specifies a template to expand for dynamically generating
+	// multiple variables. Data (after template expansion) must be keys with an
+	// optional value (<key>[=<value>]) separated by newlines.
+	// +optional
+	VarsTemplate string `json:"varsTemplate,omitempty"`
+
+	// MatchFeatures specifies a set of matcher terms all of which must match.
+	// +optional
+	MatchFeatures FeatureMatcher `json:"matchFeatures"`
+
+	// MatchAny specifies a list of matchers one of which must match.
+	// +optional
+	MatchAny []MatchAnyElem `json:"matchAny"`
+}
@@ -25,15 +25,59 @@ import (
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // NodeFeatureRuleSpec defines the desired state of NodeFeatureRule
+// +kubebuilder:pruning:PreserveUnknownFields
 type NodeFeatureRuleSpec struct {
 	// Rules is a list of node customization rules.
 	Rules []Rule `json:"rules"`
 }
 
-// NodeFeatureRuleStatus defines the observed state of NodeFeatureRule
+// NodeFeatureRuleStatus defines the observed state of NodeFeatureRule. It is
+// reported on the NodeFeatureRules of nfd.openshift.io group and on their
+// mirrors in nfd.k8s-sigs.io group, standalone NodeFeatureRules of
+// nfd.k8s-sigs.io group have no status.
 type NodeFeatureRuleStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// MatchedNodeCount is the number of nodes matched by at least one of the
+	// rules.
+	// +optional
+	MatchedNodeCount int32 `json:"matchedNodeCount"`
+
+	// Rules reports the nodes matched by each rule and what the rule
+	// produces on them.
+	// +optional
+	Rules []RuleStatus `json:"rules,omitempty"`
+
+	// Conditions represents the latest available observations of the
+	// evaluation of the rules, a Degraded condition reports the rules that
+	// fail to evaluate.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// RuleStatus is the result of the evaluation of one rule on the nodes of the
+// cluster.
+type RuleStatus struct {
+	// Name is the name of the rule.
+	Name string `json:"name"`
+
+	// MatchedNodeCount is the number of nodes matched by the rule.
+	MatchedNodeCount int32 `json:"matchedNodeCount"`
+
+	// MatchedNodes are the names of the first nodes matched by the rule, in
+	// alphabetical order. The list is truncated to 10 nodes.
+	// +optional
+	MatchedNodes []string `json:"matchedNodes,omitempty"`
+
+	// Labels are the labels produced by the rule. Templates and dynamic
+	// values may produce different values on each node, the value reported
+	// is the one of the first matching node.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are the taints produced by the rule.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -70,36 +114,37 @@ func init() {
 }
 
 // Rule defines a rule for node customization such as labeling.
+// +kubebuilder:pruning:PreserveUnknownFields
 type Rule struct {
 	// Name of the rule.
 	Name string `json:"name"`
 
 	// Labels to create if the rule matches.
 	// +optional
-	Labels map[string]string `json:"labels"`
+	Labels map[string]string `json:"labels,omitempty"`
 
 	// LabelsTemplate specifies a template to expand for dynamically generating
 	// multiple labels. Data (after template expansion) must be keys with an
 	// optional value (<key>[=<value>]) separated by newlines.
 	// +optional
-	LabelsTemplate string `json:"labelsTemplate"`
+	LabelsTemplate string `json:"labelsTemplate,omitempty"`
 
 	// Annotations to create if the rule matches.
 	// +optional
-	Annotations map[string]string `json:"annotations"`
+	Annotations map[string]string `json:"annotations,omitempty"`
 
 	// Vars is the variables to store if the rule matches. Variables do not
 	// directly inflict any changes in the node object. However, they can be
 	// referenced from other rules enabling more complex rule hierarchies,
 	// without exposing intermediary output values as labels.
 	// +optional
-	Vars map[string]string `json:"vars"`
+	Vars map[string]string `json:"vars,omitempty"`
 
 	// VarsTemplate specifies a template to expand for dynamically generating
 	// multiple variables. Data (after template expansion) must be keys with an
 	// optional value (<key>[=<value>]) separated by newlines.
 	// +optional
-	VarsTemplate string `json:"varsTemplate"`
+	VarsTemplate string `json:"varsTemplate,omitempty"`
 
 	// Taints to create if the rule matches.
 	// +optional
@@ -107,18 +152,19 @@ type Rule struct {
 
 	// ExtendedResources to create if the rule matches.
 	// +optional
-	ExtendedResources map[string]string `json:"extendedResources"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
 
 	// MatchFeatures specifies a set of matcher terms all of which must match.
 	// +optional
-	MatchFeatures FeatureMatcher `json:"matchFeatures"`
+	MatchFeatures FeatureMatcher `json:"matchFeatures,omitempty"`
 
 	// MatchAny specifies a list of matchers one of which must match.
 	// +optional
-	MatchAny []MatchAnyElem `json:"matchAny"`
+	MatchAny []MatchAnyElem `json:"matchAny,omitempty"`
 }
 
 // MatchAnyElem specifies one sub-matcher of MatchAny.
+// +kubebuilder:pruning:PreserveUnknownFields
 type MatchAnyElem struct {
 	// MatchFeatures specifies a set of matcher terms all of which must match.
 	MatchFeatures FeatureMatcher `json:"matchFeatures"`
@@ -131,17 +177,18 @@ type FeatureMatcher []FeatureMatcherTerm
 // FeatureMatcherTerm defines requirements against one feature set. All
 // requirements (specified as MatchExpressions) are evaluated against each
 // element in the feature set.
+// +kubebuilder:pruning:PreserveUnknownFields
 type FeatureMatcherTerm struct {
 	// Feature is the name of the feature set to match against.
 	Feature string `json:"feature"`
 	// MatchExpressions is the set of per-element expressions evaluated. These
 	// match against the value of the specified elements.
 	// +optional
-	MatchExpressions *MatchExpressionSet `json:"matchExpressions"`
+	MatchExpressions *MatchExpressionSet `json:"matchExpressions,omitempty"`
 	// MatchName in an expression that is matched against the name of each
 	// element in the feature set.
 	// +optional
-	MatchName *MatchExpression `json:"matchName"`
+	MatchName *MatchExpression `json:"matchName,omitempty"`
 }
 
 // MatchExpressionSet contains a set of MatchExpressions, each of which is
@@ -151,6 +198,7 @@ type MatchExpressionSet map[string]*MatchExpression
 // MatchExpression specifies an expression to evaluate against a set of input
 // values. It contains an operator that is applied when matching the input and
 // an array of values that the operator evaluates the input against.
+// +kubebuilder:pruning:PreserveUnknownFields
 type MatchExpression struct {
 	// Op is the operator to be applied.
 	Op MatchOp `json:"op"`
@@ -158,7 +206,8 @@ type MatchExpression struct {
 	// Value is the list of values that the operand evaluates the input
 	// against. Value should be empty if the operator is Exists, DoesNotExist,
 	// IsTrue or IsFalse. Value should contain exactly one element if the
-	// operator is Gt or Lt and exactly two elements if the operator is GtLt.
+	// operator is Gt, Ge, Lt or Le and exactly two elements if the operator
+	// is GtLt or GeLe.
 	// In other cases Value should contain at least one element.
 	// +optional
 	Value MatchValue `json:"value,omitempty"`
@@ -166,7 +215,7 @@ type MatchExpression struct {
 
 // MatchOp is the match operator that is applied on values when evaluating a
 // MatchExpression.
-// +kubebuilder:validation:Enum="In";"NotIn";"InRegexp";"Exists";"DoesNotExist";"Gt";"Lt";"GtLt";"IsTrue";"IsFalse"
+// +kubebuilder:validation:Enum="In";"NotIn";"InRegexp";"Exists";"DoesNotExist";"Gt";"Ge";"Lt";"Le";"GtLt";"GeLe";"IsTrue";"IsFalse"
 type MatchOp string
 
 // MatchValue is the list of values associated with a MatchExpression.
@@ -195,16 +244,32 @@ const (
 	// Both the input and value must be integer numbers, otherwise an error is
 	// returned.
 	MatchGt MatchOp = "Gt"
+	// MatchGe returns true if the input is greater than or equal to the value
+	// of the expression (number of values in the expression must be exactly
+	// one). Both the input and value must be integer numbers, otherwise an
+	// error is returned.
+	MatchGe MatchOp = "Ge"
 	// MatchLt returns true if the input is less  than the value of the
 	// expression (number of values in the expression must be exactly one).
 	// Both the input and value must be integer numbers, otherwise an error is
 	// returned.
 	MatchLt MatchOp = "Lt"
+	// MatchLe returns true if the input is less than or equal to the value of
+	// the expression (number of values in the expression must be exactly
+	// one). Both the input and value must be integer numbers, otherwise an
+	// error is returned.
+	MatchLe MatchOp = "Le"
 	// MatchGtLt returns true if the input is between two values, i.e. greater
 	// than the first value and less than the second value of the expression
 	// (number of values in the expression must be exactly two). Both the input
 	// and values must be integer numbers, otherwise an error is returned.
 	MatchGtLt MatchOp = "GtLt"
+	// MatchGeLe returns true if the input is between two values, both
+	// included, i.e. greater than or equal to the first value and less than
+	// or equal to the second value of the expression (number of values in the
+	// expression must be exactly two). Both the input and values must be
+	// integer numbers, otherwise an error is returned.
+	MatchGeLe MatchOp = "GeLe"
 	// MatchIsTrue returns true if the input holds the value "true". The
 	// expression must not have any values.
 	MatchIsTrue MatchOp = "IsTrue"
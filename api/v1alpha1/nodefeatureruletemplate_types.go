@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeFeatureRuleTemplateSpec defines the parameters and the rules of a
+// NodeFeatureRuleTemplate
+type NodeFeatureRuleTemplateSpec struct {
+	// Parameters declares the parameters the instances of the template
+	// provide. A parameter is referenced as $(name) in the strings of the
+	// rules, map keys included.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+
+	// Rules are the node customization rules rendered for each instance of
+	// the template.
+	// +kubebuilder:validation:MinItems=1
+	Rules []EmbeddedRule `json:"rules"`
+}
+
+// ParameterType is the type of the value of a template parameter
+// +kubebuilder:validation:Enum=string;integer;boolean
+type ParameterType string
+
+const (
+	// ParameterTypeString accepts any value
+	ParameterTypeString ParameterType = "string"
+	// ParameterTypeInteger accepts integer numbers
+	ParameterTypeInteger ParameterType = "integer"
+	// ParameterTypeBoolean accepts "true" and "false"
+	ParameterTypeBoolean ParameterType = "boolean"
+)
+
+// TemplateParameter declares a parameter of a NodeFeatureRuleTemplate and the
+// values it accepts.
+type TemplateParameter struct {
+	// Name of the parameter.
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Description of the parameter.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type of the value of the parameter.
+	// +kubebuilder:default=string
+	// +optional
+	Type ParameterType `json:"type,omitempty"`
+
+	// Required parameters must be set by every instance of the template.
+	// +optional
+	Required bool `json:"required,omitempty"`
+
+	// Default is the value of the parameter when an instance does not set
+	// it.
+	// +optional
+	Default *string `json:"default,omitempty"`
+
+	// Pattern is a regular expression the whole value must match.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+
+	// Enum lists the values accepted for the parameter.
+	// +optional
+	Enum []string `json:"enum,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeFeatureRuleTemplate resource is a reusable set of node customization
+// rules with parameters. Each NodeFeatureRuleInstance referencing the template
+// is rendered into a NodeFeatureRule with the values of its parameters.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nfrt,scope=Cluster
+type NodeFeatureRuleTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NodeFeatureRuleTemplateSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeFeatureRuleTemplateList contains a list of NodeFeatureRuleTemplate objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeFeatureRuleTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeFeatureRuleTemplate `json:"items"`
+}
+
+// NodeFeatureRuleInstanceSpec defines the template and the parameter values
+// of a NodeFeatureRuleInstance
+type NodeFeatureRuleInstanceSpec struct {
+	// TemplateName is the name of the NodeFeatureRuleTemplate to render.
+	// +kubebuilder:validation:MinLength=1
+	TemplateName string `json:"templateName"`
+
+	// Parameters are the values of the parameters of the template, keyed by
+	// parameter name.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// NodeFeatureRuleInstanceStatus defines the observed state of
+// NodeFeatureRuleInstance
+type NodeFeatureRuleInstanceStatus struct {
+	// TemplateGeneration is the generation of the template the
+	// NodeFeatureRule was last rendered from.
+	// +optional
+	TemplateGeneration int64 `json:"templateGeneration,omitempty"`
+
+	// Conditions represents the latest available observations of the
+	// rendering, a Rendered condition reports whether the NodeFeatureRule
+	// is up to date with the template and the parameters.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// NodeFeatureRuleInstance resource instantiates a NodeFeatureRuleTemplate
+// with parameter values. The operator renders it into the nfd.k8s-sigs.io
+// NodeFeatureRule of the same name and namespace.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:shortName=nfri,scope=Namespaced
+// +kubebuilder:printcolumn:name="Template",type=string,JSONPath=`.spec.templateName`
+// +kubebuilder:printcolumn:name="Rendered",type=string,JSONPath=`.status.conditions[?(@.type=="Rendered")].status`
+type NodeFeatureRuleInstance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeFeatureRuleInstanceSpec   `json:"spec"`
+	Status NodeFeatureRuleInstanceStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// NodeFeatureRuleInstanceList contains a list of NodeFeatureRuleInstance objects.
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type NodeFeatureRuleInstanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeFeatureRuleInstance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeFeatureRuleTemplate{}, &NodeFeatureRuleTemplateList{},
+		&NodeFeatureRuleInstance{}, &NodeFeatureRuleInstanceList{})
+}
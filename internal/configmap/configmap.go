@@ -19,11 +19,13 @@ package configmap
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -32,8 +34,10 @@ import (
 //go:generate mockgen -source=configmap.go -package=configmap -destination=mock_configmap.go ConfigMapAPI
 
 type ConfigMapAPI interface {
-	SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, workerCM *corev1.ConfigMap) error
+	SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, profile *nfdv1.WorkerProfile, workerCM *corev1.ConfigMap) error
+	SetTopologyUpdaterConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, topologyCM *corev1.ConfigMap) error
 	DeleteConfigMap(ctx context.Context, namespace, name string) error
+	GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error)
 }
 
 type configMap struct {
@@ -48,9 +52,50 @@ func NewConfigMapAPI(client client.Client, scheme *runtime.Scheme) ConfigMapAPI
 	}
 }
 
-func (c *configMap) SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, cm *corev1.ConfigMap) error {
+func (c *configMap) SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, profile *nfdv1.WorkerProfile, cm *corev1.ConfigMap) error {
+	workerConf, err := getWorkerConfigData(profile.WorkerConfig)
+	if err != nil {
+		return err
+	}
+
+	cm.Data = map[string]string{"nfd-worker-conf": workerConf}
+
+	return controllerutil.SetControllerReference(nfdInstance, cm, c.scheme)
+}
+
+// getWorkerConfigData returns the content of the nfd-worker configuration file,
+// either the raw ConfigData or the one rendered from the typed Config
+func getWorkerConfigData(workerConfig *nfdv1.ConfigMap) (string, error) {
+	if workerConfig.Config == nil {
+		return workerConfig.ConfigData, nil
+	}
+	if strings.TrimSpace(workerConfig.ConfigData) != "" {
+		return "", fmt.Errorf("workerConfig.configData and workerConfig.config are mutually exclusive")
+	}
+
+	data, err := yaml.Marshal(workerConfig.Config)
+	if err != nil {
+		return "", fmt.Errorf("failed to render the typed worker config: %w", err)
+	}
+	return string(data), nil
+}
 
-	cm.Data = map[string]string{"nfd-worker-conf": nfdInstance.Spec.WorkerConfig.ConfigData}
+// topologyUpdaterConf is the nfd-topology-updater configuration file
+type topologyUpdaterConf struct {
+	ExcludeList map[string][]string `json:"excludeList,omitempty"`
+}
+
+func (c *configMap) SetTopologyUpdaterConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, cm *corev1.ConfigMap) error {
+	conf := topologyUpdaterConf{}
+	if nfdInstance.Spec.TopologyUpdaterConfig != nil {
+		conf.ExcludeList = nfdInstance.Spec.TopologyUpdaterConfig.ExcludeList
+	}
+	data, err := yaml.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("failed to render the topology updater config: %w", err)
+	}
+
+	cm.Data = map[string]string{"nfd-topology-updater.conf": string(data)}
 
 	return controllerutil.SetControllerReference(nfdInstance, cm, c.scheme)
 }
@@ -68,3 +113,9 @@ func (c *configMap) DeleteConfigMap(ctx context.Context, namespace, name string)
 	}
 	return nil
 }
+
+func (c *configMap) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, cm)
+	return cm, err
+}
@@ -71,7 +71,7 @@ var _ = Describe("SetWorkerDaemonsetAsDesired", func() {
 			},
 		}
 
-		err = configmapAPI.SetWorkerConfigMapAsDesired(ctx, &actualNfdCR, &actualWorkerCM)
+		err = configmapAPI.SetWorkerConfigMapAsDesired(ctx, &actualNfdCR, &actualNfdCR.GetWorkerProfiles()[0], &actualWorkerCM)
 		Expect(err).To(BeNil())
 		expectedYAMLFile, err := os.ReadFile("testdata/test_worker_configmap.yaml")
 		Expect(err).To(BeNil())
@@ -82,6 +82,129 @@ var _ = Describe("SetWorkerDaemonsetAsDesired", func() {
 		Expect(err).To(BeNil())
 		Expect(&expectedWorkerCM).To(BeComparableTo(&actualWorkerCM))
 	})
+
+	It("typed worker config is rendered into the worker config", func() {
+		discoveryYAML, err := os.ReadFile("testdata/nfd_typed_worker_config.yaml")
+		Expect(err).To(BeNil())
+		actualNfdCR := nfdv1.NodeFeatureDiscovery{}
+		err = yaml.Unmarshal(discoveryYAML, &actualNfdCR)
+		Expect(err).To(BeNil())
+		actualWorkerCM := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfd-worker",
+				Namespace: "test-namespace",
+			},
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: "v1",
+			},
+		}
+
+		err = configmapAPI.SetWorkerConfigMapAsDesired(ctx, &actualNfdCR, &actualNfdCR.GetWorkerProfiles()[0], &actualWorkerCM)
+		Expect(err).To(BeNil())
+		expectedYAMLFile, err := os.ReadFile("testdata/test_typed_worker_configmap.yaml")
+		Expect(err).To(BeNil())
+		expectedWorkerCM := corev1.ConfigMap{}
+		err = yaml.Unmarshal(expectedYAMLFile, &expectedWorkerCM)
+		Expect(err).To(BeNil())
+		Expect(&expectedWorkerCM).To(BeComparableTo(&actualWorkerCM))
+	})
+
+	It("raw and typed worker config are mutually exclusive", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigData: "core:\n  sleepInterval: 60s\n",
+					Config:     &nfdv1.WorkerConfig{},
+				},
+			},
+		}
+
+		err := configmapAPI.SetWorkerConfigMapAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &corev1.ConfigMap{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("blank raw worker config does not conflict with the typed worker config", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigData: "  \n",
+					Config:     &nfdv1.WorkerConfig{},
+				},
+			},
+		}
+
+		workerCM := corev1.ConfigMap{}
+		err := configmapAPI.SetWorkerConfigMapAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &workerCM)
+		Expect(err).To(BeNil())
+		Expect(workerCM.Data).To(HaveKeyWithValue("nfd-worker-conf", "{}\n"))
+	})
+
+	It("worker profile config replaces the top-level worker config", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 60s\n"},
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "gpu", WorkerConfig: &nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 10s\n"}},
+					{Name: "compute"},
+				},
+			},
+		}
+		profiles := nfdCR.GetWorkerProfiles()
+
+		gpuCM := corev1.ConfigMap{}
+		err := configmapAPI.SetWorkerConfigMapAsDesired(ctx, &nfdCR, &profiles[0], &gpuCM)
+		Expect(err).To(BeNil())
+		Expect(gpuCM.Data).To(HaveKeyWithValue("nfd-worker-conf", "core:\n  sleepInterval: 10s\n"))
+
+		computeCM := corev1.ConfigMap{}
+		err = configmapAPI.SetWorkerConfigMapAsDesired(ctx, &nfdCR, &profiles[1], &computeCM)
+		Expect(err).To(BeNil())
+		Expect(computeCM.Data).To(HaveKeyWithValue("nfd-worker-conf", "core:\n  sleepInterval: 60s\n"))
+	})
+})
+
+var _ = Describe("SetTopologyUpdaterConfigMapAsDesired", func() {
+	var (
+		configmapAPI ConfigMapAPI
+	)
+
+	BeforeEach(func() {
+		configmapAPI = NewConfigMapAPI(nil, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("exclude list is rendered into the topology updater config", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				TopologyUpdaterConfig: &nfdv1.TopologyUpdaterConfig{
+					ExcludeList: map[string][]string{
+						"*":      {"hugepages-1Gi"},
+						"node-1": {"memory", "example.com/device"},
+					},
+				},
+			},
+		}
+		topologyCM := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "nfd-topology-updater", Namespace: "test-namespace"}}
+
+		err := configmapAPI.SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, &topologyCM)
+		Expect(err).To(BeNil())
+		Expect(topologyCM.Data).To(Equal(map[string]string{
+			"nfd-topology-updater.conf": "excludeList:\n  '*':\n  - hugepages-1Gi\n  node-1:\n  - memory\n  - example.com/device\n",
+		}))
+		Expect(topologyCM.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("empty config without topology updater config", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		topologyCM := corev1.ConfigMap{}
+
+		err := configmapAPI.SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, &topologyCM)
+		Expect(err).To(BeNil())
+		Expect(topologyCM.Data).To(Equal(map[string]string{"nfd-topology-updater.conf": "{}\n"}))
+	})
 })
 
 var _ = Describe("DeleteConfigMap", func() {
@@ -56,16 +56,45 @@ func (mr *MockConfigMapAPIMockRecorder) DeleteConfigMap(ctx, namespace, name any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigMap", reflect.TypeOf((*MockConfigMapAPI)(nil).DeleteConfigMap), ctx, namespace, name)
 }
 
+// GetConfigMap mocks base method.
+func (m *MockConfigMapAPI) GetConfigMap(ctx context.Context, namespace, name string) (*v10.ConfigMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigMap", ctx, namespace, name)
+	ret0, _ := ret[0].(*v10.ConfigMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigMap indicates an expected call of GetConfigMap.
+func (mr *MockConfigMapAPIMockRecorder) GetConfigMap(ctx, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigMap", reflect.TypeOf((*MockConfigMapAPI)(nil).GetConfigMap), ctx, namespace, name)
+}
+
+// SetTopologyUpdaterConfigMapAsDesired mocks base method.
+func (m *MockConfigMapAPI) SetTopologyUpdaterConfigMapAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, topologyCM *v10.ConfigMap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTopologyUpdaterConfigMapAsDesired", ctx, nfdInstance, topologyCM)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTopologyUpdaterConfigMapAsDesired indicates an expected call of SetTopologyUpdaterConfigMapAsDesired.
+func (mr *MockConfigMapAPIMockRecorder) SetTopologyUpdaterConfigMapAsDesired(ctx, nfdInstance, topologyCM any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTopologyUpdaterConfigMapAsDesired", reflect.TypeOf((*MockConfigMapAPI)(nil).SetTopologyUpdaterConfigMapAsDesired), ctx, nfdInstance, topologyCM)
+}
+
 // SetWorkerConfigMapAsDesired mocks base method.
-func (m *MockConfigMapAPI) SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, workerCM *v10.ConfigMap) error {
+func (m *MockConfigMapAPI) SetWorkerConfigMapAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, profile *v1.WorkerProfile, workerCM *v10.ConfigMap) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetWorkerConfigMapAsDesired", ctx, nfdInstance, workerCM)
+	ret := m.ctrl.Call(m, "SetWorkerConfigMapAsDesired", ctx, nfdInstance, profile, workerCM)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetWorkerConfigMapAsDesired indicates an expected call of SetWorkerConfigMapAsDesired.
-func (mr *MockConfigMapAPIMockRecorder) SetWorkerConfigMapAsDesired(ctx, nfdInstance, workerCM any) *gomock.Call {
+func (mr *MockConfigMapAPIMockRecorder) SetWorkerConfigMapAsDesired(ctx, nfdInstance, profile, workerCM any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkerConfigMapAsDesired", reflect.TypeOf((*MockConfigMapAPI)(nil).SetWorkerConfigMapAsDesired), ctx, nfdInstance, workerCM)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkerConfigMapAsDesired", reflect.TypeOf((*MockConfigMapAPI)(nil).SetWorkerConfigMapAsDesired), ctx, nfdInstance, profile, workerCM)
 }
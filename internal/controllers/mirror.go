@@ -0,0 +1,168 @@
+/*
+Copyright 2021. The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+// MirrorAdoptionPolicy tells what to do when the nfd.k8s-sigs.io object
+// mirroring a nfd.openshift.io NodeFeatureRule or NodeFeatureGroup already
+// exists but was not created by the operator
+type MirrorAdoptionPolicy string
+
+const (
+	// MirrorAdoptionPolicyRefuse leaves the existing object untouched and
+	// reports the conflict with an event
+	MirrorAdoptionPolicyRefuse MirrorAdoptionPolicy = "Refuse"
+	// MirrorAdoptionPolicyAdopt takes the ownership of the existing object
+	// and overwrites its spec
+	MirrorAdoptionPolicyAdopt MirrorAdoptionPolicy = "Adopt"
+
+	// sourceGenerationAnnotation records the generation of the source
+	// object that the mirror was last updated from, to tell the changes of
+	// the source from the edits of the mirror
+	sourceGenerationAnnotation = "nfd.openshift.io/source-generation"
+)
+
+// errMirrorConflict is returned when the mirror exists and cannot be adopted
+var errMirrorConflict = errors.New("mirror in nfd.k8s-sigs.io group is not owned by the operator")
+
+// mirrorResult tells how createOrPatchMirror changed the mirror
+type mirrorResult struct {
+	operation controllerutil.OperationResult
+	adopted   bool
+	drifted   bool
+}
+
+// createOrPatchMirror creates or patches the object of nfd.k8s-sigs.io group
+// of the same kind and name as the source, controlled by the source.
+//
+// The spec is copied as stored by the API server rather than through the Go
+// types of the operator, so that the fields added by newer NFD versions, which
+// the types of the operator may not know of yet, are carried forward to the
+// mirror instead of being dropped
+func createOrPatchMirror(ctx context.Context, clnt client.Client, scheme *runtime.Scheme, source client.Object,
+	adoptionPolicy MirrorAdoptionPolicy) (*unstructured.Unstructured, mirrorResult, error) {
+	gvk, err := apiutil.GVKForObject(source, scheme)
+	if err != nil {
+		return nil, mirrorResult{}, err
+	}
+
+	raw := &unstructured.Unstructured{}
+	raw.SetGroupVersionKind(gvk)
+	if err := clnt.Get(ctx, client.ObjectKeyFromObject(source), raw); err != nil {
+		return nil, mirrorResult{}, fmt.Errorf("failed to get %s %s/%s: %w", gvk.Kind, source.GetNamespace(), source.GetName(), err)
+	}
+	spec, _, err := unstructured.NestedMap(raw.Object, "spec")
+	if err != nil {
+		return nil, mirrorResult{}, fmt.Errorf("invalid spec of %s %s/%s: %w", gvk.Kind, source.GetNamespace(), source.GetName(), err)
+	}
+
+	target := &unstructured.Unstructured{}
+	target.SetGroupVersionKind(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(gvk.Kind))
+	target.SetNamespace(source.GetNamespace())
+	target.SetName(source.GetName())
+
+	// CreateOrPatch removes the status from unstructured objects, the status
+	// of the mirror is kept aside for the caller
+	var status interface{}
+	var result mirrorResult
+	result.operation, err = controllerutil.CreateOrPatch(ctx, clnt, target, func() error {
+		status = target.Object["status"]
+		if target.GetResourceVersion() != "" && !metav1.IsControlledBy(target, source) {
+			if adoptionPolicy != MirrorAdoptionPolicyAdopt || metav1.GetControllerOf(target) != nil {
+				return errMirrorConflict
+			}
+			result.adopted = true
+		}
+		if err := controllerutil.SetControllerReference(source, target, scheme); err != nil {
+			return err
+		}
+
+		// the spec of the mirror changed while the source did not
+		generation := strconv.FormatInt(raw.GetGeneration(), 10)
+		current, _, _ := unstructured.NestedMap(target.Object, "spec")
+		result.drifted = !result.adopted && target.GetResourceVersion() != "" &&
+			target.GetAnnotations()[sourceGenerationAnnotation] == generation && !equality.Semantic.DeepEqual(current, spec)
+
+		annotations := target.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[sourceGenerationAnnotation] = generation
+		target.SetAnnotations(annotations)
+
+		return unstructured.SetNestedMap(target.Object, spec, "spec")
+	})
+	if err != nil {
+		return nil, result, err
+	}
+	if status != nil {
+		target.Object["status"] = status
+	}
+	return target, result, nil
+}
+
+// recordMirrorResult logs and records an event on the source for the creation,
+// the adoption or the correction of its mirror
+func recordMirrorResult(ctx context.Context, recorder record.EventRecorder, source client.Object, kind string, result mirrorResult) {
+	logger := log.FromContext(ctx)
+
+	switch {
+	case result.operation == controllerutil.OperationResultCreated:
+		logger.Info(fmt.Sprintf("Successfully created %s in nfd.k8s-sigs.io group", kind))
+		recorder.Eventf(source, corev1.EventTypeNormal, "MirrorCreated", "Created %s in nfd.k8s-sigs.io group", kind)
+	case result.adopted:
+		logger.Info(fmt.Sprintf("Adopted %s in nfd.k8s-sigs.io group", kind))
+		recorder.Eventf(source, corev1.EventTypeNormal, "MirrorAdopted",
+			"Adopted the existing %s in nfd.k8s-sigs.io group and replaced its spec", kind)
+	case result.drifted:
+		logger.Info(fmt.Sprintf("Restored the spec of %s in nfd.k8s-sigs.io group modified outside of the operator", kind))
+		recorder.Eventf(source, corev1.EventTypeWarning, "MirrorDriftCorrected",
+			"The spec of %s in nfd.k8s-sigs.io group was modified and has been restored", kind)
+	case result.operation != controllerutil.OperationResultNone:
+		logger.Info(fmt.Sprintf("Successfully updated %s in nfd.k8s-sigs.io group", kind))
+	}
+}
+
+// recordMirrorConflict logs and records an event on the source when its
+// mirror exists and is not owned by the operator
+func recordMirrorConflict(ctx context.Context, recorder record.EventRecorder, source client.Object, kind string,
+	adoptionPolicy MirrorAdoptionPolicy) {
+	log.FromContext(ctx).Info(fmt.Sprintf("%s in nfd.k8s-sigs.io group already exists and is not owned by the operator, not mirroring", kind),
+		"policy", adoptionPolicy)
+	recorder.Eventf(source, corev1.EventTypeWarning, "MirrorConflict",
+		"%s %s/%s in nfd.k8s-sigs.io group already exists and is not owned by this %s",
+		kind, source.GetNamespace(), source.GetName(), kind)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clt "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdv1openshiftioalpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+// getObject expects the object to be read as unstructured, the way the
+// mirroring reads both the source and the mirror
+func getObject(ctx context.Context, clnt *client.MockClient, obj runtime.Object) *gomock.Call {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	Expect(err).NotTo(HaveOccurred())
+	return getUnstructured(ctx, clnt, content)
+}
+
+func getUnstructured(ctx context.Context, clnt *client.MockClient, content map[string]interface{}) *gomock.Call {
+	return clnt.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&unstructured.Unstructured{})).
+		DoAndReturn(func(_ context.Context, _ clt.ObjectKey, obj clt.Object, _ ...clt.GetOption) error {
+			u := obj.(*unstructured.Unstructured)
+			gvk := u.GroupVersionKind()
+			u.Object = runtime.DeepCopyJSON(content)
+			u.SetGroupVersionKind(gvk)
+			return nil
+		})
+}
+
+var _ = Describe("createOrPatchMirror", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+	ctx := context.Background()
+
+	source := &nfdv1openshiftioalpha1.NodeFeatureGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "source-uid", Generation: 3},
+	}
+
+	// the source as stored by the API server, with a field added by a newer
+	// NFD version that the Go types of the operator do not know of
+	rawSource := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "test-name", "namespace": "test-namespace", "generation": int64(3)},
+		"spec": map[string]interface{}{
+			"featureGroupRules": []interface{}{
+				map[string]interface{}{"name": "test", "futureField": "kept"},
+			},
+		},
+	}
+
+	It("fields unknown to the operator are carried forward to the mirror", func() {
+		gomock.InOrder(
+			getUnstructured(ctx, clnt, rawSource),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj clt.Object, _ ...clt.CreateOption) error {
+					u := obj.(*unstructured.Unstructured)
+					Expect(u.GetAPIVersion()).To(Equal("nfd.k8s-sigs.io/v1alpha1"))
+					Expect(u.GetKind()).To(Equal("NodeFeatureGroup"))
+					Expect(u.Object["spec"]).To(Equal(rawSource["spec"]))
+					Expect(metav1.IsControlledBy(u, source)).To(BeTrue())
+					Expect(u.GetAnnotations()).To(HaveKeyWithValue(sourceGenerationAnnotation, "3"))
+					return nil
+				}),
+		)
+
+		_, result, err := createOrPatchMirror(ctx, clnt, scheme, source, MirrorAdoptionPolicyRefuse)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.operation).To(Equal(controllerutil.OperationResultCreated))
+	})
+
+	It("the mirror is not patched when it is up to date", func() {
+		mirror := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name": "test-name", "namespace": "test-namespace", "resourceVersion": "1",
+				"annotations": map[string]interface{}{sourceGenerationAnnotation: "3"},
+			},
+			"spec": rawSource["spec"],
+		}
+		gomock.InOrder(
+			getUnstructured(ctx, clnt, rawSource),
+			getUnstructured(ctx, clnt, mirror).Do(func(_ context.Context, _ clt.ObjectKey, obj clt.Object, _ ...clt.GetOption) {
+				Expect(controllerutil.SetControllerReference(source, obj, scheme)).To(Succeed())
+			}),
+		)
+
+		_, result, err := createOrPatchMirror(ctx, clnt, scheme, source, MirrorAdoptionPolicyRefuse)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(mirrorResult{operation: controllerutil.OperationResultNone}))
+	})
+
+	It("the mirror of another object is a conflict", func() {
+		other := source.DeepCopy()
+		other.UID = "other-uid"
+		gomock.InOrder(
+			getUnstructured(ctx, clnt, rawSource),
+			getUnstructured(ctx, clnt, rawSource).Do(func(_ context.Context, _ clt.ObjectKey, obj clt.Object, _ ...clt.GetOption) {
+				obj.SetResourceVersion("1")
+				Expect(controllerutil.SetControllerReference(other, obj, scheme)).To(Succeed())
+			}),
+		)
+
+		_, _, err := createOrPatchMirror(ctx, clnt, scheme, source, MirrorAdoptionPolicyAdopt)
+
+		Expect(err).To(MatchError(errMirrorConflict))
+	})
+})
@@ -69,32 +69,32 @@ func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleGC(ctx, nfdInstan
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleGC", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleGC), ctx, nfdInstance, operandImage)
 }
 
-// handleNetworkPolicies mocks base method.
-func (m *MocknodeFeatureDiscoveryHelperAPI) handleNetworkPolicies(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) error {
+// handleMaster mocks base method.
+func (m *MocknodeFeatureDiscoveryHelperAPI) handleMaster(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, operandImage string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "handleNetworkPolicies", ctx, nfdInstance)
+	ret := m.ctrl.Call(m, "handleMaster", ctx, nfdInstance, operandImage)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// handleNetworkPolicies indicates an expected call of handleNetworkPolicies.
-func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleNetworkPolicies(ctx, nfdInstance any) *gomock.Call {
+// handleMaster indicates an expected call of handleMaster.
+func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleMaster(ctx, nfdInstance, operandImage any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNetworkPolicies", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleNetworkPolicies), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleMaster", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleMaster), ctx, nfdInstance, operandImage)
 }
 
-// handleMaster mocks base method.
-func (m *MocknodeFeatureDiscoveryHelperAPI) handleMaster(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, operandImage string) error {
+// handleNetworkPolicies mocks base method.
+func (m *MocknodeFeatureDiscoveryHelperAPI) handleNetworkPolicies(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "handleMaster", ctx, nfdInstance, operandImage)
+	ret := m.ctrl.Call(m, "handleNetworkPolicies", ctx, nfdInstance)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// handleMaster indicates an expected call of handleMaster.
-func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleMaster(ctx, nfdInstance, operandImage any) *gomock.Call {
+// handleNetworkPolicies indicates an expected call of handleNetworkPolicies.
+func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleNetworkPolicies(ctx, nfdInstance any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleMaster", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleMaster), ctx, nfdInstance, operandImage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleNetworkPolicies", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleNetworkPolicies), ctx, nfdInstance)
 }
 
 // handlePrune mocks base method.
@@ -112,6 +112,20 @@ func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handlePrune(ctx, nfdIns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handlePrune", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handlePrune), ctx, nfdInstance, operandImage)
 }
 
+// handleRulePresets mocks base method.
+func (m *MocknodeFeatureDiscoveryHelperAPI) handleRulePresets(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "handleRulePresets", ctx, nfdInstance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// handleRulePresets indicates an expected call of handleRulePresets.
+func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleRulePresets(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleRulePresets", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleRulePresets), ctx, nfdInstance)
+}
+
 // handleSCCs mocks base method.
 func (m *MocknodeFeatureDiscoveryHelperAPI) handleSCCs(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) error {
 	m.ctrl.T.Helper()
@@ -127,17 +141,17 @@ func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleSCCs(ctx, nfdInst
 }
 
 // handleStatus mocks base method.
-func (m *MocknodeFeatureDiscoveryHelperAPI) handleStatus(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) error {
+func (m *MocknodeFeatureDiscoveryHelperAPI) handleStatus(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, reconcileErr error) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "handleStatus", ctx, nfdInstance)
+	ret := m.ctrl.Call(m, "handleStatus", ctx, nfdInstance, reconcileErr)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // handleStatus indicates an expected call of handleStatus.
-func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleStatus(ctx, nfdInstance any) *gomock.Call {
+func (mr *MocknodeFeatureDiscoveryHelperAPIMockRecorder) handleStatus(ctx, nfdInstance, reconcileErr any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleStatus", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleStatus), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "handleStatus", reflect.TypeOf((*MocknodeFeatureDiscoveryHelperAPI)(nil).handleStatus), ctx, nfdInstance, reconcileErr)
 }
 
 // handleTopology mocks base method.
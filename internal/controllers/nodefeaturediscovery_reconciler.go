@@ -22,36 +22,68 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	securityv1 "github.com/openshift/api/security/v1"
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
 	"github.com/openshift/cluster-nfd-operator/internal/configmap"
 	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
 	"github.com/openshift/cluster-nfd-operator/internal/deployment"
+	"github.com/openshift/cluster-nfd-operator/internal/featurefile"
 	"github.com/openshift/cluster-nfd-operator/internal/job"
 	"github.com/openshift/cluster-nfd-operator/internal/networkpolicy"
+	"github.com/openshift/cluster-nfd-operator/internal/noderesourcetopology"
+	"github.com/openshift/cluster-nfd-operator/internal/poddisruptionbudget"
+	"github.com/openshift/cluster-nfd-operator/internal/presets"
 	"github.com/openshift/cluster-nfd-operator/internal/scc"
 	"github.com/openshift/cluster-nfd-operator/internal/status"
+	"github.com/openshift/cluster-nfd-operator/pkg/metrics"
 )
 
-const finalizerLabel = "nfd-finalizer"
+const (
+	finalizerLabel = "nfd-finalizer"
+
+	// maxReconcileErrorLength bounds the reconcile error reported in the
+	// status
+	maxReconcileErrorLength = 1024
+
+	// reconcileTimeRefreshInterval bounds how often the time of the last
+	// successful reconcile is updated when nothing else in the status
+	// changes, as every patch of the status triggers a new reconcile
+	reconcileTimeRefreshInterval = 5 * time.Minute
+
+	// workerConfigMapRefIndex indexes the NodeFeatureDiscovery instances by
+	// the names of the user-owned worker ConfigMaps they reference
+	workerConfigMapRefIndex = "spec.workerConfig.configMapRef.name"
+)
 
 // NodeFeatureDiscoveryReconciler reconciles a NodeFeatureDiscovery object
 type nodeFeatureDiscoveryReconciler struct {
@@ -60,8 +92,11 @@ type nodeFeatureDiscoveryReconciler struct {
 
 func NewNodeFeatureDiscoveryReconciler(client client.Client, deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI,
 	configmapAPI configmap.ConfigMapAPI, jobAPI job.JobAPI, sccAPI scc.SccAPI, networkPolicyAPI networkpolicy.NetworkPolicyAPI,
-	statusAPI status.StatusAPI, scheme *runtime.Scheme, recorder record.EventRecorder) *nodeFeatureDiscoveryReconciler {
-	helper := newNodeFeatureDiscoveryHelperAPI(client, deploymentAPI, daemonsetAPI, configmapAPI, jobAPI, sccAPI, networkPolicyAPI, statusAPI, scheme, recorder)
+	pdbAPI poddisruptionbudget.PodDisruptionBudgetAPI, nrtAPI noderesourcetopology.NodeResourceTopologyAPI, statusAPI status.StatusAPI,
+	featureFileAPI featurefile.FeatureFileAPI, presetAPI presets.PresetAPI, scheme *runtime.Scheme, recorder record.EventRecorder,
+	sccAvailable bool) *nodeFeatureDiscoveryReconciler {
+	helper := newNodeFeatureDiscoveryHelperAPI(client, deploymentAPI, daemonsetAPI, configmapAPI, jobAPI, sccAPI, networkPolicyAPI, pdbAPI,
+		nrtAPI, statusAPI, featureFileAPI, presetAPI, scheme, recorder, sccAvailable)
 	return &nodeFeatureDiscoveryReconciler{
 		helper: helper,
 	}
@@ -72,8 +107,17 @@ func NewNodeFeatureDiscoveryReconciler(client client.Client, deploymentAPI deplo
 func (r *nodeFeatureDiscoveryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	p := getPredicates()
 
-	// watch for all events on NodeFeatureDiscovery and for
+	err := mgr.GetFieldIndexer().IndexField(context.Background(), &nfdv1.NodeFeatureDiscovery{}, workerConfigMapRefIndex,
+		workerConfigMapRefNames)
+	if err != nil {
+		return fmt.Errorf("failed to index NodeFeatureDiscovery instances by worker ConfigMap: %w", err)
+	}
+
+	// watch for all events on NodeFeatureDiscovery, for
 	// update and delete events for the resource created by operator
+	// for all events on the worker ConfigMaps referenced by the users,
+	// for leader changes of the nfd-master replicas, for all events on the
+	// NodeFeatureFiles and for label changes of the nodes
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nfdv1.NodeFeatureDiscovery{}).
 		Owns(&appsv1.Deployment{}, builder.WithPredicates(p)).
@@ -81,6 +125,14 @@ func (r *nodeFeatureDiscoveryReconciler) SetupWithManager(mgr ctrl.Manager) erro
 		Owns(&corev1.ConfigMap{}, builder.WithPredicates(p)).
 		Owns(&batchv1.Job{}, builder.WithPredicates(p)).
 		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(p)).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(p)).
+		Owns(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}, builder.WithPredicates(p)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(workerConfigMapReferrers(mgr.GetClient()))).
+		Watches(&coordinationv1.Lease{}, handler.EnqueueRequestsFromMapFunc(masterLeaseHolders(mgr.GetClient())),
+			builder.WithPredicates(getMasterLeasePredicates())).
+		Watches(&nfdopenshiftiov1alpha1.NodeFeatureFile{}, handler.EnqueueRequestsFromMapFunc(namespaceInstances(mgr.GetClient()))).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(featureFileInstances(mgr.GetClient())),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
 		Complete(reconcile.AsReconciler[*nfdv1.NodeFeatureDiscovery](mgr.GetClient(), r))
 }
 
@@ -97,6 +149,131 @@ func getPredicates() predicate.Predicate {
 	}
 }
 
+// workerConfigMapRefNames returns the names of the user-owned worker
+// ConfigMaps referenced by a NodeFeatureDiscovery, for workerConfigMapRefIndex
+func workerConfigMapRefNames(obj client.Object) []string {
+	nfdInstance, ok := obj.(*nfdv1.NodeFeatureDiscovery)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, ref := range nfdInstance.WorkerConfigMapRefs() {
+		if !slices.Contains(names, ref.Name) {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
+
+// workerConfigMapReferrers returns a MapFunc enqueueing the NodeFeatureDiscovery
+// instances whose worker configuration references the given ConfigMap. The
+// instances are looked up in workerConfigMapRefIndex, so that the events of
+// the ConfigMaps that no instance references are cheap to discard
+func workerConfigMapReferrers(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nfdList := nfdv1.NodeFeatureDiscoveryList{}
+		err := clnt.List(ctx, &nfdList, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{workerConfigMapRefIndex: obj.GetName()})
+		if err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureDiscovery instances", "namespace", obj.GetNamespace())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, nfd := range nfdList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKey{Namespace: nfd.Namespace, Name: nfd.Name},
+			})
+		}
+		return requests
+	}
+}
+
+// getMasterLeasePredicates only lets through the changes of holder of the
+// nfd-master leader election lease, and not its periodic renewals
+func getMasterLeasePredicates() predicate.Predicate {
+	isMasterLease := func(obj client.Object) bool {
+		return obj.GetName() == deployment.MasterLeaseName
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isMasterLease(e.Object) },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldLease, oldOK := e.ObjectOld.(*coordinationv1.Lease)
+			newLease, newOK := e.ObjectNew.(*coordinationv1.Lease)
+			if !oldOK || !newOK || !isMasterLease(newLease) {
+				return false
+			}
+			return ptr.Deref(oldLease.Spec.HolderIdentity, "") != ptr.Deref(newLease.Spec.HolderIdentity, "")
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool { return isMasterLease(e.Object) },
+	}
+}
+
+// masterLeaseHolders returns a MapFunc enqueueing the NodeFeatureDiscovery
+// instances of the namespace of the lease whose masters elect a leader
+func masterLeaseHolders(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nfdList := nfdv1.NodeFeatureDiscoveryList{}
+		if err := clnt.List(ctx, &nfdList, client.InNamespace(obj.GetNamespace())); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureDiscovery instances", "namespace", obj.GetNamespace())
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range nfdList.Items {
+			nfd := &nfdList.Items[i]
+			if nfd.Spec.Operand.MasterLeaderElection() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKey{Namespace: nfd.Namespace, Name: nfd.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// namespaceInstances returns a MapFunc enqueueing the NodeFeatureDiscovery
+// instances of the namespace of the object
+func namespaceInstances(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nfdList := nfdv1.NodeFeatureDiscoveryList{}
+		if err := clnt.List(ctx, &nfdList, client.InNamespace(obj.GetNamespace())); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureDiscovery instances", "namespace", obj.GetNamespace())
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(nfdList.Items))
+		for i := range nfdList.Items {
+			nfd := &nfdList.Items[i]
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKey{Namespace: nfd.Namespace, Name: nfd.Name},
+			})
+		}
+		return requests
+	}
+}
+
+// featureFileInstances returns a MapFunc enqueueing the NodeFeatureDiscovery
+// instances having NodeFeatureFiles, whose files may now be installed on, or
+// removed from, the node
+func featureFileInstances(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nffList := nfdopenshiftiov1alpha1.NodeFeatureFileList{}
+		if err := clnt.List(ctx, &nffList); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureFiles")
+			return nil
+		}
+		var requests []reconcile.Request
+		namespaces := map[string]bool{}
+		for _, nff := range nffList.Items {
+			if namespaces[nff.Namespace] {
+				continue
+			}
+			namespaces[nff.Namespace] = true
+			requests = append(requests, namespaceInstances(clnt)(ctx, &nff)...)
+		}
+		return requests
+	}
+}
+
 func isControlledByNFD(obj client.Object) bool {
 	controller := metav1.GetControllerOf(obj)
 	if controller == nil {
@@ -116,10 +293,16 @@ func getOperandImage(nfdInstance *nfdv1.NodeFeatureDiscovery) string {
 // +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturefiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturefiles/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=nfd.kubernetes.io,resources=nodefeaturediscoveries,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nfd.kubernetes.io,resources=nodefeaturediscoveries/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=nfd.kubernetes.io,resources=nodefeaturediscoveries/finalizers,verbs=update
@@ -127,7 +310,8 @@ func getOperandImage(nfdInstance *nfdv1.NodeFeatureDiscovery) string {
 
 // Reconcile moves the current state of the cluster closer to the desired state.
 // It creates/pataches the NFD components ( master, worker, topology, prune, GC) in accordance with
-// NFD CR Spec. In addition, it also updates the Status of the NFD CR
+// NFD CR Spec. In addition, it also updates the Status of the NFD CR. Unmanaged instances only get
+// their Status updated and the components of Removed instances are deleted
 func (r *nodeFeatureDiscoveryReconciler) Reconcile(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (ctrl.Result, error) {
 	res := ctrl.Result{}
 	logger := ctrl.LoggerFrom(ctx).WithValues("instance namespace", nfdInstance.Namespace, "instance name", nfdInstance.Name)
@@ -147,18 +331,29 @@ func (r *nodeFeatureDiscoveryReconciler) Reconcile(ctx context.Context, nfdInsta
 			// reconcile will be called again when prune job has been completed
 			return res, nil
 		}
+		metrics.DeleteManagementState(nfdInstance.Name, nfdInstance.Namespace)
 		return res, r.helper.removeFinalizer(ctx, nfdInstance)
 	}
 
-	// setting the topology flag to false, in order to skip the generation of Topology deployment
-	// this code can be removed once it is decided that NFD should be responsible for topology deployment
-	nfdInstance.Spec.TopologyUpdater = false
-
 	// If the finalizer doesn't exist, add it.
 	if !r.helper.hasFinalizer(nfdInstance) {
 		return res, r.helper.setFinalizer(ctx, nfdInstance)
 	}
 
+	switch nfdInstance.GetManagementState() {
+	case nfdv1.Unmanaged:
+		// the operands may be edited by hand, e.g. to debug them
+		logger.Info("instance is unmanaged, only reconciling NFD status")
+		return res, r.helper.handleStatus(ctx, nfdInstance, nil)
+	case nfdv1.Removed:
+		logger.Info("instance is removed, deleting the components")
+		err := r.helper.finalizeComponents(ctx, nfdInstance)
+		if err != nil {
+			err = fmt.Errorf("failed to remove components for %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
+		}
+		return res, errors.Join(err, r.helper.handleStatus(ctx, nfdInstance, err))
+	}
+
 	errs := make([]error, 0, 10)
 
 	logger.Info("reconciling SCCs")
@@ -185,10 +380,14 @@ func (r *nodeFeatureDiscoveryReconciler) Reconcile(ctx context.Context, nfdInsta
 	err = r.helper.handleNetworkPolicies(ctx, nfdInstance)
 	errs = append(errs, err)
 
-	logger.Info("reconciling NFD status")
-	err = r.helper.handleStatus(ctx, nfdInstance)
+	logger.Info("reconciling rule presets")
+	err = r.helper.handleRulePresets(ctx, nfdInstance)
 	errs = append(errs, err)
-	return res, errors.Join(errs...)
+
+	logger.Info("reconciling NFD status")
+	reconcileErr := errors.Join(errs...)
+	err = r.helper.handleStatus(ctx, nfdInstance, reconcileErr)
+	return res, errors.Join(reconcileErr, err)
 }
 
 //go:generate mockgen -source=nodefeaturediscovery_reconciler.go -package=new_controllers -destination=mock_nodefeaturediscovery_reconciler.go nodeFeatureDiscoveryHelperAPI
@@ -205,7 +404,8 @@ type nodeFeatureDiscoveryHelperAPI interface {
 	handleGC(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error
 	handleNetworkPolicies(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error
 	handlePrune(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) (bool, error)
-	handleStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error
+	handleRulePresets(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error
+	handleStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, reconcileErr error) error
 }
 
 type nodeFeatureDiscoveryHelper struct {
@@ -216,14 +416,23 @@ type nodeFeatureDiscoveryHelper struct {
 	jobAPI           job.JobAPI
 	sccAPI           scc.SccAPI
 	networkPolicyAPI networkpolicy.NetworkPolicyAPI
+	pdbAPI           poddisruptionbudget.PodDisruptionBudgetAPI
+	nrtAPI           noderesourcetopology.NodeResourceTopologyAPI
 	statusAPI        status.StatusAPI
+	featureFileAPI   featurefile.FeatureFileAPI
+	presetAPI        presets.PresetAPI
 	scheme           *runtime.Scheme
 	recorder         record.EventRecorder
+	// sccAvailable is false on clusters without the SCC API, e.g. vanilla
+	// Kubernetes, where Pod Security Admission labels are used instead
+	sccAvailable bool
 }
 
 func newNodeFeatureDiscoveryHelperAPI(client client.Client, deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI,
 	configmapAPI configmap.ConfigMapAPI, jobAPI job.JobAPI, sccAPI scc.SccAPI, networkPolicyAPI networkpolicy.NetworkPolicyAPI,
-	statusAPI status.StatusAPI, scheme *runtime.Scheme, recorder record.EventRecorder) nodeFeatureDiscoveryHelperAPI {
+	pdbAPI poddisruptionbudget.PodDisruptionBudgetAPI, nrtAPI noderesourcetopology.NodeResourceTopologyAPI, statusAPI status.StatusAPI,
+	featureFileAPI featurefile.FeatureFileAPI, presetAPI presets.PresetAPI, scheme *runtime.Scheme, recorder record.EventRecorder,
+	sccAvailable bool) nodeFeatureDiscoveryHelperAPI {
 	return &nodeFeatureDiscoveryHelper{
 		client:           client,
 		deploymentAPI:    deploymentAPI,
@@ -232,52 +441,95 @@ func newNodeFeatureDiscoveryHelperAPI(client client.Client, deploymentAPI deploy
 		jobAPI:           jobAPI,
 		sccAPI:           sccAPI,
 		networkPolicyAPI: networkPolicyAPI,
+		pdbAPI:           pdbAPI,
+		nrtAPI:           nrtAPI,
 		statusAPI:        statusAPI,
+		featureFileAPI:   featureFileAPI,
+		presetAPI:        presetAPI,
 		scheme:           scheme,
 		recorder:         recorder,
+		sccAvailable:     sccAvailable,
 	}
 }
 
 func (nfdh *nodeFeatureDiscoveryHelper) finalizeComponents(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
-	err := nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, "nfd-worker")
+	workerName := nfdInstance.ComponentName("nfd-worker")
+	err := nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, workerName)
 	if err != nil {
 		return fmt.Errorf("failed to delete worker daemonset: %w", err)
 	}
 
-	err = nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, "nfd-worker")
-	if err != nil {
-		return fmt.Errorf("failed to delete worker config map: %w", err)
+	if !isWorkerConfigMapReferenced(nfdInstance, workerName) {
+		err = nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, workerName)
+		if err != nil {
+			return fmt.Errorf("failed to delete worker config map: %w", err)
+		}
 	}
 
-	if nfdInstance.Spec.TopologyUpdater {
-		err = nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, "nfd-topology-updater")
+	for _, profile := range nfdInstance.Spec.WorkerProfiles {
+		name := nfdInstance.WorkerProfileName(profile.Name)
+		err = nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, name)
 		if err != nil {
-			return fmt.Errorf("failed to delete topology-updater daemonset: %w", err)
+			return fmt.Errorf("failed to delete worker daemonset of profile %s: %w", profile.Name, err)
+		}
+		if isWorkerConfigMapReferenced(nfdInstance, name) {
+			continue
+		}
+		err = nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete worker config map of profile %s: %w", profile.Name, err)
 		}
 	}
-	err = nfdh.deploymentAPI.DeleteDeployment(ctx, nfdInstance.Namespace, "nfd-master")
+
+	err = nfdh.deleteFeatureFiles(ctx, nfdInstance)
+	if err != nil {
+		return err
+	}
+
+	err = nfdh.deleteTopology(ctx, nfdInstance)
+	if err != nil {
+		return err
+	}
+	err = nfdh.deploymentAPI.DeleteDeployment(ctx, nfdInstance.Namespace, nfdInstance.ComponentName("nfd-master"))
 	if err != nil {
 		return fmt.Errorf("failed to delete master deployment: %w", err)
 	}
 
-	err = nfdh.deploymentAPI.DeleteDeployment(ctx, nfdInstance.Namespace, "nfd-gc")
+	err = nfdh.pdbAPI.DeletePodDisruptionBudget(ctx, nfdInstance.Namespace, nfdInstance.ComponentName("nfd-master"))
+	if err != nil {
+		return fmt.Errorf("failed to delete master pod disruption budget: %w", err)
+	}
+
+	err = nfdh.deploymentAPI.DeleteDeployment(ctx, nfdInstance.Namespace, nfdInstance.ComponentName("nfd-gc"))
 	if err != nil {
 		return fmt.Errorf("failed to delete nfd-gc deployment: %w", err)
 	}
 
-	for _, name := range []string{"nfd-master", "nfd-worker", "nfd-gc"} {
+	for _, component := range []string{"nfd-master", "nfd-worker", "nfd-gc"} {
+		name := nfdInstance.ComponentName(component)
 		err = nfdh.networkPolicyAPI.DeleteNetworkPolicy(ctx, nfdInstance.Namespace, name)
 		if err != nil {
 			return fmt.Errorf("failed to delete %s NetworkPolicy: %w", name, err)
 		}
 	}
 
-	err = nfdh.sccAPI.DeleteSCC(ctx, "nfd-worker")
+	// the preset rules are owned by the instance, but a Removed instance
+	// is kept and would still label the nodes through them
+	err = nfdh.deleteRulePresets(ctx, nfdInstance)
+	if err != nil {
+		return err
+	}
+
+	if !nfdh.sccAvailable {
+		return nil
+	}
+
+	err = nfdh.sccAPI.RemoveSCCUser(ctx, nfdInstance, workerName, scc.WorkerServiceAccount)
 	if err != nil {
-		return fmt.Errorf("failed to delete nfd-worker scc: %w", err)
+		return fmt.Errorf("failed to release %s scc: %w", workerName, err)
 	}
 
-	return nfdh.sccAPI.DeleteSCC(ctx, "nfd-topology-updater")
+	return nfdh.sccAPI.RemoveSCCUser(ctx, nfdInstance, nfdInstance.ComponentName("nfd-topology-updater"), scc.TopologyServiceAccount)
 }
 
 func (nfdh *nodeFeatureDiscoveryHelper) hasFinalizer(nfdInstance *nfdv1.NodeFeatureDiscovery) bool {
@@ -300,37 +552,51 @@ func (nfdh *nodeFeatureDiscoveryHelper) removeFinalizer(ctx context.Context, ins
 func (nfdh *nodeFeatureDiscoveryHelper) handleSCCs(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
 	logger := ctrl.LoggerFrom(ctx)
 
+	if !nfdh.sccAvailable {
+		namespace := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.Namespace},
+		}
+		nsRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &namespace, func() error {
+			return nfdh.sccAPI.SetPodSecurityLabelsAsDesired(ctx, nfdInstance, &namespace)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile pod security labels of namespace %s: %w", namespace.Name, err)
+		}
+		logger.Info("SCC API not available, reconciled pod security labels", "namespace", namespace.Name, "result", nsRes)
+		return nil
+	}
+
 	workerSCC := securityv1.SecurityContextConstraints{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-worker"},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-worker")},
 	}
 
 	workerRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &workerSCC, func() error {
 		return nfdh.sccAPI.SetWorkerSCCAsDesired(ctx, nfdInstance, &workerSCC)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to reconcile nfd-worker SCC: %w", err)
+		return fmt.Errorf("failed to reconcile %s SCC: %w", workerSCC.Name, err)
 	}
-	logger.Info("reconciled nfd-worker SCC", "result", workerRes)
+	logger.Info("reconciled nfd-worker SCC", "name", workerSCC.Name, "result", workerRes)
 
 	topologySCC := securityv1.SecurityContextConstraints{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-topology-updater"},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-topology-updater")},
 	}
 
 	topologyRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &topologySCC, func() error {
 		return nfdh.sccAPI.SetTopologySCCAsDesired(ctx, nfdInstance, &topologySCC)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to reconcile nfd-topology-updater SCC: %w", err)
+		return fmt.Errorf("failed to reconcile %s SCC: %w", topologySCC.Name, err)
 	}
 
-	logger.Info("reconciled nfd-topology-updater SCC", "result", topologyRes)
+	logger.Info("reconciled nfd-topology-updater SCC", "name", topologySCC.Name, "result", topologyRes)
 
 	return nil
 }
 
 func (nfdh *nodeFeatureDiscoveryHelper) handleMaster(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
 	masterDep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-master", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-master"), Namespace: nfdInstance.Namespace},
 	}
 	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &masterDep, func() error {
 		return nfdh.deploymentAPI.SetMasterDeploymentAsDesired(nfdInstance, &masterDep, operandImage)
@@ -340,47 +606,277 @@ func (nfdh *nodeFeatureDiscoveryHelper) handleMaster(ctx context.Context, nfdIns
 		return fmt.Errorf("failed to reconcile master deployment %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
 	}
 	ctrl.LoggerFrom(ctx).Info("reconciled master deployment", "namespace", nfdInstance.Namespace, "name", nfdInstance.Name, "result", opRes)
+
+	return nfdh.handleMasterPodDisruptionBudget(ctx, nfdInstance)
+}
+
+// handleMasterPodDisruptionBudget protects the nfd-master replicas from
+// voluntary disruptions. A single replica has no budget, as it would block
+// the drain of its node
+func (nfdh *nodeFeatureDiscoveryHelper) handleMasterPodDisruptionBudget(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	name := nfdInstance.ComponentName("nfd-master")
+	if !nfdInstance.Spec.Operand.MasterLeaderElection() {
+		err := nfdh.pdbAPI.DeletePodDisruptionBudget(ctx, nfdInstance.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete master pod disruption budget: %w", err)
+		}
+		return nil
+	}
+
+	masterPDB := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nfdInstance.Namespace},
+	}
+	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &masterPDB, func() error {
+		return nfdh.pdbAPI.SetMasterPodDisruptionBudgetAsDesired(nfdInstance, &masterPDB)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile master pod disruption budget %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
+	}
+	ctrl.LoggerFrom(ctx).Info("reconciled master pod disruption budget", "namespace", nfdInstance.Namespace, "name", nfdInstance.Name, "result", opRes)
 	return nil
 }
 
 func (nfdh *nodeFeatureDiscoveryHelper) handleWorker(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
-	logger := ctrl.LoggerFrom(ctx)
+	profiles := nfdInstance.GetWorkerProfiles()
+
+	if len(nfdInstance.Spec.WorkerProfiles) > 0 {
+		nodes := corev1.NodeList{}
+		err := nfdh.client.List(ctx, &nodes)
+		if err != nil {
+			return fmt.Errorf("failed to list nodes: %w", err)
+		}
+		// a node selected by two profiles would run two workers fighting over
+		// its labels, so the profiles are left untouched until this is fixed
+		if overlaps := daemonset.FindWorkerProfileOverlaps(profiles, nodes.Items); len(overlaps) > 0 {
+			message := fmt.Sprintf("worker profiles overlap: %s", strings.Join(overlaps, "; "))
+			nfdh.recorder.Event(nfdInstance, corev1.EventTypeWarning, "WorkerProfilesOverlap", message)
+			return fmt.Errorf("failed to reconcile workers of %s/%s: %s", nfdInstance.Namespace, nfdInstance.Name, message)
+		}
+	}
+
+	featureFilesConfigMap, err := nfdh.handleFeatureFiles(ctx, nfdInstance)
+	if err != nil {
+		return err
+	}
 
-	workerCM := corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-worker", Namespace: nfdInstance.Namespace},
+	for i := range profiles {
+		err := nfdh.handleWorkerProfile(ctx, nfdInstance, &profiles[i], operandImage, featureFilesConfigMap)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nfdh.deleteStaleWorkers(ctx, nfdInstance)
+}
+
+// handleFeatureFiles stores the feature files of the NodeFeatureFiles of the
+// namespace in a ConfigMap and the files of each node in the ConfigMaps of the
+// buckets, and returns the name of the first one, which is empty when there
+// are no NodeFeatureFiles, in which case the ConfigMaps are deleted. The
+// status of each NodeFeatureFile reports the selected nodes running a worker
+// pod
+func (nfdh *nodeFeatureDiscoveryHelper) handleFeatureFiles(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (string, error) {
+	name := featurefile.ConfigMapName(nfdInstance)
+
+	nffs, err := nfdh.featureFileAPI.GetNodeFeatureFiles(ctx, nfdInstance.Namespace)
+	if err != nil {
+		return "", err
 	}
-	cmRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &workerCM, func() error {
-		return nfdh.configmapAPI.SetWorkerConfigMapAsDesired(ctx, nfdInstance, &workerCM)
+	if len(nffs) == 0 {
+		return "", nfdh.deleteFeatureFiles(ctx, nfdInstance)
+	}
+
+	nodes := corev1.NodeList{}
+	err = nfdh.client.List(ctx, &nodes)
+	if err != nil {
+		return "", fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for i := range nffs {
+		if _, err := featurefile.SelectNodes(&nffs[i], nodes.Items); err != nil {
+			nfdh.recorder.Event(&nffs[i], corev1.EventTypeWarning, "InvalidNodeSelector", err.Error())
+		}
+	}
+
+	featureFilesCM := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nfdInstance.Namespace},
+	}
+	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &featureFilesCM, func() error {
+		return nfdh.featureFileAPI.SetFeatureFilesConfigMapAsDesired(ctx, nfdInstance, nffs, &featureFilesCM)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to reconcile worker configmap %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
+		return "", fmt.Errorf("failed to reconcile feature files configmap %s/%s: %w", nfdInstance.Namespace, name, err)
+	}
+	ctrl.LoggerFrom(ctx).Info("reconciled feature files ConfigMap", "namespace", nfdInstance.Namespace, "name", name, "result", opRes)
+
+	// the patches only carry the keys of the nodes whose files changed
+	for bucket, nodeFiles := range featurefile.GetNodeFiles(nffs, nodes.Items) {
+		nodeFilesCM := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: featurefile.NodeFilesConfigMapName(name, bucket), Namespace: nfdInstance.Namespace},
+		}
+		_, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &nodeFilesCM, func() error {
+			return nfdh.featureFileAPI.SetNodeFilesConfigMapAsDesired(ctx, nfdInstance, nodeFiles, &nodeFilesCM)
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to reconcile feature files configmap %s/%s: %w", nfdInstance.Namespace, nodeFilesCM.Name, err)
+		}
+	}
+
+	workerNodes, err := nfdh.featureFileAPI.GetWorkerNodeNames(ctx, nfdInstance)
+	if err != nil {
+		return "", err
+	}
+	errs := []error{}
+	for i := range nffs {
+		errs = append(errs, nfdh.featureFileAPI.UpdateNodeFeatureFileStatus(ctx, &nffs[i], nodes.Items, workerNodes))
+	}
+
+	return name, errors.Join(errs...)
+}
+
+// deleteFeatureFiles deletes the ConfigMap holding the feature files and the
+// ConfigMaps of the buckets listing the files of the nodes
+func (nfdh *nodeFeatureDiscoveryHelper) deleteFeatureFiles(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	name := featurefile.ConfigMapName(nfdInstance)
+	names := []string{name}
+	for bucket := 0; bucket < featurefile.NodeFilesBuckets; bucket++ {
+		names = append(names, featurefile.NodeFilesConfigMapName(name, bucket))
+	}
+	for _, name := range names {
+		err := nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete feature files configmap %s/%s: %w", nfdInstance.Namespace, name, err)
+		}
+	}
+	return nil
+}
+
+func (nfdh *nodeFeatureDiscoveryHelper) handleWorkerProfile(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	profile *nfdv1.WorkerProfile, operandImage string, featureFilesConfigMap string) error {
+	logger := ctrl.LoggerFrom(ctx)
+	workerName := nfdInstance.WorkerProfileName(profile.Name)
+
+	if ref := profile.WorkerConfig.ConfigMapRef; ref != nil {
+		// the worker configuration is owned by the user, remove the ConfigMap
+		// the operator may have generated before the reference was set
+		if !isWorkerConfigMapReferenced(nfdInstance, workerName) {
+			err := nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, workerName)
+			if err != nil {
+				return fmt.Errorf("failed to delete generated worker configmap %s/%s: %w", nfdInstance.Namespace, workerName, err)
+			}
+		}
+		logger.Info("using referenced worker ConfigMap", "namespace", nfdInstance.Namespace, "name", ref.Name, "key", ref.Key)
+	} else {
+		workerCM := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: workerName, Namespace: nfdInstance.Namespace},
+		}
+		cmRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &workerCM, func() error {
+			return nfdh.configmapAPI.SetWorkerConfigMapAsDesired(ctx, nfdInstance, profile, &workerCM)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reconcile worker configmap %s/%s: %w", nfdInstance.Namespace, workerName, err)
+		}
+		logger.Info("reconciled worker ConfigMap", "namespace", nfdInstance.Namespace, "name", workerName, "result", cmRes)
 	}
-	logger.Info("reconciled worker ConfigMap", "namespace", nfdInstance.Namespace, "name", nfdInstance.Name, "result", cmRes)
 
 	workerDS := appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-worker", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: workerName, Namespace: nfdInstance.Namespace},
 	}
 	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &workerDS, func() error {
-		return nfdh.daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, nfdInstance, &workerDS, operandImage)
+		return nfdh.daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, nfdInstance, profile, &workerDS, operandImage, featureFilesConfigMap)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to reconcile worker DaemonSet %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
+		return fmt.Errorf("failed to reconcile worker DaemonSet %s/%s: %w", nfdInstance.Namespace, workerName, err)
 	}
 
-	logger.Info("reconciled worker DaemonSet", "namespace", nfdInstance.Namespace, "name", nfdInstance.Name, "result", opRes)
+	logger.Info("reconciled worker DaemonSet", "namespace", nfdInstance.Namespace, "name", workerName, "result", opRes)
 
 	return nil
 }
 
-func (nfdh *nodeFeatureDiscoveryHelper) handleTopology(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
+// deleteStaleWorkers removes the worker DaemonSets and ConfigMaps of the
+// profiles that are no longer in the spec, as well as the single worker ones
+// once profiles are set
+func (nfdh *nodeFeatureDiscoveryHelper) deleteStaleWorkers(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	desired := map[string]bool{}
+	for _, profile := range nfdInstance.GetWorkerProfiles() {
+		desired[nfdInstance.WorkerProfileName(profile.Name)] = true
+	}
+
+	stale := []string{}
+	if defaultName := nfdInstance.WorkerProfileName(""); !desired[defaultName] {
+		stale = append(stale, defaultName)
+	}
+	profileDSs := appsv1.DaemonSetList{}
+	err := nfdh.client.List(ctx, &profileDSs, client.InNamespace(nfdInstance.Namespace), client.HasLabels{daemonset.WorkerProfileLabel})
+	if err != nil {
+		return fmt.Errorf("failed to list worker profile daemonsets in %s: %w", nfdInstance.Namespace, err)
+	}
+	for i := range profileDSs.Items {
+		ds := &profileDSs.Items[i]
+		if metav1.IsControlledBy(ds, nfdInstance) && !desired[ds.Name] {
+			stale = append(stale, ds.Name)
+		}
+	}
+
+	for _, name := range stale {
+		err = nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete stale worker daemonset: %w", err)
+		}
+		if isWorkerConfigMapReferenced(nfdInstance, name) {
+			continue
+		}
+		err = nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete stale worker config map: %w", err)
+		}
+		ctrl.LoggerFrom(ctx).Info("deleted stale worker", "namespace", nfdInstance.Namespace, "name", name)
+	}
+	return nil
+}
+
+// isWorkerConfigMapReferenced tells whether a user-owned ConfigMap, which must
+// never be deleted by the operator, is referenced under the given name
+func isWorkerConfigMapReferenced(nfdInstance *nfdv1.NodeFeatureDiscovery, name string) bool {
+	for _, ref := range nfdInstance.WorkerConfigMapRefs() {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
 
+func (nfdh *nodeFeatureDiscoveryHelper) handleTopology(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
 	if !nfdInstance.Spec.TopologyUpdater {
-		return nil
+		return nfdh.deleteTopology(ctx, nfdInstance)
+	}
+
+	nrtCRD := apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: noderesourcetopology.CRDName},
+	}
+	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &nrtCRD, func() error {
+		return nfdh.nrtAPI.SetCRDAsDesired(&nrtCRD)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile CRD %s: %w", noderesourcetopology.CRDName, err)
+	}
+	ctrl.LoggerFrom(ctx).Info("reconciled NodeResourceTopology CRD", "name", noderesourcetopology.CRDName, "result", opRes)
+
+	topologyCM := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-topology-updater"), Namespace: nfdInstance.Namespace},
+	}
+	opRes, err = controllerutil.CreateOrPatch(ctx, nfdh.client, &topologyCM, func() error {
+		return nfdh.configmapAPI.SetTopologyUpdaterConfigMapAsDesired(ctx, nfdInstance, &topologyCM)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile topology configmap %s/%s: %w", nfdInstance.Namespace, nfdInstance.Name, err)
 	}
+	ctrl.LoggerFrom(ctx).Info("reconciled topology configmap", "namespace", nfdInstance.Namespace, "name", nfdInstance.Name, "result", opRes)
+
 	topologyDS := appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-topology-updater", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-topology-updater"), Namespace: nfdInstance.Namespace},
 	}
-	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &topologyDS, func() error {
+	opRes, err = controllerutil.CreateOrPatch(ctx, nfdh.client, &topologyDS, func() error {
 		return nfdh.daemonsetAPI.SetTopologyDaemonsetAsDesired(ctx, nfdInstance, &topologyDS, operandImage)
 	})
 
@@ -391,11 +887,45 @@ func (nfdh *nodeFeatureDiscoveryHelper) handleTopology(ctx context.Context, nfdI
 	return nil
 }
 
+// deleteTopology deletes the topology-updater daemonset and configmap of the
+// instance, and the NodeResourceTopology CRD once no other instance runs the
+// topology-updater
+func (nfdh *nodeFeatureDiscoveryHelper) deleteTopology(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	name := nfdInstance.ComponentName("nfd-topology-updater")
+	err := nfdh.daemonsetAPI.DeleteDaemonSet(ctx, nfdInstance.Namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete topology-updater daemonset: %w", err)
+	}
+	err = nfdh.configmapAPI.DeleteConfigMap(ctx, nfdInstance.Namespace, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete topology-updater config map: %w", err)
+	}
+
+	nfdList := nfdv1.NodeFeatureDiscoveryList{}
+	err = nfdh.client.List(ctx, &nfdList)
+	if err != nil {
+		return fmt.Errorf("failed to list NodeFeatureDiscovery instances: %w", err)
+	}
+	for _, nfd := range nfdList.Items {
+		if nfd.UID == nfdInstance.UID || nfd.DeletionTimestamp != nil {
+			continue
+		}
+		if nfd.Spec.TopologyUpdater {
+			return nil
+		}
+	}
+	err = nfdh.nrtAPI.DeleteCRD(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete NodeResourceTopology CRD: %w", err)
+	}
+	return nil
+}
+
 func (nfdh *nodeFeatureDiscoveryHelper) handleNetworkPolicies(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
 	logger := ctrl.LoggerFrom(ctx)
 
 	masterNP := networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-master", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-master"), Namespace: nfdInstance.Namespace},
 	}
 	masterRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &masterNP, func() error {
 		return nfdh.networkPolicyAPI.SetMasterNetworkPolicyAsDesired(nfdInstance, &masterNP)
@@ -406,7 +936,7 @@ func (nfdh *nodeFeatureDiscoveryHelper) handleNetworkPolicies(ctx context.Contex
 	logger.Info("reconciled nfd-master NetworkPolicy", "namespace", nfdInstance.Namespace, "result", masterRes)
 
 	workerNP := networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-worker", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-worker"), Namespace: nfdInstance.Namespace},
 	}
 	workerRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &workerNP, func() error {
 		return nfdh.networkPolicyAPI.SetWorkerNetworkPolicyAsDesired(nfdInstance, &workerNP)
@@ -417,7 +947,7 @@ func (nfdh *nodeFeatureDiscoveryHelper) handleNetworkPolicies(ctx context.Contex
 	logger.Info("reconciled nfd-worker NetworkPolicy", "namespace", nfdInstance.Namespace, "result", workerRes)
 
 	gcNP := networkingv1.NetworkPolicy{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-gc", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-gc"), Namespace: nfdInstance.Namespace},
 	}
 	gcRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &gcNP, func() error {
 		return nfdh.networkPolicyAPI.SetGCNetworkPolicyAsDesired(nfdInstance, &gcNP)
@@ -432,7 +962,7 @@ func (nfdh *nodeFeatureDiscoveryHelper) handleNetworkPolicies(ctx context.Contex
 
 func (nfdh *nodeFeatureDiscoveryHelper) handleGC(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
 	gcDep := appsv1.Deployment{
-		ObjectMeta: metav1.ObjectMeta{Name: "nfd-gc", Namespace: nfdInstance.Namespace},
+		ObjectMeta: metav1.ObjectMeta{Name: nfdInstance.ComponentName("nfd-gc"), Namespace: nfdInstance.Namespace},
 	}
 	opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &gcDep, func() error {
 		return nfdh.deploymentAPI.SetGCDeploymentAsDesired(nfdInstance, &gcDep, operandImage)
@@ -450,7 +980,7 @@ func (nfdh *nodeFeatureDiscoveryHelper) handlePrune(ctx context.Context, nfdInst
 		return true, nil
 	}
 
-	pruneJob, err := nfdh.jobAPI.GetJob(ctx, nfdInstance.Namespace, "nfd-prune")
+	pruneJob, err := nfdh.jobAPI.GetJob(ctx, nfdInstance.Namespace, nfdInstance.ComponentName("nfd-prune"))
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			err = nfdh.jobAPI.CreatePruneJob(ctx, nfdInstance, operandImage)
@@ -476,23 +1006,163 @@ func (nfdh *nodeFeatureDiscoveryHelper) handlePrune(ctx context.Context, nfdInst
 	return done, returnErr
 }
 
-func (nfdh *nodeFeatureDiscoveryHelper) handleStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
-	conditions := nfdh.statusAPI.GetConditions(ctx, nfdInstance)
-	if nfdh.statusAPI.AreConditionsEqual(nfdInstance.Status.Conditions, conditions) {
+// handleRulePresets creates a NodeFeatureRule for each preset enabled in the
+// spec, upgrades it when the catalog ships a newer version of the preset and
+// deletes the NodeFeatureRules of the presets that are not enabled anymore
+func (nfdh *nodeFeatureDiscoveryHelper) handleRulePresets(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	logger := ctrl.LoggerFrom(ctx)
+	enabled := map[string]bool{}
+	errs := []error{}
+
+	for _, presetName := range nfdInstance.Spec.RulePresets {
+		preset, ok := presets.Get(presetName)
+		if !ok {
+			nfdh.recorder.Eventf(nfdInstance, corev1.EventTypeWarning, "UnknownRulePreset",
+				"rule preset %s is not part of the catalog of the operator", presetName)
+			continue
+		}
+		name := presets.RuleName(nfdInstance, preset.Name)
+		enabled[name] = true
+
+		nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: nfdInstance.Namespace},
+		}
+		previousVersion := ""
+		opRes, err := controllerutil.CreateOrPatch(ctx, nfdh.client, &nfr, func() error {
+			previousVersion = nfr.Annotations[presets.PresetVersionAnnotation]
+			return nfdh.presetAPI.SetPresetRuleAsDesired(ctx, nfdInstance, preset, &nfr)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to reconcile NodeFeatureRule %s/%s of rule preset %s: %w", nfdInstance.Namespace, name, preset.Name, err))
+			continue
+		}
+		logger.Info("reconciled rule preset NodeFeatureRule", "namespace", nfdInstance.Namespace, "name", name, "result", opRes)
+		if previousVersion != "" && previousVersion != nfr.Annotations[presets.PresetVersionAnnotation] {
+			nfdh.recorder.Eventf(nfdInstance, corev1.EventTypeNormal, "RulePresetUpgraded",
+				"rule preset %s upgraded from version %s to version %d", preset.Name, previousVersion, preset.Version)
+		}
+	}
+
+	nfrs, err := nfdh.presetAPI.GetPresetRules(ctx, nfdInstance)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, nfr := range nfrs {
+		if !enabled[nfr.Name] {
+			errs = append(errs, nfdh.presetAPI.DeletePresetRule(ctx, nfr.Namespace, nfr.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deleteRulePresets deletes the NodeFeatureRules of all the rule presets of
+// the instance
+func (nfdh *nodeFeatureDiscoveryHelper) deleteRulePresets(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) error {
+	nfrs, err := nfdh.presetAPI.GetPresetRules(ctx, nfdInstance)
+	if err != nil {
+		return err
+	}
+	for _, nfr := range nfrs {
+		err = nfdh.presetAPI.DeletePresetRule(ctx, nfr.Namespace, nfr.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleStatus reports the state of the components and the outcome of the
+// reconcile, reconcileErr being the errors of the previous steps. The status
+// is only patched when it changes, as every patch triggers a new reconcile
+func (nfdh *nodeFeatureDiscoveryHelper) handleStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, reconcileErr error) error {
+	metrics.ManagementState(nfdInstance.Name, nfdInstance.Namespace, nfdInstance.GetManagementState())
+	components := nfdh.statusAPI.GetComponents(ctx, nfdInstance)
+	conditions := nfdh.statusAPI.GetConditions(ctx, nfdInstance, components)
+	masterLeader := nfdh.statusAPI.GetMasterLeader(ctx, nfdInstance)
+	reconcileError := formatReconcileError(reconcileErr)
+	// the spec of an Unmanaged instance is not applied to the operands
+	observedGeneration := nfdInstance.Generation
+	if nfdInstance.GetManagementState() == nfdv1.Unmanaged {
+		observedGeneration = nfdInstance.Status.ObservedGeneration
+	}
+	reconcileTimeOutdated := reconcileErr == nil && (nfdInstance.Status.LastReconcileTime == nil ||
+		time.Since(nfdInstance.Status.LastReconcileTime.Time) >= reconcileTimeRefreshInterval)
+	if nfdh.statusAPI.AreConditionsEqual(nfdInstance.Status.Conditions, conditions) &&
+		nfdInstance.Status.MasterLeader == masterLeader &&
+		equality.Semantic.DeepEqual(nfdInstance.Status.Components, components) &&
+		nfdInstance.Status.ObservedGeneration == observedGeneration &&
+		nfdInstance.Status.LastReconcileError == reconcileError &&
+		!reconcileTimeOutdated {
 		return nil
 	}
 	oldConditions := nfdInstance.Status.Conditions
+	oldComponents := nfdInstance.Status.Components
 	unmodifiedCR := nfdInstance.DeepCopy()
 	nfdInstance.Status.Conditions = conditions
+	nfdInstance.Status.MasterLeader = masterLeader
+	nfdInstance.Status.Components = components
+	nfdInstance.Status.ObservedGeneration = observedGeneration
+	nfdInstance.Status.LastReconcileError = reconcileError
+	if reconcileErr == nil {
+		now := metav1.Now()
+		nfdInstance.Status.LastReconcileTime = &now
+	}
 	if err := nfdh.client.Status().Patch(ctx, nfdInstance, client.MergeFrom(unmodifiedCR)); err != nil {
 		return err
 	}
 	// Only emit the event once the new conditions have actually persisted, so a failed/retried
 	// patch can't cause the event to fire without a corresponding status update (or fire twice).
 	nfdh.recordOperandImagePinnedEvent(nfdInstance, oldConditions, conditions)
+	nfdh.recordPodFailureEvents(nfdInstance, oldComponents, components)
 	return nil
 }
 
+// formatReconcileError turns the joined errors of a reconcile into a stable
+// message: every error on its own line, sorted and deduplicated so that the
+// order of the failing steps does not change the status, and truncated
+// on a rune boundary
+func formatReconcileError(err error) string {
+	if err == nil {
+		return ""
+	}
+	lines := strings.Split(err.Error(), "\n")
+	sort.Strings(lines)
+	lines = slices.Compact(lines)
+	message := strings.TrimSpace(strings.Join(lines, "\n"))
+	return status.TruncateMessage(message, maxReconcileErrorLength)
+}
+
+// recordPodFailureEvents emits a Warning event for each reason the pods of a
+// component started failing for since the previous status, so that the
+// failure is not reported again on every reconcile while it lasts
+func (nfdh *nodeFeatureDiscoveryHelper) recordPodFailureEvents(nfdInstance *nfdv1.NodeFeatureDiscovery, oldComponents, newComponents []nfdv1.ComponentStatus) {
+	if nfdh.recorder == nil {
+		return
+	}
+	oldFailures := map[string]bool{}
+	for _, component := range oldComponents {
+		for _, failure := range component.PodFailures {
+			oldFailures[component.Name+"/"+string(failure.Reason)] = true
+		}
+	}
+	for _, component := range newComponents {
+		for _, failure := range component.PodFailures {
+			if oldFailures[component.Name+"/"+string(failure.Reason)] {
+				continue
+			}
+			message := fmt.Sprintf("%s %s: %d pods %s", component.Kind, component.Name, failure.Count, failure.Reason)
+			if len(failure.Nodes) > 0 {
+				message += fmt.Sprintf(" on %s", strings.Join(failure.Nodes, ", "))
+			}
+			if failure.Message != "" {
+				message += ": " + failure.Message
+			}
+			nfdh.recorder.Event(nfdInstance, corev1.EventTypeWarning, "OperandPodFailure", message)
+		}
+	}
+}
+
 // recordOperandImagePinnedEvent emits a Warning event the first time spec.operand.image becomes
 // pinned, so it shows up in `oc get events -n <namespace>` without needing to inspect
 // status.conditions directly. It only fires on the False->True transition (not every reconcile).
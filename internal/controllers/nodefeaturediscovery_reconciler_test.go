@@ -18,12 +18,18 @@ package new_controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdv1openshiftioalpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
 	"go.uber.org/mock/gomock"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -31,7 +37,10 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -40,8 +49,12 @@ import (
 	"github.com/openshift/cluster-nfd-operator/internal/configmap"
 	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
 	"github.com/openshift/cluster-nfd-operator/internal/deployment"
+	"github.com/openshift/cluster-nfd-operator/internal/featurefile"
 	"github.com/openshift/cluster-nfd-operator/internal/job"
 	"github.com/openshift/cluster-nfd-operator/internal/networkpolicy"
+	"github.com/openshift/cluster-nfd-operator/internal/noderesourcetopology"
+	"github.com/openshift/cluster-nfd-operator/internal/poddisruptionbudget"
+	"github.com/openshift/cluster-nfd-operator/internal/presets"
 	"github.com/openshift/cluster-nfd-operator/internal/scc"
 	"github.com/openshift/cluster-nfd-operator/internal/status"
 )
@@ -74,7 +87,8 @@ var _ = Describe("Reconcile", func() {
 		mockHelper.EXPECT().handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image).Return(nil)
 		mockHelper.EXPECT().handleGC(ctx, &nfdCR, nfdCR.Spec.Operand.Image).Return(nil)
 		mockHelper.EXPECT().handleNetworkPolicies(ctx, &nfdCR).Return(nil)
-		mockHelper.EXPECT().handleStatus(ctx, &nfdCR).Return(nil)
+		mockHelper.EXPECT().handleRulePresets(ctx, &nfdCR).Return(nil)
+		mockHelper.EXPECT().handleStatus(ctx, &nfdCR, nil).Return(nil)
 
 		res, err := nfdr.Reconcile(ctx, &nfdCR)
 		Expect(res).To(Equal(reconcile.Result{}))
@@ -140,6 +154,52 @@ var _ = Describe("Reconcile", func() {
 		Entry("setFinalizer succeeded", fmt.Errorf("set finalizer error")),
 	)
 
+	DescribeTable("unmanaged flow", func(handleStatusError error) {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Unmanaged},
+		}
+
+		mockHelper.EXPECT().hasFinalizer(&nfdCR).Return(true)
+		mockHelper.EXPECT().handleStatus(ctx, &nfdCR, nil).Return(handleStatusError)
+
+		res, err := nfdr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		if handleStatusError != nil {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).To(BeNil())
+		}
+	},
+		Entry("handleStatus failed", fmt.Errorf("status error")),
+		Entry("handleStatus succeeded", nil),
+	)
+
+	DescribeTable("removed flow", func(finalizeComponentsError, handleStatusError error) {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Removed},
+		}
+
+		mockHelper.EXPECT().hasFinalizer(&nfdCR).Return(true)
+		mockHelper.EXPECT().finalizeComponents(ctx, &nfdCR).Return(finalizeComponentsError)
+		reconcileErr := gomock.Nil()
+		if finalizeComponentsError != nil {
+			reconcileErr = gomock.Not(gomock.Nil())
+		}
+		mockHelper.EXPECT().handleStatus(ctx, &nfdCR, reconcileErr).Return(handleStatusError)
+
+		res, err := nfdr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		if finalizeComponentsError != nil || handleStatusError != nil {
+			Expect(err).To(HaveOccurred())
+		} else {
+			Expect(err).To(BeNil())
+		}
+	},
+		Entry("finalizeComponents failed", fmt.Errorf("finalize error"), nil),
+		Entry("handleStatus failed", nil, fmt.Errorf("status error")),
+		Entry("components removed", nil, nil),
+	)
+
 	DescribeTable("check components error flows", func(handlerSCCError,
 		handlerMasterError,
 		handlerWorkerError,
@@ -147,6 +207,7 @@ var _ = Describe("Reconcile", func() {
 		handlerGCError,
 		handleNetworkPoliciesError,
 		handlePruneError,
+		handleRulePresetsError,
 		handleStatusError error) {
 		nfdCR := nfdv1.NodeFeatureDiscovery{}
 
@@ -157,25 +218,32 @@ var _ = Describe("Reconcile", func() {
 		mockHelper.EXPECT().handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image).Return(handleTopologyError)
 		mockHelper.EXPECT().handleGC(ctx, &nfdCR, nfdCR.Spec.Operand.Image).Return(handlerGCError)
 		mockHelper.EXPECT().handleNetworkPolicies(ctx, &nfdCR).Return(handleNetworkPoliciesError)
-		mockHelper.EXPECT().handleStatus(ctx, &nfdCR).Return(handleStatusError)
+		mockHelper.EXPECT().handleRulePresets(ctx, &nfdCR).Return(handleRulePresetsError)
+		componentsFailed := handlerSCCError != nil || handlerMasterError != nil || handlerWorkerError != nil || handleTopologyError != nil ||
+			handlerGCError != nil || handleNetworkPoliciesError != nil || handlePruneError != nil || handleRulePresetsError != nil
+		reconcileErr := gomock.Nil()
+		if componentsFailed {
+			reconcileErr = gomock.Not(gomock.Nil())
+		}
+		mockHelper.EXPECT().handleStatus(ctx, &nfdCR, reconcileErr).Return(handleStatusError)
 
 		res, err := nfdr.Reconcile(ctx, &nfdCR)
 		Expect(res).To(Equal(reconcile.Result{}))
-		if handlerSCCError != nil || handlerMasterError != nil || handlerWorkerError != nil || handleTopologyError != nil ||
-			handlerGCError != nil || handleNetworkPoliciesError != nil || handlePruneError != nil || handleStatusError != nil {
+		if componentsFailed || handleStatusError != nil {
 			Expect(err).To(HaveOccurred())
 		} else {
 			Expect(err).To(BeNil())
 		}
 	},
-		Entry("handleSCCs failed", fmt.Errorf("scc error"), nil, nil, nil, nil, nil, nil, nil),
-		Entry("handleMaster failed", nil, fmt.Errorf("master error"), nil, nil, nil, nil, nil, nil),
-		Entry("handleWorker failed", nil, nil, fmt.Errorf("worker error"), nil, nil, nil, nil, nil),
-		Entry("handleTopology failed", nil, nil, nil, fmt.Errorf("topology error"), nil, nil, nil, nil),
-		Entry("handleGC failed", nil, nil, nil, nil, fmt.Errorf("gc error"), nil, nil, nil),
-		Entry("handleNetworkPolicies failed", nil, nil, nil, nil, nil, fmt.Errorf("networkpolicy error"), nil, nil),
-		Entry("handleStatus failed", nil, nil, nil, nil, nil, nil, nil, fmt.Errorf("status error")),
-		Entry("all components succeeded", nil, nil, nil, nil, nil, nil, nil, nil),
+		Entry("handleSCCs failed", fmt.Errorf("scc error"), nil, nil, nil, nil, nil, nil, nil, nil),
+		Entry("handleMaster failed", nil, fmt.Errorf("master error"), nil, nil, nil, nil, nil, nil, nil),
+		Entry("handleWorker failed", nil, nil, fmt.Errorf("worker error"), nil, nil, nil, nil, nil, nil),
+		Entry("handleTopology failed", nil, nil, nil, fmt.Errorf("topology error"), nil, nil, nil, nil, nil),
+		Entry("handleGC failed", nil, nil, nil, nil, fmt.Errorf("gc error"), nil, nil, nil, nil),
+		Entry("handleNetworkPolicies failed", nil, nil, nil, nil, nil, fmt.Errorf("networkpolicy error"), nil, nil, nil),
+		Entry("handleRulePresets failed", nil, nil, nil, nil, nil, nil, nil, fmt.Errorf("rule presets error"), nil),
+		Entry("handleStatus failed", nil, nil, nil, nil, nil, nil, nil, nil, fmt.Errorf("status error")),
+		Entry("all components succeeded", nil, nil, nil, nil, nil, nil, nil, nil, nil),
 	)
 })
 
@@ -184,6 +252,7 @@ var _ = Describe("handleMaster", func() {
 		ctrl           *gomock.Controller
 		clnt           *client.MockClient
 		mockDeployment *deployment.MockDeploymentAPI
+		mockPDB        *poddisruptionbudget.MockPodDisruptionBudgetAPI
 		nfdh           nodeFeatureDiscoveryHelperAPI
 	)
 
@@ -191,8 +260,9 @@ var _ = Describe("handleMaster", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockDeployment = deployment.NewMockDeploymentAPI(ctrl)
+		mockPDB = poddisruptionbudget.NewMockPodDisruptionBudgetAPI(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, nil, nil, nil, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, nil, nil, nil, nil, nil, mockPDB, nil, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -203,6 +273,7 @@ var _ = Describe("handleMaster", func() {
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
 			mockDeployment.EXPECT().SetMasterDeploymentAsDesired(&nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, nfdCR.Namespace, "nfd-master").Return(nil),
 		)
 
 		err := nfdh.handleMaster(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
@@ -228,6 +299,7 @@ var _ = Describe("handleMaster", func() {
 				},
 			),
 			mockDeployment.EXPECT().SetMasterDeploymentAsDesired(&nfdCR, &existingDeployment, nfdCR.Spec.Operand.Image).Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, nfdCR.Namespace, "nfd-master").Return(nil),
 		)
 
 		err := nfdh.handleMaster(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
@@ -244,15 +316,54 @@ var _ = Describe("handleMaster", func() {
 		err := nfdh.handleMaster(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should create the master pod disruption budget with several replicas", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfd-cr",
+				Namespace: "test-namespace",
+			},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{MasterReplicas: ptr.To[int32](3)},
+			},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDeployment.EXPECT().SetMasterDeploymentAsDesired(&nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, types.NamespacedName{Namespace: nfdCR.Namespace, Name: "nfd-master"}, gomock.Any()).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockPDB.EXPECT().SetMasterPodDisruptionBudgetAsDesired(&nfdCR, gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleMaster(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+	})
+
+	It("error flow, failed to delete the master pod disruption budget", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDeployment.EXPECT().SetMasterDeploymentAsDesired(&nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, nfdCR.Namespace, "nfd-master").Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleMaster(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("handleWorker", func() {
 	var (
-		ctrl   *gomock.Controller
-		clnt   *client.MockClient
-		mockDS *daemonset.MockDaemonsetAPI
-		mockCM *configmap.MockConfigMapAPI
-		nfdh   nodeFeatureDiscoveryHelperAPI
+		ctrl     *gomock.Controller
+		clnt     *client.MockClient
+		mockDS   *daemonset.MockDaemonsetAPI
+		mockCM   *configmap.MockConfigMapAPI
+		mockFF   *featurefile.MockFeatureFileAPI
+		recorder *record.FakeRecorder
+		nfdh     nodeFeatureDiscoveryHelperAPI
 	)
 
 	BeforeEach(func() {
@@ -260,22 +371,33 @@ var _ = Describe("handleWorker", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockDS = daemonset.NewMockDaemonsetAPI(ctrl)
 		mockCM = configmap.NewMockConfigMapAPI(ctrl)
+		mockFF = featurefile.NewMockFeatureFileAPI(ctrl)
+		recorder = record.NewFakeRecorder(10)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, mockDS, mockCM, nil, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, mockDS, mockCM, nil, nil, nil, nil, nil, nil, mockFF, nil, scheme, recorder, true)
 	})
 
 	ctx := context.Background()
 	nfdCR := nfdv1.NodeFeatureDiscovery{}
 
+	noFeatureFiles := func(namespace string) []any {
+		return []any{
+			mockFF.EXPECT().GetNodeFeatureFiles(ctx, namespace).Return(nil, nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-feature-files").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, nodeFilesConfigMaps("nfd-feature-files")).Return(nil).Times(featurefile.NodeFilesBuckets),
+		}
+	}
+
 	It("both configmap and daemonset are missing, they should both be created", func() {
-		gomock.InOrder(
+		gomock.InOrder(append(noFeatureFiles(nfdCR.Namespace),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any()).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(nil),
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any(), nfdCR.Spec.Operand.Image, "").Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
-		)
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)...)
 
 		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
 		Expect(err).To(BeNil())
@@ -297,7 +419,7 @@ var _ = Describe("handleWorker", func() {
 		existingCM := corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{Namespace: nfdCR.Namespace, Name: "nfd-worker"},
 		}
-		gomock.InOrder(
+		gomock.InOrder(append(noFeatureFiles(nfdCR.Namespace),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, cm *corev1.ConfigMap, _ ...ctrlclient.GetOption) error {
 					cm.SetName(existingCM.Name)
@@ -305,7 +427,7 @@ var _ = Describe("handleWorker", func() {
 					return nil
 				},
 			),
-			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, &existingCM).Return(nil),
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any(), &existingCM).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, ds *appsv1.DaemonSet, _ ...ctrlclient.GetOption) error {
 					ds.SetName(existingDS.Name)
@@ -313,30 +435,244 @@ var _ = Describe("handleWorker", func() {
 					return nil
 				},
 			),
-			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &existingDS, nfdCR.Spec.Operand.Image).Return(nil),
-		)
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), &existingDS, nfdCR.Spec.Operand.Image, "").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)...)
 
 		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
 		Expect(err).To(BeNil())
 	})
 
 	It("error flow, failed to populate configmap object", func() {
-		gomock.InOrder(
+		gomock.InOrder(append(noFeatureFiles(nfdCR.Namespace),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(fmt.Errorf("some error")),
-		)
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error")),
+		)...)
 
 		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("error flow, failed to populate daemonset object", func() {
+		gomock.InOrder(append(noFeatureFiles(nfdCR.Namespace),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any(), nfdCR.Spec.Operand.Image, "").Return(fmt.Errorf("some error")),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("worker config references a user ConfigMap, generated configmap is deleted and not reconciled", func() {
+		refNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+						Key:                  "nfd-worker.conf",
+					},
+				},
+			},
+		}
+		gomock.InOrder(append(noFeatureFiles(refNFD.Namespace),
+			mockCM.EXPECT().DeleteConfigMap(ctx, refNFD.Namespace, "nfd-worker").Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &refNFD, gomock.Any(), gomock.Any(), refNFD.Spec.Operand.Image, "").Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &refNFD, refNFD.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+	})
+
+	It("worker config references a user ConfigMap named as the generated one, it is not deleted", func() {
+		refNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "nfd-worker"},
+						Key:                  "nfd-worker.conf",
+					},
+				},
+			},
+		}
+		gomock.InOrder(append(noFeatureFiles(refNFD.Namespace),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &refNFD, gomock.Any(), gomock.Any(), refNFD.Spec.Operand.Image, "").Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &refNFD, refNFD.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+	})
+
+	It("error flow, failed to delete the generated configmap", func() {
+		refNFD := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+						Key:                  "nfd-worker.conf",
+					},
+				},
+			},
+		}
+		gomock.InOrder(append(noFeatureFiles(refNFD.Namespace),
+			mockCM.EXPECT().DeleteConfigMap(ctx, refNFD.Namespace, "nfd-worker").Return(fmt.Errorf("some error")),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &refNFD, refNFD.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("overlapping worker profiles are not reconciled", func() {
+		profilesNFD := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{Name: "all"},
+				},
+			},
+		}
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, nodes *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+				nodes.Items = []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{
+					"pool": "gpu", "node-role.kubernetes.io/worker": "", "kubernetes.io/os": "linux"}}}}
+				return nil
+			},
+		)
+
+		err := nfdh.handleWorker(ctx, &profilesNFD, profilesNFD.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("node gpu-node is selected by profiles gpu, all")))
+	})
+
+	It("worker profiles are reconciled and the stale workers are deleted", func() {
+		profilesNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace", UID: "nfd-uid"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+					{Name: "sriov", NodeSelector: map[string]string{"pool": "sriov"}},
+				},
+			},
+		}
+		controlledBy := func(name string, uid string) appsv1.DaemonSet {
+			return appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: profilesNFD.Namespace,
+				OwnerReferences: []metav1.OwnerReference{{Name: "nfd-cr", UID: types.UID(uid), Controller: ptr.To(true)}}}}
+		}
+		isProfile := func(profile string) gomock.Matcher {
+			return gomock.Cond(func(x any) bool { return x.(*nfdv1.WorkerProfile).Name == profile })
+		}
+		isNamed := func(name string) gomock.Matcher {
+			return gomock.Cond(func(x any) bool { return x.(ctrlclient.Object).GetName() == name })
+		}
+		expectProfile := func(profile string) {
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &profilesNFD, isProfile(profile), gomock.Any()).Return(nil)
+			clnt.EXPECT().Create(ctx, isNamed("nfd-worker-"+profile)).Return(nil)
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &profilesNFD, isProfile(profile), gomock.Any(), profilesNFD.Spec.Operand.Image, "").Return(nil)
+			clnt.EXPECT().Create(ctx, isNamed("nfd-worker-"+profile)).Return(nil)
+		}
 		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(nil),
+		)
+		expectProfile("gpu")
+		expectProfile("sriov")
+		gomock.InOrder(append(noFeatureFiles(profilesNFD.Namespace),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *appsv1.DaemonSetList, _ ...ctrlclient.ListOption) error {
+					list.Items = []appsv1.DaemonSet{
+						controlledBy("nfd-worker-gpu", "nfd-uid"),
+						controlledBy("nfd-worker-removed", "nfd-uid"),
+						controlledBy("nfd-worker-other-instance", "other-uid"),
+					}
+					return nil
+				},
+			),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, profilesNFD.Namespace, "nfd-worker").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, profilesNFD.Namespace, "nfd-worker").Return(nil),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, profilesNFD.Namespace, "nfd-worker-removed").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, profilesNFD.Namespace, "nfd-worker-removed").Return(nil),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &profilesNFD, profilesNFD.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+	})
+
+	It("feature files are stored in a configmap mounted by the workers and the status is updated", func() {
+		nffs := []nfdv1openshiftioalpha1.NodeFeatureFile{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "test-namespace"},
+				Spec: nfdv1openshiftioalpha1.NodeFeatureFileSpec{
+					NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+					Files:        map[string]string{"gpu.features": "gpu.present"},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "test-namespace"},
+				Spec: nfdv1openshiftioalpha1.NodeFeatureFileSpec{
+					NodeSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "pool", Operator: "Unknown"},
+					}},
+					Files: map[string]string{"invalid.features": "invalid"},
+				},
+			},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+		}
+		nodes := []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{"pool": "gpu"}}}}
+		workerNodes := sets.New("gpu-node")
+		expectations := []any{
+			mockFF.EXPECT().GetNodeFeatureFiles(ctx, nfdCR.Namespace).Return(nffs, nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, list *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+					list.Items = nodes
+					return nil
+				},
+			),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
+			mockFF.EXPECT().SetFeatureFilesConfigMapAsDesired(ctx, &nfdCR, nffs, gomock.Any()).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		}
+		for _, nodeFiles := range featurefile.GetNodeFiles(nffs, nodes) {
+			expectations = append(expectations,
+				clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+				mockFF.EXPECT().SetNodeFilesConfigMapAsDesired(ctx, &nfdCR, nodeFiles, gomock.Any()).Return(nil),
+				clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			)
+		}
+		gomock.InOrder(append(expectations,
+			mockFF.EXPECT().GetWorkerNodeNames(ctx, &nfdCR).Return(workerNodes, nil),
+			mockFF.EXPECT().UpdateNodeFeatureFileStatus(ctx, &nffs[0], nodes, workerNodes).Return(nil),
+			mockFF.EXPECT().UpdateNodeFeatureFileStatus(ctx, &nffs[1], nodes, workerNodes).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(fmt.Errorf("some error")),
+			mockCM.EXPECT().SetWorkerConfigMapAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockDS.EXPECT().SetWorkerDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), gomock.Any(), nfdCR.Spec.Operand.Image, "nfd-feature-files").Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil),
+		)...)
+
+		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("invalid node selector of NodeFeatureFile test-namespace/invalid")))
+	})
+
+	It("error flow, failed to delete the feature files configmap", func() {
+		gomock.InOrder(
+			mockFF.EXPECT().GetNodeFeatureFiles(ctx, nfdCR.Namespace).Return(nil, nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, nfdCR.Namespace, "nfd-feature-files").Return(fmt.Errorf("some error")),
 		)
 
 		err := nfdh.handleWorker(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
@@ -344,31 +680,117 @@ var _ = Describe("handleWorker", func() {
 	})
 })
 
+// nodeFilesConfigMaps matches the names of the ConfigMaps of the buckets
+// listing the feature files of the nodes
+func nodeFilesConfigMaps(name string) gomock.Matcher {
+	return gomock.Cond(func(x any) bool { return strings.HasPrefix(x.(string), name+"-nodes-") })
+}
+
+var _ = Describe("workerConfigMapReferrers", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+
+	ctx := context.Background()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "user-worker-config", Namespace: "test-namespace"},
+	}
+
+	It("should enqueue the instances indexed by the configmap", func() {
+		referring := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "referring", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+						Key:                  "nfd-worker.conf",
+					},
+				},
+			},
+		}
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("test-namespace"),
+			ctrlclient.MatchingFields{workerConfigMapRefIndex: "user-worker-config"}).DoAndReturn(
+			func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{referring}
+				return nil
+			},
+		)
+
+		requests := workerConfigMapReferrers(clnt)(ctx, cm)
+		Expect(requests).To(Equal([]reconcile.Request{
+			{NamespacedName: ctrlclient.ObjectKey{Namespace: "test-namespace", Name: "referring"}},
+		}))
+	})
+
+	It("should not enqueue anything when listing the instances fails", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		requests := workerConfigMapReferrers(clnt)(ctx, cm)
+		Expect(requests).To(BeEmpty())
+	})
+})
+
+var _ = Describe("workerConfigMapRefNames", func() {
+	It("should index the configmaps referenced by the instance and its profiles", func() {
+		ref := func(name string) *corev1.ConfigMapKeySelector {
+			return &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "nfd-worker.conf"}
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerConfig: nfdv1.ConfigMap{ConfigMapRef: ref("user-worker-config")},
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "gpu", WorkerConfig: &nfdv1.ConfigMap{ConfigMapRef: ref("gpu-worker-config")}},
+					{Name: "compute"},
+				},
+			},
+		}
+
+		Expect(workerConfigMapRefNames(&nfdCR)).To(ConsistOf("user-worker-config", "gpu-worker-config"))
+		Expect(workerConfigMapRefNames(&nfdv1.NodeFeatureDiscovery{})).To(BeEmpty())
+	})
+})
+
 var _ = Describe("handleTopology", func() {
 	var (
-		ctrl   *gomock.Controller
-		clnt   *client.MockClient
-		mockDS *daemonset.MockDaemonsetAPI
-		nfdh   nodeFeatureDiscoveryHelperAPI
+		ctrl    *gomock.Controller
+		clnt    *client.MockClient
+		mockDS  *daemonset.MockDaemonsetAPI
+		mockCM  *configmap.MockConfigMapAPI
+		mockNRT *noderesourcetopology.MockNodeResourceTopologyAPI
+		nfdh    nodeFeatureDiscoveryHelperAPI
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 		mockDS = daemonset.NewMockDaemonsetAPI(ctrl)
+		mockCM = configmap.NewMockConfigMapAPI(ctrl)
+		mockNRT = noderesourcetopology.NewMockNodeResourceTopologyAPI(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, mockDS, nil, nil, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, mockDS, mockCM, nil, nil, nil, nil, mockNRT, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
 
-	It("should create new nfd-topology daemonset if it does not exist", func() {
+	It("should create the NodeResourceTopology CRD, the topology configmap and daemonset if they do not exist", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{
 			Spec: nfdv1.NodeFeatureDiscoverySpec{
 				TopologyUpdater: true,
 			},
 		}
 		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockNRT.EXPECT().SetCRDAsDesired(gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockCM.EXPECT().SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
 			mockDS.EXPECT().SetTopologyDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(nil),
 			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
@@ -378,7 +800,7 @@ var _ = Describe("handleTopology", func() {
 		Expect(err).To(BeNil())
 	})
 
-	It("topology daemonset exists, no need to create it, update is not executed", func() {
+	It("topology objects exist, no need to create them, update is not executed", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      "nfd-cr",
@@ -392,6 +814,10 @@ var _ = Describe("handleTopology", func() {
 			ObjectMeta: metav1.ObjectMeta{Namespace: nfdCR.Namespace, Name: "nfd-topology-updater"},
 		}
 		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockNRT.EXPECT().SetCRDAsDesired(gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockCM.EXPECT().SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
 				func(_ interface{}, _ interface{}, ds *appsv1.DaemonSet, _ ...ctrlclient.GetOption) error {
 					ds.SetName(existingDS.Name)
@@ -406,6 +832,38 @@ var _ = Describe("handleTopology", func() {
 		Expect(err).To(BeNil())
 	})
 
+	It("error flow, failed to populate the NodeResourceTopology CRD", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				TopologyUpdater: true,
+			},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockNRT.EXPECT().SetCRDAsDesired(gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("error flow, failed to populate configmap object", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				TopologyUpdater: true,
+			},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockNRT.EXPECT().SetCRDAsDesired(gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockCM.EXPECT().SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("error flow, failed to populate daemonset object", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{
 			Spec: nfdv1.NodeFeatureDiscoverySpec{
@@ -413,6 +871,10 @@ var _ = Describe("handleTopology", func() {
 			},
 		}
 		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockNRT.EXPECT().SetCRDAsDesired(gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockCM.EXPECT().SetTopologyUpdaterConfigMapAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
 			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
 			mockDS.EXPECT().SetTopologyDaemonsetAsDesired(ctx, &nfdCR, gomock.Any(), nfdCR.Spec.Operand.Image).Return(fmt.Errorf("some error")),
 		)
@@ -421,12 +883,59 @@ var _ = Describe("handleTopology", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
-	It("if TopologyUpdate not set - nothing to do", func() {
-		nfdCR := nfdv1.NodeFeatureDiscovery{}
+	It("if TopologyUpdate not set - the topology objects and the unused CRD are deleted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace"},
+		}
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(nil),
+			mockNRT.EXPECT().DeleteCRD(ctx).Return(nil),
+		)
+
+		err := nfdh.handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(BeNil())
+	})
+
+	It("if TopologyUpdate not set - the CRD is kept while another instance runs the topology-updater", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", UID: "uid-1"},
+		}
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ interface{}, nfdList *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+					nfdList.Items = []nfdv1.NodeFeatureDiscovery{
+						nfdCR,
+						{
+							ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", UID: "uid-2"},
+							Spec:       nfdv1.NodeFeatureDiscoverySpec{TopologyUpdater: true},
+						},
+					}
+					return nil
+				},
+			),
+		)
 
 		err := nfdh.handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
 		Expect(err).To(BeNil())
 	})
+
+	It("if TopologyUpdate not set - failure to list the instances", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace"},
+		}
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, "test-namespace", "nfd-topology-updater").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleTopology(ctx, &nfdCR, nfdCR.Spec.Operand.Image)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("handleGC", func() {
@@ -442,7 +951,7 @@ var _ = Describe("handleGC", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockDeployment = deployment.NewMockDeploymentAPI(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, nil, nil, nil, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -509,7 +1018,7 @@ var _ = Describe("handleNetworkPolicies", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockNP = networkpolicy.NewMockNetworkPolicyAPI(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, mockNP, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, mockNP, nil, nil, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -575,9 +1084,103 @@ var _ = Describe("handleNetworkPolicies", func() {
 	})
 })
 
+var _ = Describe("handleRulePresets", func() {
+	var (
+		ctrl       *gomock.Controller
+		clnt       *client.MockClient
+		mockPreset *presets.MockPresetAPI
+		recorder   *record.FakeRecorder
+		nfdh       nodeFeatureDiscoveryHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockPreset = presets.NewMockPresetAPI(ctrl)
+		recorder = record.NewFakeRecorder(10)
+
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, mockPreset, scheme, recorder, true)
+	})
+
+	ctx := context.Background()
+	nvidiaGPU, _ := presets.Get("nvidia-gpu")
+
+	It("enabled presets are created, unknown presets are reported and disabled presets are deleted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{RulePresets: []string{"nvidia-gpu", "quantum-accelerator"}},
+		}
+		presetRules := []nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nfd-preset-nvidia-gpu", Namespace: "test-namespace"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "nfd-preset-rdma", Namespace: "test-namespace"}},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Namespace: "test-namespace", Name: "nfd-preset-nvidia-gpu"}, gomock.Any()).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			mockPreset.EXPECT().SetPresetRuleAsDesired(ctx, &nfdCR, nvidiaGPU, gomock.Any()).Return(nil),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+			mockPreset.EXPECT().GetPresetRules(ctx, &nfdCR).Return(presetRules, nil),
+			mockPreset.EXPECT().DeletePresetRule(ctx, "test-namespace", "nfd-preset-rdma").Return(nil),
+		)
+
+		err := nfdh.handleRulePresets(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("UnknownRulePreset")))
+	})
+
+	It("the NodeFeatureRule of an older version of the preset is upgraded", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{RulePresets: []string{"nvidia-gpu"}},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, nfr *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...ctrlclient.GetOption) error {
+					nfr.Annotations = map[string]string{presets.PresetVersionAnnotation: "0"}
+					return nil
+				},
+			),
+			mockPreset.EXPECT().SetPresetRuleAsDesired(ctx, &nfdCR, nvidiaGPU, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, preset *presets.Preset, nfr *nfdk8ssigsiov1alpha1.NodeFeatureRule) error {
+					nfr.Annotations[presets.PresetVersionAnnotation] = fmt.Sprint(preset.Version)
+					return nil
+				},
+			),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockPreset.EXPECT().GetPresetRules(ctx, &nfdCR).Return(nil, nil),
+		)
+
+		err := nfdh.handleRulePresets(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("rule preset nvidia-gpu upgraded from version 0")))
+	})
+
+	It("error flow, failed to list the preset NodeFeatureRules", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		mockPreset.EXPECT().GetPresetRules(ctx, &nfdCR).Return(nil, fmt.Errorf("some error"))
+
+		err := nfdh.handleRulePresets(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("error flow, failed to delete the NodeFeatureRule of a disabled preset", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		presetRules := []nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			{ObjectMeta: metav1.ObjectMeta{Name: "nfd-preset-rdma", Namespace: "test-namespace"}},
+		}
+		gomock.InOrder(
+			mockPreset.EXPECT().GetPresetRules(ctx, &nfdCR).Return(presetRules, nil),
+			mockPreset.EXPECT().DeletePresetRule(ctx, "test-namespace", "nfd-preset-rdma").Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleRulePresets(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("hasFinalizer", func() {
 	It("checking return status whether finalizer set or not", func() {
-		nfdh := newNodeFeatureDiscoveryHelperAPI(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nfdh := newNodeFeatureDiscoveryHelperAPI(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, true)
 
 		By("finalizers was empty")
 		nfdCR := nfdv1.NodeFeatureDiscovery{
@@ -621,7 +1224,7 @@ var _ = Describe("setFinalizer", func() {
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, true)
 	})
 
 	It("checking the return status of setFinalizer function", func() {
@@ -672,6 +1275,9 @@ var _ = Describe("finalizeComponents", func() {
 		mockCM         *configmap.MockConfigMapAPI
 		mockSCC        *scc.MockSccAPI
 		mockNP         *networkpolicy.MockNetworkPolicyAPI
+		mockPDB        *poddisruptionbudget.MockPodDisruptionBudgetAPI
+		mockNRT        *noderesourcetopology.MockNodeResourceTopologyAPI
+		mockPreset     *presets.MockPresetAPI
 		nfdh           nodeFeatureDiscoveryHelperAPI
 	)
 
@@ -683,8 +1289,11 @@ var _ = Describe("finalizeComponents", func() {
 		mockCM = configmap.NewMockConfigMapAPI(ctrl)
 		mockSCC = scc.NewMockSccAPI(ctrl)
 		mockNP = networkpolicy.NewMockNetworkPolicyAPI(ctrl)
+		mockPDB = poddisruptionbudget.NewMockPodDisruptionBudgetAPI(ctrl)
+		mockNRT = noderesourcetopology.NewMockNodeResourceTopologyAPI(ctrl)
+		mockPreset = presets.NewMockPresetAPI(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, mockDS, mockCM, nil, mockSCC, mockNP, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, mockDS, mockCM, nil, mockSCC, mockNP, mockPDB, mockNRT, nil, nil, mockPreset, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -700,8 +1309,10 @@ var _ = Describe("finalizeComponents", func() {
 		deleteWorkerCMError,
 		deleteTopologyDSError,
 		deleteMasterDeploymentError,
+		deleteMasterPDBError,
 		deleteGCDeploymentError,
 		deleteNetworkPolicyError,
+		deletePresetRuleError,
 		deleteWorkerSCCError,
 		deleteTopologySCCError bool) {
 
@@ -715,16 +1326,26 @@ var _ = Describe("finalizeComponents", func() {
 			goto executeTestFunction
 		}
 		mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-worker").Return(nil)
+		mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-feature-files").Return(nil)
+		mockCM.EXPECT().DeleteConfigMap(ctx, namespace, nodeFilesConfigMaps("nfd-feature-files")).Return(nil).Times(featurefile.NodeFilesBuckets)
 		if deleteTopologyDSError {
 			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-topology-updater").Return(fmt.Errorf("some error"))
 			goto executeTestFunction
 		}
 		mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-topology-updater").Return(nil)
+		mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-topology-updater").Return(nil)
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(nil)
+		mockNRT.EXPECT().DeleteCRD(ctx).Return(nil)
 		if deleteMasterDeploymentError {
 			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-master").Return(fmt.Errorf("some error"))
 			goto executeTestFunction
 		}
 		mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-master").Return(nil)
+		if deleteMasterPDBError {
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, namespace, "nfd-master").Return(fmt.Errorf("some error"))
+			goto executeTestFunction
+		}
+		mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, namespace, "nfd-master").Return(nil)
 		if deleteGCDeploymentError {
 			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-gc").Return(fmt.Errorf("some error"))
 			goto executeTestFunction
@@ -737,39 +1358,220 @@ var _ = Describe("finalizeComponents", func() {
 		mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-master").Return(nil)
 		mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-worker").Return(nil)
 		mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-gc").Return(nil)
+		mockPreset.EXPECT().GetPresetRules(ctx, &nfdCR).Return([]nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "nfd-preset-nvidia-gpu"}},
+		}, nil)
+		if deletePresetRuleError {
+			mockPreset.EXPECT().DeletePresetRule(ctx, namespace, "nfd-preset-nvidia-gpu").Return(fmt.Errorf("some error"))
+			goto executeTestFunction
+		}
+		mockPreset.EXPECT().DeletePresetRule(ctx, namespace, "nfd-preset-nvidia-gpu").Return(nil)
 		if deleteWorkerSCCError {
-			mockSCC.EXPECT().DeleteSCC(ctx, "nfd-worker").Return(fmt.Errorf("some error"))
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-worker", "nfd-worker").Return(fmt.Errorf("some error"))
 			goto executeTestFunction
 		}
-		mockSCC.EXPECT().DeleteSCC(ctx, "nfd-worker").Return(nil)
+		mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-worker", "nfd-worker").Return(nil)
 		if deleteTopologySCCError {
-			mockSCC.EXPECT().DeleteSCC(ctx, "nfd-topology-updater").Return(fmt.Errorf("some error"))
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-topology-updater", "nfd-topology-updater").Return(fmt.Errorf("some error"))
 			goto executeTestFunction
 		}
-		mockSCC.EXPECT().DeleteSCC(ctx, "nfd-topology-updater").Return(nil)
+		mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-topology-updater", "nfd-topology-updater").Return(nil)
 
 	executeTestFunction:
 
 		err := nfdh.finalizeComponents(ctx, &nfdCR)
 
 		if deleteGCDeploymentError || deleteWorkerDSError || deleteWorkerCMError ||
-			deleteTopologyDSError || deleteMasterDeploymentError || deleteNetworkPolicyError ||
-			deleteWorkerSCCError || deleteTopologySCCError {
+			deleteTopologyDSError || deleteMasterDeploymentError || deleteMasterPDBError || deleteNetworkPolicyError ||
+			deletePresetRuleError || deleteWorkerSCCError || deleteTopologySCCError {
 			Expect(err).To(HaveOccurred())
 		} else {
 			Expect(err).To(BeNil())
 		}
 	},
-		Entry("delete worker daemonset failed", true, false, false, false, false, false, false, false),
-		Entry("delete worker configmap failed", false, true, false, false, false, false, false, false),
-		Entry("delete topology daemonset failed", false, false, true, false, false, false, false, false),
-		Entry("delete master deployment failed", false, false, false, true, false, false, false, false),
-		Entry("delete gc deployment failed", false, false, false, false, true, false, false, false),
-		Entry("delete network policy failed", false, false, false, false, false, true, false, false),
-		Entry("delete worker scc  failed", false, false, false, false, false, false, true, false),
-		Entry("delete topology scc  failed", false, false, false, false, false, false, false, true),
-		Entry("finalization flow was succesful", false, false, false, false, false, false, false, false),
+		Entry("delete worker daemonset failed", true, false, false, false, false, false, false, false, false, false),
+		Entry("delete worker configmap failed", false, true, false, false, false, false, false, false, false, false),
+		Entry("delete topology daemonset failed", false, false, true, false, false, false, false, false, false, false),
+		Entry("delete master deployment failed", false, false, false, true, false, false, false, false, false, false),
+		Entry("delete master pod disruption budget failed", false, false, false, false, true, false, false, false, false, false),
+		Entry("delete gc deployment failed", false, false, false, false, false, true, false, false, false, false),
+		Entry("delete network policy failed", false, false, false, false, false, false, true, false, false, false),
+		Entry("delete preset rule failed", false, false, false, false, false, false, false, true, false, false),
+		Entry("delete worker scc  failed", false, false, false, false, false, false, false, false, true, false),
+		Entry("delete topology scc  failed", false, false, false, false, false, false, false, false, false, true),
+		Entry("finalization flow was succesful", false, false, false, false, false, false, false, false, false, false),
 	)
+	It("objects suffixed with the instance name are deleted", func() {
+		instanceCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+			},
+		}
+
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-worker-team-a").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-worker-team-a").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-feature-files-team-a").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, nodeFilesConfigMaps("nfd-feature-files-team-a")).Return(nil).Times(featurefile.NodeFilesBuckets),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-topology-updater-team-a").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-topology-updater-team-a").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(nil),
+			mockNRT.EXPECT().DeleteCRD(ctx).Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-master-team-a").Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, namespace, "nfd-master-team-a").Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-gc-team-a").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-master-team-a").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-worker-team-a").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-gc-team-a").Return(nil),
+			mockPreset.EXPECT().GetPresetRules(ctx, &instanceCR).Return(nil, nil),
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-worker-team-a", "nfd-worker").Return(nil),
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-topology-updater-team-a", "nfd-topology-updater").Return(nil),
+		)
+
+		err := nfdh.finalizeComponents(ctx, &instanceCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("worker profile objects are deleted, referenced user configmaps are kept", func() {
+		profilesCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "gpu"},
+					{Name: "sriov", WorkerConfig: &nfdv1.ConfigMap{ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "nfd-worker-sriov"},
+						Key:                  "nfd-worker.conf",
+					}}},
+				},
+			},
+		}
+
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-worker").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-worker").Return(nil),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-worker-gpu").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-worker-gpu").Return(nil),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-worker-sriov").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-feature-files").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, nodeFilesConfigMaps("nfd-feature-files")).Return(nil).Times(featurefile.NodeFilesBuckets),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-topology-updater").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-topology-updater").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(nil),
+			mockNRT.EXPECT().DeleteCRD(ctx).Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-master").Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, namespace, "nfd-master").Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-gc").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-master").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-worker").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-gc").Return(nil),
+			mockPreset.EXPECT().GetPresetRules(ctx, &profilesCR).Return(nil, nil),
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-worker", "nfd-worker").Return(nil),
+			mockSCC.EXPECT().RemoveSCCUser(ctx, gomock.Any(), "nfd-topology-updater", "nfd-topology-updater").Return(nil),
+		)
+
+		err := nfdh.finalizeComponents(ctx, &profilesCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("SCCs are left alone when the SCC API is not available", func() {
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, mockDeployment, mockDS, mockCM, nil, mockSCC, mockNP, mockPDB, mockNRT, nil, nil, mockPreset, scheme, nil, false)
+		instanceCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace},
+		}
+
+		gomock.InOrder(
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-worker").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-worker").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-feature-files").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, nodeFilesConfigMaps("nfd-feature-files")).Return(nil).Times(featurefile.NodeFilesBuckets),
+			mockDS.EXPECT().DeleteDaemonSet(ctx, namespace, "nfd-topology-updater").Return(nil),
+			mockCM.EXPECT().DeleteConfigMap(ctx, namespace, "nfd-topology-updater").Return(nil),
+			clnt.EXPECT().List(ctx, gomock.Any()).Return(nil),
+			mockNRT.EXPECT().DeleteCRD(ctx).Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-master").Return(nil),
+			mockPDB.EXPECT().DeletePodDisruptionBudget(ctx, namespace, "nfd-master").Return(nil),
+			mockDeployment.EXPECT().DeleteDeployment(ctx, namespace, "nfd-gc").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-master").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-worker").Return(nil),
+			mockNP.EXPECT().DeleteNetworkPolicy(ctx, namespace, "nfd-gc").Return(nil),
+			mockPreset.EXPECT().GetPresetRules(ctx, &instanceCR).Return(nil, nil),
+		)
+
+		err := nfdh.finalizeComponents(ctx, &instanceCR)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("handleSCCs", func() {
+	var (
+		ctrl    *gomock.Controller
+		clnt    *client.MockClient
+		mockSCC *scc.MockSccAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mockSCC = scc.NewMockSccAPI(ctrl)
+	})
+
+	ctx := context.Background()
+	nfdCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+	}
+
+	It("worker and topology SCCs are reconciled when the SCC API is available", func() {
+		nfdh := newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, mockSCC, nil, nil, nil, nil, nil, nil, scheme, nil, true)
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockSCC.EXPECT().SetWorkerSCCAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockSCC.EXPECT().SetTopologySCCAsDesired(ctx, &nfdCR, gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleSCCs(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("the namespace is labelled for pod security admission when the SCC API is not available", func() {
+		nfdh := newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, mockSCC, nil, nil, nil, nil, nil, nil, scheme, nil, false)
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Name: "test-namespace"}, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, ns *corev1.Namespace, _ ...ctrlclient.GetOption) error {
+					ns.SetName("test-namespace")
+					return nil
+				},
+			),
+			mockSCC.EXPECT().SetPodSecurityLabelsAsDesired(ctx, &nfdCR, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, ns *corev1.Namespace) error {
+					ns.Labels = map[string]string{"pod-security.kubernetes.io/enforce": "privileged"}
+					return nil
+				},
+			),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleSCCs(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("failure to label the namespace", func() {
+		nfdh := newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, mockSCC, nil, nil, nil, nil, nil, nil, scheme, nil, false)
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockSCC.EXPECT().SetPodSecurityLabelsAsDesired(ctx, &nfdCR, gomock.Any()).DoAndReturn(
+				func(_ interface{}, _ interface{}, ns *corev1.Namespace) error {
+					ns.Labels = map[string]string{"pod-security.kubernetes.io/enforce": "privileged"}
+					return nil
+				},
+			),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := nfdh.handleSCCs(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("removeFinalizer", func() {
@@ -783,7 +1585,7 @@ var _ = Describe("removeFinalizer", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
 
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -827,7 +1629,7 @@ var _ = Describe("handlePrune", func() {
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockJob = job.NewMockJobAPI(ctrl)
-		nfdh = newNodeFeatureDiscoveryHelperAPI(nil, nil, nil, nil, mockJob, nil, nil, nil, scheme, nil)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(nil, nil, nil, nil, mockJob, nil, nil, nil, nil, nil, nil, nil, scheme, nil, true)
 	})
 
 	ctx := context.Background()
@@ -923,7 +1725,7 @@ var _ = Describe("handleStatus", func() {
 		clnt = client.NewMockClient(ctrl)
 		mockStatus = status.NewMockStatusAPI(ctrl)
 		recorder = record.NewFakeRecorder(10)
-		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, mockStatus, scheme, recorder)
+		nfdh = newNodeFeatureDiscoveryHelperAPI(clnt, nil, nil, nil, nil, nil, nil, nil, nil, mockStatus, nil, nil, scheme, recorder, true)
 	})
 
 	ctx := context.Background()
@@ -934,16 +1736,55 @@ var _ = Describe("handleStatus", func() {
 	}
 	newConditions := []metav1.Condition{}
 
+	// patchedStatus matches the instance patched with the expected status,
+	// which records the time of the successful reconcile
+	patchedStatus := func(expected *nfdv1.NodeFeatureDiscovery) gomock.Matcher {
+		return gomock.Cond(func(x any) bool {
+			patched := x.(*nfdv1.NodeFeatureDiscovery).DeepCopy()
+			if expected.Status.LastReconcileError == "" && patched.Status.LastReconcileTime == nil {
+				return false
+			}
+			patched.Status.LastReconcileTime = expected.Status.LastReconcileTime
+			return reflect.DeepEqual(patched, expected)
+		})
+	}
+
 	It("conditions are equal, no status update is needed", func() {
+		lastReconcileTime := metav1.NewTime(time.Now().Add(-time.Minute))
+		reconciledNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{Conditions: newConditions, LastReconcileTime: &lastReconcileTime},
+		}
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &nfdCR).Return(newConditions),
-			mockStatus.EXPECT().AreConditionsEqual(nfdCR.Status.Conditions, newConditions).Return(true),
+			mockStatus.EXPECT().GetComponents(ctx, &reconciledNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &reconciledNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &reconciledNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(reconciledNFD.Status.Conditions, newConditions).Return(true),
 		)
 
-		err := nfdh.handleStatus(ctx, &nfdCR)
+		err := nfdh.handleStatus(ctx, &reconciledNFD, nil)
 		Expect(err).To(BeNil())
 	})
 
+	It("an outdated time of the last successful reconcile is updated even if nothing else changed", func() {
+		lastReconcileTime := metav1.NewTime(time.Now().Add(-reconcileTimeRefreshInterval))
+		reconciledNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{Conditions: newConditions, LastReconcileTime: &lastReconcileTime},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &reconciledNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &reconciledNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &reconciledNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(reconciledNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &reconciledNFD, gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &reconciledNFD, nil)
+		Expect(err).To(BeNil())
+		Expect(reconciledNFD.Status.LastReconcileTime.After(lastReconcileTime.Time)).To(BeTrue())
+	})
+
 	It("conditions are not equal, status update is needed", func() {
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		expectedNFD := nfdv1.NodeFeatureDiscovery{
@@ -952,14 +1793,73 @@ var _ = Describe("handleStatus", func() {
 			},
 		}
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &nfdCR).Return(newConditions),
+			mockStatus.EXPECT().GetComponents(ctx, &nfdCR).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &nfdCR, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &nfdCR).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(nfdCR.Status.Conditions, newConditions).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &nfdCR, nil)
+		Expect(err).To(BeNil())
+	})
+
+	It("conditions are equal, the master leader changed, status update is needed", func() {
+		leaderNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions:   []metav1.Condition{},
+				MasterLeader: "nfd-master-7d9f-abcde",
+			},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		expectedNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions:   newConditions,
+				MasterLeader: "nfd-master-7d9f-fghij",
+			},
+		}
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &leaderNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &leaderNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &leaderNFD).Return("nfd-master-7d9f-fghij"),
+			mockStatus.EXPECT().AreConditionsEqual(leaderNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
 		)
 
-		err := nfdh.handleStatus(ctx, &nfdCR)
+		err := nfdh.handleStatus(ctx, &leaderNFD, nil)
 		Expect(err).To(BeNil())
+		Expect(leaderNFD.Status.MasterLeader).To(Equal("nfd-master-7d9f-fghij"))
+	})
+
+	It("conditions are equal, the components changed, status update is needed", func() {
+		componentsNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions: []metav1.Condition{},
+				Components: []nfdv1.ComponentStatus{{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentAvailable, Desired: 2, Ready: 2}},
+			},
+		}
+		components := []nfdv1.ComponentStatus{{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentAvailable, Desired: 3, Ready: 3}}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		expectedNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions: newConditions,
+				Components: components,
+			},
+		}
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &componentsNFD).Return(components),
+			mockStatus.EXPECT().GetConditions(ctx, &componentsNFD, components).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &componentsNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(componentsNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &componentsNFD, nil)
+		Expect(err).To(BeNil())
+		Expect(componentsNFD.Status.Components).To(Equal(components))
 	})
 
 	It("conditions are not equal, status update failed", func() {
@@ -970,13 +1870,15 @@ var _ = Describe("handleStatus", func() {
 			},
 		}
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &nfdCR).Return(newConditions),
+			mockStatus.EXPECT().GetComponents(ctx, &nfdCR).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &nfdCR, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &nfdCR).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(nfdCR.Status.Conditions, newConditions).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(fmt.Errorf("some error")),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(fmt.Errorf("some error")),
 		)
 
-		err := nfdh.handleStatus(ctx, &nfdCR)
+		err := nfdh.handleStatus(ctx, &nfdCR, nil)
 		Expect(err).To(HaveOccurred())
 	})
 
@@ -992,13 +1894,15 @@ var _ = Describe("handleStatus", func() {
 		}
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD).Return(newConds),
+			mockStatus.EXPECT().GetComponents(ctx, &pinnedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD, nil).Return(newConds),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &pinnedNFD).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(pinnedNFD.Status.Conditions, newConds).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
 		)
 
-		err := nfdh.handleStatus(ctx, &pinnedNFD)
+		err := nfdh.handleStatus(ctx, &pinnedNFD, nil)
 		Expect(err).To(BeNil())
 
 		Eventually(recorder.Events).Should(Receive(ContainSubstring("pinned to some-image")))
@@ -1016,31 +1920,37 @@ var _ = Describe("handleStatus", func() {
 		}
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD).Return(newConds),
+			mockStatus.EXPECT().GetComponents(ctx, &pinnedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD, nil).Return(newConds),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &pinnedNFD).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(pinnedNFD.Status.Conditions, newConds).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(fmt.Errorf("some error")),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(fmt.Errorf("some error")),
 		)
 
-		err := nfdh.handleStatus(ctx, &pinnedNFD)
+		err := nfdh.handleStatus(ctx, &pinnedNFD, nil)
 		Expect(err).To(HaveOccurred())
 
 		Consistently(recorder.Events).ShouldNot(Receive())
 	})
 
 	It("does not re-emit the event once OperandImagePinned is already True", func() {
+		lastReconcileTime := metav1.Now()
 		alreadyPinnedNFD := nfdv1.NodeFeatureDiscovery{
 			Status: nfdv1.NodeFeatureDiscoveryStatus{
 				Conditions: []metav1.Condition{
 					{Type: status.ConditionOperandImagePinned, Status: metav1.ConditionTrue, Reason: "OperandImageSetExplicitly", Message: "pinned to some-image"},
 				},
+				LastReconcileTime: &lastReconcileTime,
 			},
 		}
 		newConds := alreadyPinnedNFD.Status.Conditions
-		mockStatus.EXPECT().GetConditions(ctx, &alreadyPinnedNFD).Return(newConds)
+		mockStatus.EXPECT().GetComponents(ctx, &alreadyPinnedNFD).Return(nil)
+		mockStatus.EXPECT().GetConditions(ctx, &alreadyPinnedNFD, nil).Return(newConds)
+		mockStatus.EXPECT().GetMasterLeader(ctx, &alreadyPinnedNFD).Return("")
 		mockStatus.EXPECT().AreConditionsEqual(alreadyPinnedNFD.Status.Conditions, newConds).Return(true)
 
-		err := nfdh.handleStatus(ctx, &alreadyPinnedNFD)
+		err := nfdh.handleStatus(ctx, &alreadyPinnedNFD, nil)
 		Expect(err).To(BeNil())
 
 		Consistently(recorder.Events).ShouldNot(Receive())
@@ -1062,13 +1972,15 @@ var _ = Describe("handleStatus", func() {
 		}
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &alreadyPinnedNFD).Return(newConds),
+			mockStatus.EXPECT().GetComponents(ctx, &alreadyPinnedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &alreadyPinnedNFD, nil).Return(newConds),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &alreadyPinnedNFD).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(alreadyPinnedNFD.Status.Conditions, newConds).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
 		)
 
-		err := nfdh.handleStatus(ctx, &alreadyPinnedNFD)
+		err := nfdh.handleStatus(ctx, &alreadyPinnedNFD, nil)
 		Expect(err).To(BeNil())
 
 		Consistently(recorder.Events).ShouldNot(Receive())
@@ -1090,13 +2002,15 @@ var _ = Describe("handleStatus", func() {
 		}
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &previouslyPinnedNFD).Return(newConds),
+			mockStatus.EXPECT().GetComponents(ctx, &previouslyPinnedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &previouslyPinnedNFD, nil).Return(newConds),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &previouslyPinnedNFD).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(previouslyPinnedNFD.Status.Conditions, newConds).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
 		)
 
-		err := nfdh.handleStatus(ctx, &previouslyPinnedNFD)
+		err := nfdh.handleStatus(ctx, &previouslyPinnedNFD, nil)
 		Expect(err).To(BeNil())
 
 		Consistently(recorder.Events).ShouldNot(Receive())
@@ -1112,17 +2026,152 @@ var _ = Describe("handleStatus", func() {
 		}
 		statusWriter := client.NewMockStatusWriter(ctrl)
 		gomock.InOrder(
-			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD).Return(newConds),
+			mockStatus.EXPECT().GetComponents(ctx, &pinnedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &pinnedNFD, nil).Return(newConds),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &pinnedNFD).Return(""),
 			mockStatus.EXPECT().AreConditionsEqual(pinnedNFD.Status.Conditions, newConds).Return(false),
 			clnt.EXPECT().Status().Return(statusWriter),
-			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
 		)
 
-		err := nfdh.handleStatus(ctx, &pinnedNFD)
+		err := nfdh.handleStatus(ctx, &pinnedNFD, nil)
 		Expect(err).To(BeNil())
 
 		Eventually(recorder.Events).Should(Receive(ContainSubstring("pinned to some-image")))
 	})
+
+	It("emits a Warning event only for the new pod failures of the components", func() {
+		crashLoop := nfdv1.PodFailure{Reason: nfdv1.PodCrashLoopBackOff, Count: 2, Nodes: []string{"worker-0", "worker-1"}, Message: "back-off 5m0s restarting failed container"}
+		oomKilled := nfdv1.PodFailure{Reason: nfdv1.PodOOMKilled, Count: 1, Nodes: []string{"worker-2"}}
+		failingNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Components: []nfdv1.ComponentStatus{
+					{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded, PodFailures: []nfdv1.PodFailure{crashLoop}},
+				},
+			},
+		}
+		components := []nfdv1.ComponentStatus{
+			{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded, PodFailures: []nfdv1.PodFailure{crashLoop, oomKilled}},
+		}
+		expectedNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions: newConditions,
+				Components: components,
+			},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &failingNFD).Return(components),
+			mockStatus.EXPECT().GetConditions(ctx, &failingNFD, components).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &failingNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(failingNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &failingNFD, nil)
+		Expect(err).To(BeNil())
+
+		Eventually(recorder.Events).Should(Receive(Equal("Warning OperandPodFailure DaemonSet nfd-worker: 1 pods OOMKilled on worker-2")))
+		Consistently(recorder.Events).ShouldNot(Receive())
+	})
+
+	It("a new generation of the spec is recorded even if nothing else changed", func() {
+		generationNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status:     nfdv1.NodeFeatureDiscoveryStatus{ObservedGeneration: 1},
+		}
+		expectedNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status:     nfdv1.NodeFeatureDiscoveryStatus{Conditions: newConditions, ObservedGeneration: 2},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &generationNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &generationNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &generationNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(generationNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, patchedStatus(&expectedNFD), gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &generationNFD, nil)
+		Expect(err).To(BeNil())
+		Expect(generationNFD.Status.ObservedGeneration).To(Equal(int64(2)))
+		Expect(generationNFD.Status.LastReconcileTime).ToNot(BeNil())
+	})
+
+	It("the generation of the spec is not recorded while the instance is Unmanaged", func() {
+		lastReconcileTime := metav1.Now()
+		unmanagedNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Unmanaged},
+			Status:     nfdv1.NodeFeatureDiscoveryStatus{ObservedGeneration: 1, LastReconcileTime: &lastReconcileTime},
+		}
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &unmanagedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &unmanagedNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &unmanagedNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(unmanagedNFD.Status.Conditions, newConditions).Return(true),
+		)
+
+		err := nfdh.handleStatus(ctx, &unmanagedNFD, nil)
+		Expect(err).To(BeNil())
+		Expect(unmanagedNFD.Status.ObservedGeneration).To(Equal(int64(1)))
+	})
+
+	It("the reconcile error is published without updating the time of the last successful reconcile", func() {
+		lastReconcileTime := metav1.NewTime(time.Now().Add(-time.Hour))
+		failedNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{LastReconcileTime: &lastReconcileTime},
+		}
+		reconcileErr := errors.Join(fmt.Errorf("worker error"), nil, fmt.Errorf("master error"))
+		expectedNFD := nfdv1.NodeFeatureDiscovery{
+			Status: nfdv1.NodeFeatureDiscoveryStatus{
+				Conditions:         newConditions,
+				LastReconcileTime:  &lastReconcileTime,
+				LastReconcileError: "master error\nworker error",
+			},
+		}
+		statusWriter := client.NewMockStatusWriter(ctrl)
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &failedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &failedNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &failedNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(failedNFD.Status.Conditions, newConditions).Return(true),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &expectedNFD, gomock.Any()).Return(nil),
+		)
+
+		err := nfdh.handleStatus(ctx, &failedNFD, reconcileErr)
+		Expect(err).To(BeNil())
+
+		By("the same error does not update the status again")
+		gomock.InOrder(
+			mockStatus.EXPECT().GetComponents(ctx, &failedNFD).Return(nil),
+			mockStatus.EXPECT().GetConditions(ctx, &failedNFD, nil).Return(newConditions),
+			mockStatus.EXPECT().GetMasterLeader(ctx, &failedNFD).Return(""),
+			mockStatus.EXPECT().AreConditionsEqual(failedNFD.Status.Conditions, newConditions).Return(true),
+		)
+
+		err = nfdh.handleStatus(ctx, &failedNFD, errors.Join(fmt.Errorf("master error"), fmt.Errorf("worker error")))
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("formatReconcileError", func() {
+	DescribeTable("errors are reported in a stable form", func(err error, expected string) {
+		Expect(formatReconcileError(err)).To(Equal(expected))
+	},
+		Entry("no error", nil, ""),
+		Entry("single error", fmt.Errorf("scc error"), "scc error"),
+		Entry("errors are sorted and deduplicated",
+			errors.Join(fmt.Errorf("worker error"), fmt.Errorf("gc error"), fmt.Errorf("worker error")), "gc error\nworker error"),
+		Entry("long errors are truncated",
+			fmt.Errorf("%s", strings.Repeat("x", maxReconcileErrorLength+1)), strings.Repeat("x", maxReconcileErrorLength)+"..."),
+		Entry("long errors are truncated on a rune boundary",
+			fmt.Errorf("%s", strings.Repeat("x", maxReconcileErrorLength-1)+"é"), strings.Repeat("x", maxReconcileErrorLength-1)+"..."),
+	)
 })
 
 var _ = Describe("getOperandImage", Ordered, func() {
@@ -0,0 +1,115 @@
+/*
+Copyright 2021. The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+// nodeFeatureGroupReconciler mirrors the NodeFeatureGroups of nfd.openshift.io
+// group into nfd.k8s-sigs.io group, which nfd-master evaluates, and reports
+// back the nodes of the group found by nfd-master
+type nodeFeatureGroupReconciler struct {
+	client         client.Client
+	scheme         *runtime.Scheme
+	recorder       record.EventRecorder
+	adoptionPolicy MirrorAdoptionPolicy
+}
+
+func NewNodeFeatureGroupReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder,
+	adoptionPolicy MirrorAdoptionPolicy) *nodeFeatureGroupReconciler {
+	return &nodeFeatureGroupReconciler{
+		client:         client,
+		scheme:         scheme,
+		recorder:       recorder,
+		adoptionPolicy: adoptionPolicy,
+	}
+}
+
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturegroups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturegroups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturegroups/finalizers,verbs=update
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturegroups,verbs=get;list;watch;create;update;patch;delete
+
+func (r *nodeFeatureGroupReconciler) Reconcile(ctx context.Context, nfg *nfdopenshiftiov1alpha1.NodeFeatureGroup) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling NodeFeatureGroup from nfd.openshift.io group", "name", nfg.Name)
+
+	target, result, err := createOrPatchMirror(ctx, r.client, r.scheme, nfg, r.adoptionPolicy)
+	if errors.Is(err, errMirrorConflict) {
+		recordMirrorConflict(ctx, r.recorder, nfg, "NodeFeatureGroup", r.adoptionPolicy)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		logger.Error(err, "Failed to create or update NodeFeatureGroup in nfd.k8s-sigs.io group")
+		return ctrl.Result{}, err
+	}
+	recordMirrorResult(ctx, r.recorder, nfg, "NodeFeatureGroup", result)
+
+	mirror := &nfdk8ssigsiov1alpha1.NodeFeatureGroup{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(target.UnstructuredContent(), mirror); err != nil {
+		logger.Error(err, "Failed to convert NodeFeatureGroup in nfd.k8s-sigs.io group")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.handleStatus(ctx, nfg, mirror); err != nil {
+		logger.Error(err, "Failed to update the status of NodeFeatureGroup")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// handleStatus copies the nodes of the group, found by nfd-master and reported
+// in the status of the mirror, to the status of the NodeFeatureGroup
+func (r *nodeFeatureGroupReconciler) handleStatus(ctx context.Context, nfg *nfdopenshiftiov1alpha1.NodeFeatureGroup,
+	mirror *nfdk8ssigsiov1alpha1.NodeFeatureGroup) error {
+	if equality.Semantic.DeepEqual(nfg.Status, mirror.Status) {
+		return nil
+	}
+
+	unmodified := nfg.DeepCopy()
+	nfg.Status = *mirror.Status.DeepCopy()
+	if err := r.client.Status().Patch(ctx, nfg, client.MergeFrom(unmodified)); err != nil {
+		return fmt.Errorf("failed to patch the status of NodeFeatureGroup %s/%s: %w", nfg.Namespace, nfg.Name, err)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *nodeFeatureGroupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the status of the mirrors, updated by nfd-master, is watched as well
+	// as their spec, so that the nodes of the group are reported back
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&nfdk8ssigsiov1alpha1.NodeFeatureGroup{}).
+		Complete(reconcile.AsReconciler[*nfdopenshiftiov1alpha1.NodeFeatureGroup](mgr.GetClient(), r))
+}
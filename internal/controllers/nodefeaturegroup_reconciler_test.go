@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	nfdv1openshiftioalpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	clt "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("NodeFeatureGroup Reconcile", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		recorder     *record.FakeRecorder
+		nfgr         *nodeFeatureGroupReconciler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		recorder = record.NewFakeRecorder(10)
+
+		nfgr = NewNodeFeatureGroupReconciler(clnt, scheme, recorder, MirrorAdoptionPolicyRefuse)
+	})
+	ctx := context.Background()
+
+	newSource := func() nfdv1openshiftioalpha1.NodeFeatureGroup {
+		return nfdv1openshiftioalpha1.NodeFeatureGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "source-uid", Generation: 1},
+			Spec: nfdv1openshiftioalpha1.NodeFeatureGroupSpec{
+				Rules: []nfdv1openshiftioalpha1.GroupRule{{Name: "test"}},
+			},
+		}
+	}
+
+	newMirror := func(source *nfdv1openshiftioalpha1.NodeFeatureGroup, nodes ...string) nfdk8ssigsiov1alpha1.NodeFeatureGroup {
+		mirror := nfdk8ssigsiov1alpha1.NodeFeatureGroup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-name",
+				Namespace:       "test-namespace",
+				ResourceVersion: "1",
+				Annotations:     map[string]string{sourceGenerationAnnotation: "1"},
+			},
+			Spec: source.Spec,
+		}
+		Expect(controllerutil.SetControllerReference(source, &mirror, scheme)).To(Succeed())
+		for _, node := range nodes {
+			mirror.Status.Nodes = append(mirror.Status.Nodes, nfdv1openshiftioalpha1.FeatureGroupNode{Name: node})
+		}
+		return mirror
+	}
+
+	It("Create NodeFeatureGroup successfully", func() {
+		nfg := newSource()
+		gomock.InOrder(
+			getObject(ctx, clnt, &nfg),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj clt.Object, _ ...clt.CreateOption) error {
+					Expect(obj.GetObjectKind().GroupVersionKind()).To(Equal(nfdk8ssigsiov1alpha1.GroupVersion.WithKind("NodeFeatureGroup")))
+					Expect(metav1.IsControlledBy(obj, &nfg)).To(BeTrue())
+					return nil
+				}),
+		)
+
+		res, err := nfgr.Reconcile(ctx, &nfg)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorCreated")))
+	})
+
+	It("Nodes found by nfd-master are reported in the status", func() {
+		nfg := newSource()
+		mirror := newMirror(&nfg, "worker-0", "worker-1")
+		gomock.InOrder(
+			getObject(ctx, clnt, &nfg),
+			getObject(ctx, clnt, &mirror),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdv1openshiftioalpha1.NodeFeatureGroup{}), gomock.Any()).Return(nil),
+		)
+
+		res, err := nfgr.Reconcile(ctx, &nfg)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(nfg.Status).To(Equal(mirror.Status))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("Status is not patched when it did not change", func() {
+		nfg := newSource()
+		mirror := newMirror(&nfg, "worker-0")
+		nfg.Status = mirror.Status
+		gomock.InOrder(
+			getObject(ctx, clnt, &nfg),
+			getObject(ctx, clnt, &mirror),
+		)
+
+		res, err := nfgr.Reconcile(ctx, &nfg)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+	})
+
+	It("Existing NodeFeatureGroup not owned by the operator is left untouched", func() {
+		nfg := newSource()
+		other := newSource()
+		other.UID = "other-uid"
+		mirror := newMirror(&other)
+		gomock.InOrder(
+			getObject(ctx, clnt, &nfg),
+			getObject(ctx, clnt, &mirror),
+		)
+
+		res, err := nfgr.Reconcile(ctx, &nfg)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorConflict")))
+	})
+
+	It("Fail to get NodeFeatureGroup", func() {
+		nfg := newSource()
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		res, err := nfgr.Reconcile(ctx, &nfg)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(HaveOccurred())
+	})
+})
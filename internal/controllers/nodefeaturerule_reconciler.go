@@ -18,34 +18,61 @@ package new_controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
 	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/migration"
+	"github.com/openshift/cluster-nfd-operator/internal/rulestatus"
 )
 
 // NodeFeatureRuleReconciler reconciles a NodeFeatureRule object
 type nodeFeatureRuleReconciler struct {
-	client client.Client
-	scheme *runtime.Scheme
+	client         client.Client
+	scheme         *runtime.Scheme
+	ruleStatusAPI  rulestatus.RuleStatusAPI
+	migrationAPI   migration.MigrationAPI
+	recorder       record.EventRecorder
+	adoptionPolicy MirrorAdoptionPolicy
 }
 
-func NewNodeFeatureRuleReconciler(client client.Client, scheme *runtime.Scheme) *nodeFeatureRuleReconciler {
+func NewNodeFeatureRuleReconciler(client client.Client, scheme *runtime.Scheme, ruleStatusAPI rulestatus.RuleStatusAPI,
+	migrationAPI migration.MigrationAPI, recorder record.EventRecorder, adoptionPolicy MirrorAdoptionPolicy) *nodeFeatureRuleReconciler {
 	return &nodeFeatureRuleReconciler{
-		client: client,
-		scheme: scheme,
+		client:         client,
+		scheme:         scheme,
+		ruleStatusAPI:  ruleStatusAPI,
+		migrationAPI:   migrationAPI,
+		recorder:       recorder,
+		adoptionPolicy: adoptionPolicy,
 	}
 }
 
-// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturerules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturerules,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturerules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeaturerules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeatures,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -59,40 +86,167 @@ func (r *nodeFeatureRuleReconciler) Reconcile(ctx context.Context, nfr *nfdopens
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling NodeFeatureRule from nfd.openshift.io group", "name", nfr.Name)
 
-	target := &nfdk8ssigsiov1alpha1.NodeFeatureRule{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      nfr.Name,
-			Namespace: nfr.Namespace,
-		},
-	}
-
-	result, err := controllerutil.CreateOrPatch(ctx, r.client, target, func() error {
-		if err := controllerutil.SetControllerReference(nfr, target, r.scheme); err != nil {
-			return err
+	if migration.IsMigrationRequested(nfr) {
+		if err := r.handleMigration(ctx, nfr); err != nil {
+			logger.Error(err, "Failed to migrate NodeFeatureRule to nfd.k8s-sigs.io group")
+			return ctrl.Result{}, err
 		}
-		target.Spec = nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
-			Rules: nfr.Spec.Rules,
-		}
-		return nil
-	})
+		return ctrl.Result{}, nil
+	}
 
+	target, result, err := createOrPatchMirror(ctx, r.client, r.scheme, nfr, r.adoptionPolicy)
+	if errors.Is(err, errMirrorConflict) {
+		recordMirrorConflict(ctx, r.recorder, nfr, "NodeFeatureRule", r.adoptionPolicy)
+		return ctrl.Result{}, nil
+	}
 	if err != nil {
 		logger.Error(err, "Failed to create or update NodeFeatureRule in nfd.k8s-sigs.io group")
 		return ctrl.Result{}, err
 	}
+	recordMirrorResult(ctx, r.recorder, nfr, "NodeFeatureRule", result)
 
-	switch result {
-	case controllerutil.OperationResultCreated:
-		logger.Info("Successfully created NodeFeatureRule in nfd.k8s-sigs.io group")
-	case controllerutil.OperationResultUpdated:
-		logger.Info("Successfully updated NodeFeatureRule in nfd.k8s-sigs.io group")
+	mirror := &nfdk8ssigsiov1alpha1.NodeFeatureRule{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(target.UnstructuredContent(), mirror); err != nil {
+		logger.Error(err, "Failed to convert NodeFeatureRule in nfd.k8s-sigs.io group")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.handleStatus(ctx, nfr, mirror); err != nil {
+		logger.Error(err, "Failed to update the status of NodeFeatureRule")
+		return ctrl.Result{}, err
 	}
 	return ctrl.Result{}, nil
 }
 
+// handleMigration migrates the NodeFeatureRule to a standalone object of
+// nfd.k8s-sigs.io group, which is not mirrored anymore, and records it in the
+// migration report
+func (r *nodeFeatureRuleReconciler) handleMigration(ctx context.Context, nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) error {
+	record, err := r.migrationAPI.Migrate(ctx, nfr)
+	if err != nil {
+		return err
+	}
+
+	switch record.Action {
+	case migration.ActionNone:
+		return nil
+	case migration.ActionConflict:
+		r.recorder.Eventf(nfr, corev1.EventTypeWarning, "MigrationConflict",
+			"NodeFeatureRule %s/%s in nfd.k8s-sigs.io group already exists and does not mirror this NodeFeatureRule",
+			nfr.Namespace, nfr.Name)
+	default:
+		r.recorder.Event(nfr, corev1.EventTypeNormal, "Migrated",
+			"Migrated to a standalone NodeFeatureRule in nfd.k8s-sigs.io group, this NodeFeatureRule can be deleted")
+	}
+	return r.migrationAPI.WriteReport(ctx, []migration.Record{record}, false)
+}
+
+// handleStatus evaluates the rules on the nodes and reports the result in the
+// status of both the NodeFeatureRule and its mirror in nfd.k8s-sigs.io group.
+// A Warning event is emitted on the NodeFeatureRule when its conflicts change
+func (r *nodeFeatureRuleReconciler) handleStatus(ctx context.Context, nfr *nfdopenshiftiov1alpha1.NodeFeatureRule,
+	mirror *nfdk8ssigsiov1alpha1.NodeFeatureRule) error {
+	evaluated, err := r.ruleStatusAPI.GetRuleStatus(ctx, nfr)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate the rules: %w", err)
+	}
+
+	desired := getDesiredRuleStatus(&nfr.Status, evaluated, nfr.Generation)
+	if !equality.Semantic.DeepEqual(nfr.Status, desired) {
+		unmodified := nfr.DeepCopy()
+		nfr.Status = desired
+		if err := r.client.Status().Patch(ctx, nfr, client.MergeFrom(unmodified)); err != nil {
+			return fmt.Errorf("failed to patch the status of NodeFeatureRule %s/%s: %w", nfr.Namespace, nfr.Name, err)
+		}
+		r.recordConflictEvent(nfr, unmodified.Status.Conditions)
+	}
+
+	desired = getDesiredRuleStatus(&mirror.Status, evaluated, mirror.Generation)
+	if !equality.Semantic.DeepEqual(mirror.Status, desired) {
+		unmodified := mirror.DeepCopy()
+		mirror.Status = desired
+		if err := r.client.Status().Patch(ctx, mirror, client.MergeFrom(unmodified)); err != nil {
+			return fmt.Errorf("failed to patch the status of NodeFeatureRule %s/%s in nfd.k8s-sigs.io group: %w",
+				mirror.Namespace, mirror.Name, err)
+		}
+	}
+	return nil
+}
+
+// recordConflictEvent emits a Warning event listing the conflicts of the
+// Conflicting condition when the NodeFeatureRule starts conflicting with other
+// NodeFeatureRules or its conflicts change, rather than on every reconcile
+func (r *nodeFeatureRuleReconciler) recordConflictEvent(nfr *nfdopenshiftiov1alpha1.NodeFeatureRule, oldConditions []metav1.Condition) {
+	conflicting := meta.FindStatusCondition(nfr.Status.Conditions, rulestatus.ConditionConflicting)
+	if conflicting == nil || conflicting.Status != metav1.ConditionTrue {
+		return
+	}
+	old := meta.FindStatusCondition(oldConditions, rulestatus.ConditionConflicting)
+	if old != nil && old.Status == metav1.ConditionTrue && old.Message == conflicting.Message {
+		return
+	}
+	r.recorder.Event(nfr, corev1.EventTypeWarning, "NodeFeatureRuleConflict", conflicting.Message)
+}
+
+// getDesiredRuleStatus returns the evaluated status, keeping the transition
+// time of the conditions of the current status that did not change
+func getDesiredRuleStatus(current, evaluated *nfdopenshiftiov1alpha1.NodeFeatureRuleStatus,
+	generation int64) nfdopenshiftiov1alpha1.NodeFeatureRuleStatus {
+	desired := *evaluated.DeepCopy()
+	desired.Conditions = append([]metav1.Condition(nil), current.Conditions...)
+	for _, condition := range evaluated.Conditions {
+		condition.ObservedGeneration = generation
+		meta.SetStatusCondition(&desired.Conditions, condition)
+	}
+	return desired
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *nodeFeatureRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	nodeFeature := &unstructured.Unstructured{}
+	nodeFeature.SetGroupVersionKind(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(rulestatus.NodeFeatureKind))
+
+	// the mirrors are owned so that their edits and deletions are reverted.
+	// The status of every NodeFeatureRule depends on the features of the
+	// nodes and, through back-references, on the other NodeFeatureRules,
+	// which also lets a conflicting mirror be created once it is deleted
+	allRules := handler.EnqueueRequestsFromMapFunc(allNodeFeatureRules(mgr.GetClient()))
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&nfdopenshiftiov1alpha1.NodeFeatureRule{}).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureRule{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Owns(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}, allRules, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(nodeFeature, allRules, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Node{}, allRules, builder.WithPredicates(getNodePredicates())).
 		Complete(reconcile.AsReconciler[*nfdopenshiftiov1alpha1.NodeFeatureRule](mgr.GetClient(), r))
 }
+
+// getNodePredicates only lets through the nodes joining and leaving the
+// cluster, the features of the nodes are in their NodeFeature objects
+func getNodePredicates() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return true },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// allNodeFeatureRules returns a MapFunc enqueueing all the NodeFeatureRules
+// of nfd.openshift.io group
+func allNodeFeatureRules(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nfrList := nfdopenshiftiov1alpha1.NodeFeatureRuleList{}
+		if err := clnt.List(ctx, &nfrList); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureRules")
+			return nil
+		}
+		requests := make([]reconcile.Request, 0, len(nfrList.Items))
+		for _, nfr := range nfrList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKey{Namespace: nfr.Namespace, Name: nfr.Name},
+			})
+		}
+		return requests
+	}
+}
@@ -24,47 +24,129 @@ import (
 	nfdv1openshiftioalpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
 	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
 	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"github.com/openshift/cluster-nfd-operator/internal/migration"
+	"github.com/openshift/cluster-nfd-operator/internal/rulestatus"
 	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	clt "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"time"
 )
 
 var _ = Describe("Reconcile", func() {
 	var (
-		ctrl *gomock.Controller
-		clnt *client.MockClient
-		nfr  *nodeFeatureRuleReconciler
+		ctrl           *gomock.Controller
+		clnt           *client.MockClient
+		mockRuleStatus *rulestatus.MockRuleStatusAPI
+		mockMigration  *migration.MockMigrationAPI
+		statusWriter   *client.MockStatusWriter
+		recorder       *record.FakeRecorder
+		nfr            *nodeFeatureRuleReconciler
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		clnt = client.NewMockClient(ctrl)
+		mockRuleStatus = rulestatus.NewMockRuleStatusAPI(ctrl)
+		mockMigration = migration.NewMockMigrationAPI(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		recorder = record.NewFakeRecorder(10)
 
 		nfr = &nodeFeatureRuleReconciler{
-			client: clnt,
-			scheme: scheme,
+			client:         clnt,
+			scheme:         scheme,
+			ruleStatusAPI:  mockRuleStatus,
+			migrationAPI:   mockMigration,
+			recorder:       recorder,
+			adoptionPolicy: MirrorAdoptionPolicyRefuse,
 		}
 	})
 	ctx := context.Background()
 
+	evaluatedStatus := &nfdv1openshiftioalpha1.NodeFeatureRuleStatus{
+		MatchedNodeCount: 1,
+		Rules: []nfdv1openshiftioalpha1.RuleStatus{
+			{Name: "test", MatchedNodeCount: 1, MatchedNodes: []string{"worker-0"}},
+		},
+		Conditions: []metav1.Condition{
+			{Type: rulestatus.ConditionDegraded, Status: metav1.ConditionFalse, Reason: "EvaluationSucceeded"},
+		},
+	}
+
+	newSource := func(generation int64, rules ...nfdv1openshiftioalpha1.Rule) nfdv1openshiftioalpha1.NodeFeatureRule {
+		return nfdv1openshiftioalpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", UID: "source-uid", Generation: generation},
+			Spec:       nfdv1openshiftioalpha1.NodeFeatureRuleSpec{Rules: rules},
+		}
+	}
+
+	// newMirror returns an existing nfd.k8s-sigs.io NodeFeatureRule last
+	// updated from the given generation of its source, if it has one
+	newMirror := func(source *nfdv1openshiftioalpha1.NodeFeatureRule, sourceGeneration string,
+		rules ...nfdv1openshiftioalpha1.Rule) nfdk8ssigsiov1alpha1.NodeFeatureRule {
+		mirror := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-name", Namespace: "test-namespace", ResourceVersion: "1"},
+			Spec:       nfdv1openshiftioalpha1.NodeFeatureRuleSpec{Rules: rules},
+		}
+		if source != nil {
+			Expect(controllerutil.SetControllerReference(source, &mirror, scheme)).To(Succeed())
+			mirror.Annotations = map[string]string{sourceGenerationAnnotation: sourceGeneration}
+		}
+		return mirror
+	}
+
+	// the source is read again by the mirroring, as stored by the API server
+	getSource := func(source nfdv1openshiftioalpha1.NodeFeatureRule) *gomock.Call {
+		return getObject(ctx, clnt, &source)
+	}
+
+	getMirror := func(mirror nfdk8ssigsiov1alpha1.NodeFeatureRule) *gomock.Call {
+		return getObject(ctx, clnt, &mirror)
+	}
+
+	mirrorNotFound := func() *gomock.Call {
+		return clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+	}
+
+	expectStatusPatches := func(source *nfdv1openshiftioalpha1.NodeFeatureRule) []any {
+		return []any{
+			mockRuleStatus.EXPECT().GetRuleStatus(ctx, source).Return(evaluatedStatus, nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdv1openshiftioalpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+		}
+	}
+
 	It("Create NodeFeatureRule successfully", func() {
-		nfdCR := nfdv1openshiftioalpha1.NodeFeatureRule{}
-		gomock.InOrder(
-			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
-			clnt.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil),
-		)
+		nfdCR := newSource(1)
+		calls := []any{
+			getSource(nfdCR),
+			mirrorNotFound(),
+			clnt.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj clt.Object, _ ...clt.CreateOption) error {
+					Expect(metav1.IsControlledBy(obj, &nfdCR)).To(BeTrue())
+					Expect(obj.GetAnnotations()).To(HaveKeyWithValue(sourceGenerationAnnotation, "1"))
+					return nil
+				}),
+		}
+		gomock.InOrder(append(calls, expectStatusPatches(&nfdCR)...)...)
 
 		res, err := nfr.Reconcile(ctx, &nfdCR)
 		Expect(res).To(Equal(reconcile.Result{}))
 		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorCreated")))
 	})
 	It("Fail to Create NodeFeatureRule", func() {
 		nfdCR := nfdv1openshiftioalpha1.NodeFeatureRule{}
 		gomock.InOrder(
-			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			getSource(nfdCR),
+			mirrorNotFound(),
 			clnt.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error")),
 		)
 		res, err := nfr.Reconcile(ctx, &nfdCR)
@@ -73,48 +155,91 @@ var _ = Describe("Reconcile", func() {
 	})
 
 	It("Update NodeFeatureRule successfully", func() {
-		nfdCR := nfdv1openshiftioalpha1.NodeFeatureRule{}
-		nfdCRToUpdate := nfdk8ssigsiov1alpha1.NodeFeatureRule{
-			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleSpec{Rules: []nfdv1openshiftioalpha1.Rule{
-				{Name: "test"}},
-			},
+		nfdCR := newSource(2, nfdv1openshiftioalpha1.Rule{Name: "new"})
+		calls := []any{
+			getSource(nfdCR),
+			getMirror(newMirror(&nfdCR, "1", nfdv1openshiftioalpha1.Rule{Name: "test"})),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
 		}
+		gomock.InOrder(append(calls, expectStatusPatches(&nfdCR)...)...)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(nfdCR.Status.Rules).To(Equal(evaluatedStatus.Rules))
+		Expect(nfdCR.Status.Conditions[0].LastTransitionTime.IsZero()).To(BeFalse())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("Edited mirror is restored", func() {
+		nfdCR := newSource(2, nfdv1openshiftioalpha1.Rule{Name: "test"})
+		calls := []any{
+			getSource(nfdCR),
+			getMirror(newMirror(&nfdCR, "2", nfdv1openshiftioalpha1.Rule{Name: "edited"})),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		}
+		gomock.InOrder(append(calls, expectStatusPatches(&nfdCR)...)...)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorDriftCorrected")))
+	})
+
+	It("Existing mirror not owned by the operator is left untouched", func() {
+		nfdCR := newSource(1, nfdv1openshiftioalpha1.Rule{Name: "test"})
 		gomock.InOrder(
-			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{})).
-				DoAndReturn(func(_ context.Context, _ clt.ObjectKey, obj clt.Object, opts ...clt.GetOption) error {
-					target := obj.(*nfdk8ssigsiov1alpha1.NodeFeatureRule)
-					*target = nfdCRToUpdate
+			getSource(nfdCR),
+			getMirror(newMirror(nil, "", nfdv1openshiftioalpha1.Rule{Name: "other"})),
+		)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorConflict")))
+	})
+
+	It("Existing mirror not owned by the operator is adopted", func() {
+		nfr.adoptionPolicy = MirrorAdoptionPolicyAdopt
+		nfdCR := newSource(1, nfdv1openshiftioalpha1.Rule{Name: "test"})
+		calls := []any{
+			getSource(nfdCR),
+			getMirror(newMirror(nil, "", nfdv1openshiftioalpha1.Rule{Name: "other"})),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj clt.Object, _ clt.Patch, _ ...clt.PatchOption) error {
+					Expect(metav1.IsControlledBy(obj, &nfdCR)).To(BeTrue())
 					return nil
 				}),
-			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		}
+		gomock.InOrder(append(calls, expectStatusPatches(&nfdCR)...)...)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorAdopted")))
+	})
+
+	It("Existing mirror controlled by another object is never adopted", func() {
+		nfr.adoptionPolicy = MirrorAdoptionPolicyAdopt
+		nfdCR := newSource(1, nfdv1openshiftioalpha1.Rule{Name: "test"})
+		other := newSource(1)
+		other.UID = "other-uid"
+		gomock.InOrder(
+			getSource(nfdCR),
+			getMirror(newMirror(&other, "1", nfdv1openshiftioalpha1.Rule{Name: "other"})),
 		)
 
 		res, err := nfr.Reconcile(ctx, &nfdCR)
 		Expect(res).To(Equal(reconcile.Result{}))
 		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MirrorConflict")))
 	})
+
 	It("Fail to Update NodeFeatureRule", func() {
-		nfdCR := nfdv1openshiftioalpha1.NodeFeatureRule{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-name",
-			},
-			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleSpec{
-				Rules: []nfdv1openshiftioalpha1.Rule{{Name: "rule-test-name"}},
-			},
-		}
-		nfdCRToUpdate := nfdk8ssigsiov1alpha1.NodeFeatureRule{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test-name",
-			},
-			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleSpec{},
-		}
+		nfdCR := newSource(2, nfdv1openshiftioalpha1.Rule{Name: "rule-test-name"})
 		gomock.InOrder(
-			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{})).
-				DoAndReturn(func(_ context.Context, _ clt.ObjectKey, obj clt.Object, opts ...clt.GetOption) error {
-					target := obj.(*nfdk8ssigsiov1alpha1.NodeFeatureRule)
-					*target = nfdCRToUpdate
-					return nil
-				}),
+			getSource(nfdCR),
+			getMirror(newMirror(&nfdCR, "1")),
 			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error")),
 		)
 
@@ -122,4 +247,169 @@ var _ = Describe("Reconcile", func() {
 		Expect(res).To(Equal(reconcile.Result{}))
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("Status is not patched when it did not change", func() {
+		nfdCR := newSource(2, nfdv1openshiftioalpha1.Rule{Name: "test"})
+		nfdCR.Status = getDesiredRuleStatus(&nfdCR.Status, evaluatedStatus, 2)
+		mirror := newMirror(&nfdCR, "2", nfdv1openshiftioalpha1.Rule{Name: "test"})
+		mirror.Generation = 1
+		mirror.Status = getDesiredRuleStatus(&nfdv1openshiftioalpha1.NodeFeatureRuleStatus{}, evaluatedStatus, 1)
+		// the mirror is read as stored by the API server, with times in seconds
+		mirror.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Truncate(time.Second))
+		gomock.InOrder(
+			getSource(nfdCR),
+			getMirror(mirror),
+			mockRuleStatus.EXPECT().GetRuleStatus(ctx, &nfdCR).Return(evaluatedStatus, nil),
+		)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+	})
+
+	It("A Warning event is emitted when the conflicts of the NodeFeatureRule change", func() {
+		nfdCR := newSource(1, nfdv1openshiftioalpha1.Rule{Name: "test"})
+		mirror := newMirror(&nfdCR, "1", nfdv1openshiftioalpha1.Rule{Name: "test"})
+		conflicting := evaluatedStatus.DeepCopy()
+		conflicting.Conditions = append(conflicting.Conditions, metav1.Condition{
+			Type:    rulestatus.ConditionConflicting,
+			Status:  metav1.ConditionTrue,
+			Reason:  "OutputConflict",
+			Message: "label feature.node.kubernetes.io/zone is set to different values",
+		})
+		gomock.InOrder(
+			getSource(nfdCR),
+			getMirror(mirror),
+			mockRuleStatus.EXPECT().GetRuleStatus(ctx, &nfdCR).Return(conflicting, nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdv1openshiftioalpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+		)
+
+		_, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(Equal("Warning NodeFeatureRuleConflict " + conflicting.Conditions[1].Message)))
+
+		By("the same conflicts are not reported again")
+		nfdCR.Status.MatchedNodeCount = 0
+		gomock.InOrder(
+			getSource(nfdCR),
+			getMirror(mirror),
+			mockRuleStatus.EXPECT().GetRuleStatus(ctx, &nfdCR).Return(conflicting, nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdv1openshiftioalpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}), gomock.Any()).Return(nil),
+		)
+
+		_, err = nfr.Reconcile(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("Fail to evaluate the rules", func() {
+		nfdCR := nfdv1openshiftioalpha1.NodeFeatureRule{}
+		gomock.InOrder(
+			getSource(nfdCR),
+			mirrorNotFound(),
+			clnt.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil),
+			mockRuleStatus.EXPECT().GetRuleStatus(ctx, &nfdCR).Return(nil, fmt.Errorf("some error")),
+		)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("NodeFeatureRule is migrated instead of mirrored", func() {
+		nfdCR := newSource(1)
+		nfdCR.Annotations = map[string]string{migration.MigrateAnnotation: "true"}
+		result := migration.Record{Namespace: "test-namespace", Name: "test-name", Action: migration.ActionRelease}
+		gomock.InOrder(
+			mockMigration.EXPECT().Migrate(ctx, &nfdCR).Return(result, nil),
+			mockMigration.EXPECT().WriteReport(ctx, []migration.Record{result}, false).Return(nil),
+		)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("Migrated")))
+	})
+
+	It("NodeFeatureRule already migrated is not reported again", func() {
+		nfdCR := newSource(1)
+		nfdCR.Annotations = map[string]string{migration.MigrateAnnotation: "true"}
+		mockMigration.EXPECT().Migrate(ctx, &nfdCR).Return(migration.Record{Action: migration.ActionNone}, nil)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("NodeFeatureRule conflicting with an existing object is not migrated", func() {
+		nfdCR := newSource(1)
+		nfdCR.Annotations = map[string]string{migration.MigrateAnnotation: "true"}
+		result := migration.Record{Namespace: "test-namespace", Name: "test-name", Action: migration.ActionConflict}
+		gomock.InOrder(
+			mockMigration.EXPECT().Migrate(ctx, &nfdCR).Return(result, nil),
+			mockMigration.EXPECT().WriteReport(ctx, []migration.Record{result}, false).Return(nil),
+		)
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).To(Receive(ContainSubstring("MigrationConflict")))
+	})
+
+	It("Fail to migrate NodeFeatureRule", func() {
+		nfdCR := newSource(1)
+		nfdCR.Annotations = map[string]string{migration.MigrateAnnotation: "true"}
+		mockMigration.EXPECT().Migrate(ctx, &nfdCR).Return(migration.Record{}, fmt.Errorf("some error"))
+
+		res, err := nfr.Reconcile(ctx, &nfdCR)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("allNodeFeatureRules", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+
+	ctx := context.Background()
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}}
+
+	It("should enqueue all the NodeFeatureRules", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ interface{}, list *nfdv1openshiftioalpha1.NodeFeatureRuleList, _ ...clt.ListOption) error {
+				list.Items = []nfdv1openshiftioalpha1.NodeFeatureRule{
+					{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "ns-a"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "ns-b"}},
+				}
+				return nil
+			},
+		)
+
+		requests := allNodeFeatureRules(clnt)(ctx, node)
+		Expect(requests).To(Equal([]reconcile.Request{
+			{NamespacedName: clt.ObjectKey{Namespace: "ns-a", Name: "first"}},
+			{NamespacedName: clt.ObjectKey{Namespace: "ns-b", Name: "second"}},
+		}))
+	})
+
+	It("should not enqueue anything when listing the NodeFeatureRules fails", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		requests := allNodeFeatureRules(clnt)(ctx, node)
+		Expect(requests).To(BeEmpty())
+	})
 })
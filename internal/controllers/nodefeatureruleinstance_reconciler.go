@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/ruletemplate"
+)
+
+const (
+	// ConditionRendered indicates whether the NodeFeatureRule of a
+	// NodeFeatureRuleInstance is up to date with its template and parameters
+	ConditionRendered = "Rendered"
+
+	reasonRendered          = "Rendered"
+	reasonTemplateNotFound  = "TemplateNotFound"
+	reasonInvalidParameters = "InvalidParameters"
+	reasonRenderConflict    = "Conflict"
+	reasonRenderFailed      = "RenderFailed"
+)
+
+// errRenderConflict is returned when the NodeFeatureRule to render already
+// exists and is not controlled by the instance
+var errRenderConflict = errors.New("NodeFeatureRule exists and is not owned by the NodeFeatureRuleInstance")
+
+// nodeFeatureRuleInstanceReconciler renders the NodeFeatureRuleInstances into
+// nfd.k8s-sigs.io NodeFeatureRules from their NodeFeatureRuleTemplate. The
+// NodeFeatureRule rendered last is kept when the instance can not be rendered
+// anymore, e.g. after a change of the template, so that the nodes keep their
+// labels until the instance is fixed
+type nodeFeatureRuleInstanceReconciler struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+func NewNodeFeatureRuleInstanceReconciler(client client.Client, scheme *runtime.Scheme,
+	recorder record.EventRecorder) *nodeFeatureRuleInstanceReconciler {
+	return &nodeFeatureRuleInstanceReconciler{
+		client:   client,
+		scheme:   scheme,
+		recorder: recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeatureruletemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeatureruleinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=nfd.openshift.io,resources=nodefeatureruleinstances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=get;list;watch;create;update;patch;delete
+
+func (r *nodeFeatureRuleInstanceReconciler) Reconcile(ctx context.Context, nfri *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling NodeFeatureRuleInstance", "namespace", nfri.Namespace, "name", nfri.Name)
+
+	condition, templateGeneration, err := r.render(ctx, nfri)
+	if err != nil {
+		logger.Error(err, "Failed to render NodeFeatureRuleInstance")
+		return ctrl.Result{}, err
+	}
+	previous := meta.FindStatusCondition(nfri.Status.Conditions, ConditionRendered)
+	if condition.Status != metav1.ConditionTrue &&
+		(previous == nil || previous.Reason != condition.Reason || previous.Message != condition.Message) {
+		r.recorder.Event(nfri, corev1.EventTypeWarning, condition.Reason, condition.Message)
+	}
+
+	if err := r.handleStatus(ctx, nfri, condition, templateGeneration); err != nil {
+		logger.Error(err, "Failed to update the status of NodeFeatureRuleInstance")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// render creates or updates the NodeFeatureRule of the instance and returns
+// the Rendered condition and the generation of the template it was rendered
+// from. Errors are only returned for failures that are worth a retry
+func (r *nodeFeatureRuleInstanceReconciler) render(ctx context.Context,
+	nfri *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance) (metav1.Condition, int64, error) {
+	templateGeneration := nfri.Status.TemplateGeneration
+
+	nfrt := &nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate{}
+	err := r.client.Get(ctx, client.ObjectKey{Name: nfri.Spec.TemplateName}, nfrt)
+	if k8serrors.IsNotFound(err) {
+		return newRenderedCondition(reasonTemplateNotFound,
+			fmt.Sprintf("NodeFeatureRuleTemplate %s does not exist", nfri.Spec.TemplateName)), templateGeneration, nil
+	}
+	if err != nil {
+		return metav1.Condition{}, 0, fmt.Errorf("failed to get NodeFeatureRuleTemplate %s: %w", nfri.Spec.TemplateName, err)
+	}
+
+	spec, err := ruletemplate.Render(&nfrt.Spec, nfri.Spec.Parameters)
+	if err != nil {
+		return newRenderedCondition(reasonInvalidParameters, err.Error()), templateGeneration, nil
+	}
+
+	nfr := &nfdk8ssigsiov1alpha1.NodeFeatureRule{
+		ObjectMeta: metav1.ObjectMeta{Name: nfri.Name, Namespace: nfri.Namespace},
+	}
+	_, err = controllerutil.CreateOrPatch(ctx, r.client, nfr, func() error {
+		if nfr.ResourceVersion != "" && !metav1.IsControlledBy(nfr, nfri) {
+			return errRenderConflict
+		}
+		metav1.SetMetaDataLabel(&nfr.ObjectMeta, ruletemplate.TemplateLabel, nfrt.Name)
+		nfr.Spec = *spec
+		return controllerutil.SetControllerReference(nfri, nfr, r.scheme)
+	})
+	switch {
+	case errors.Is(err, errRenderConflict):
+		message := fmt.Sprintf("NodeFeatureRule %s/%s in nfd.k8s-sigs.io group exists and is not owned by the instance", nfr.Namespace, nfr.Name)
+		return newRenderedCondition(reasonRenderConflict, message), templateGeneration, nil
+	case k8serrors.IsInvalid(err):
+		// the rendered rules are rejected by the NodeFeatureRule webhook
+		return newRenderedCondition(reasonRenderFailed, err.Error()), templateGeneration, nil
+	case err != nil:
+		return metav1.Condition{}, 0, fmt.Errorf("failed to create or update NodeFeatureRule %s/%s in nfd.k8s-sigs.io group: %w",
+			nfr.Namespace, nfr.Name, err)
+	}
+
+	return metav1.Condition{
+		Type:    ConditionRendered,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonRendered,
+		Message: fmt.Sprintf("NodeFeatureRule rendered from NodeFeatureRuleTemplate %s", nfrt.Name),
+	}, nfrt.Generation, nil
+}
+
+func newRenderedCondition(reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    ConditionRendered,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// handleStatus patches the status of the instance when it changed
+func (r *nodeFeatureRuleInstanceReconciler) handleStatus(ctx context.Context, nfri *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance,
+	condition metav1.Condition, templateGeneration int64) error {
+	status := nfri.Status.DeepCopy()
+	status.TemplateGeneration = templateGeneration
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if equality.Semantic.DeepEqual(&nfri.Status, status) {
+		return nil
+	}
+
+	unmodified := nfri.DeepCopy()
+	nfri.Status = *status
+	if err := r.client.Status().Patch(ctx, nfri, client.MergeFrom(unmodified)); err != nil {
+		return fmt.Errorf("failed to patch the status of NodeFeatureRuleInstance %s/%s: %w", nfri.Namespace, nfri.Name, err)
+	}
+	return nil
+}
+
+// templateInstances returns a MapFunc enqueueing the NodeFeatureRuleInstances
+// of the template, which are rendered again whenever the template changes
+func templateInstances(clnt client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		nfriList := nfdopenshiftiov1alpha1.NodeFeatureRuleInstanceList{}
+		if err := clnt.List(ctx, &nfriList); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to list NodeFeatureRuleInstances", "template", obj.GetName())
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, nfri := range nfriList.Items {
+			if nfri.Spec.TemplateName == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKey{Namespace: nfri.Namespace, Name: nfri.Name},
+				})
+			}
+		}
+		return requests
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *nodeFeatureRuleInstanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the rendered NodeFeatureRules are watched so that their drift is
+	// corrected, and the templates so that their instances are rendered
+	// again when they change
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureRuleInstance{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate{}, handler.EnqueueRequestsFromMapFunc(templateInstances(mgr.GetClient())),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(reconcile.AsReconciler[*nfdopenshiftiov1alpha1.NodeFeatureRuleInstance](mgr.GetClient(), r))
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package new_controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	nfdv1openshiftioalpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"github.com/openshift/cluster-nfd-operator/internal/ruletemplate"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/record"
+	clt "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("NodeFeatureRuleInstance Reconcile", func() {
+	var (
+		ctrl         *gomock.Controller
+		clnt         *client.MockClient
+		statusWriter *client.MockStatusWriter
+		recorder     *record.FakeRecorder
+		nfrir        *nodeFeatureRuleInstanceReconciler
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		recorder = record.NewFakeRecorder(10)
+
+		nfrir = NewNodeFeatureRuleInstanceReconciler(clnt, scheme, recorder)
+	})
+	ctx := context.Background()
+
+	newTemplate := func() nfdv1openshiftioalpha1.NodeFeatureRuleTemplate {
+		return nfdv1openshiftioalpha1.NodeFeatureRuleTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "kernel-module", Generation: 2},
+			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleTemplateSpec{
+				Parameters: []nfdv1openshiftioalpha1.TemplateParameter{{Name: "module", Required: true}},
+				Rules: []nfdv1openshiftioalpha1.EmbeddedRule{{
+					Name:   "$(module) loaded",
+					Labels: map[string]string{"kmod-$(module)": "true"},
+				}},
+			},
+		}
+	}
+
+	newInstance := func(params map[string]string) nfdv1openshiftioalpha1.NodeFeatureRuleInstance {
+		return nfdv1openshiftioalpha1.NodeFeatureRuleInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "vfio", Namespace: "test-namespace", UID: "instance-uid"},
+			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleInstanceSpec{
+				TemplateName: "kernel-module",
+				Parameters:   params,
+			},
+		}
+	}
+
+	getTemplate := func(nfrt *nfdv1openshiftioalpha1.NodeFeatureRuleTemplate) *gomock.Call {
+		return clnt.EXPECT().Get(ctx, clt.ObjectKey{Name: nfrt.Name}, gomock.AssignableToTypeOf(nfrt)).DoAndReturn(
+			func(_ context.Context, _ clt.ObjectKey, obj *nfdv1openshiftioalpha1.NodeFeatureRuleTemplate, _ ...clt.GetOption) error {
+				nfrt.DeepCopyInto(obj)
+				return nil
+			})
+	}
+
+	patchStatus := func(nfri *nfdv1openshiftioalpha1.NodeFeatureRuleInstance) []any {
+		return []any{
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, nfri, gomock.Any()).Return(nil),
+		}
+	}
+
+	It("NodeFeatureRule is rendered with the parameters of the instance", func() {
+		nfrt := newTemplate()
+		nfri := newInstance(map[string]string{"module": "vfio_pci"})
+		gomock.InOrder(append([]any{
+			getTemplate(&nfrt),
+			clnt.EXPECT().Get(ctx, clt.ObjectKey{Namespace: "test-namespace", Name: "vfio"}, gomock.Any()).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj clt.Object, _ ...clt.CreateOption) error {
+					nfr := obj.(*nfdk8ssigsiov1alpha1.NodeFeatureRule)
+					Expect(metav1.IsControlledBy(nfr, &nfri)).To(BeTrue())
+					Expect(nfr.Labels).To(HaveKeyWithValue(ruletemplate.TemplateLabel, "kernel-module"))
+					Expect(nfr.Spec.Rules[0].Name).To(Equal("vfio_pci loaded"))
+					Expect(nfr.Spec.Rules[0].Labels).To(Equal(map[string]string{"kmod-vfio_pci": "true"}))
+					return nil
+				}),
+		}, patchStatus(&nfri)...)...)
+
+		res, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(BeNil())
+		Expect(meta.IsStatusConditionTrue(nfri.Status.Conditions, ConditionRendered)).To(BeTrue())
+		Expect(nfri.Status.TemplateGeneration).To(Equal(int64(2)))
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("NodeFeatureRule rendered from an older template generation is updated", func() {
+		nfrt := newTemplate()
+		nfri := newInstance(map[string]string{"module": "vfio_pci"})
+		nfri.Status.TemplateGeneration = 1
+		nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "vfio", Namespace: "test-namespace", ResourceVersion: "1"},
+			Spec: nfdv1openshiftioalpha1.NodeFeatureRuleSpec{
+				Rules: []nfdv1openshiftioalpha1.Rule{{Name: "vfio_pci"}},
+			},
+		}
+		Expect(controllerutil.SetControllerReference(&nfri, &nfr, scheme)).To(Succeed())
+		gomock.InOrder(append([]any{
+			getTemplate(&nfrt),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ clt.ObjectKey, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...clt.GetOption) error {
+					nfr.DeepCopyInto(obj)
+					return nil
+				}),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(nil),
+		}, patchStatus(&nfri)...)...)
+
+		_, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(err).To(BeNil())
+		Expect(nfri.Status.TemplateGeneration).To(Equal(int64(2)))
+	})
+
+	It("Invalid parameters are reported and the NodeFeatureRule is left untouched", func() {
+		nfrt := newTemplate()
+		nfri := newInstance(map[string]string{"modules": "vfio_pci"})
+		gomock.InOrder(append([]any{getTemplate(&nfrt)}, patchStatus(&nfri)...)...)
+
+		_, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(err).To(BeNil())
+		condition := meta.FindStatusCondition(nfri.Status.Conditions, ConditionRendered)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(reasonInvalidParameters))
+		Expect(condition.Message).To(ContainSubstring("spec.parameters[modules]"))
+		Expect(recorder.Events).To(Receive(ContainSubstring(reasonInvalidParameters)))
+	})
+
+	It("A missing template is reported once", func() {
+		nfri := newInstance(nil)
+		nfri.Status.Conditions = []metav1.Condition{{
+			Type:    ConditionRendered,
+			Status:  metav1.ConditionFalse,
+			Reason:  reasonTemplateNotFound,
+			Message: "NodeFeatureRuleTemplate kernel-module does not exist",
+		}}
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+
+		_, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(err).To(BeNil())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("Existing NodeFeatureRule not owned by the instance is left untouched", func() {
+		nfrt := newTemplate()
+		nfri := newInstance(map[string]string{"module": "vfio_pci"})
+		gomock.InOrder(append([]any{
+			getTemplate(&nfrt),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ clt.ObjectKey, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...clt.GetOption) error {
+					obj.ResourceVersion = "1"
+					return nil
+				}),
+		}, patchStatus(&nfri)...)...)
+
+		_, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(err).To(BeNil())
+		Expect(meta.FindStatusCondition(nfri.Status.Conditions, ConditionRendered).Reason).To(Equal(reasonRenderConflict))
+		Expect(recorder.Events).To(Receive(ContainSubstring(reasonRenderConflict)))
+	})
+
+	It("Rendered rules rejected by the NodeFeatureRule webhook are reported", func() {
+		nfrt := newTemplate()
+		nfri := newInstance(map[string]string{"module": "vfio pci"})
+		invalid := apierrors.NewInvalid(nfdk8ssigsiov1alpha1.GroupVersion.WithKind("NodeFeatureRule").GroupKind(), "vfio",
+			field.ErrorList{field.Invalid(field.NewPath("spec", "rules").Index(0).Child("labels"), "kmod-vfio pci", "invalid label")})
+		gomock.InOrder(append([]any{
+			getTemplate(&nfrt),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(invalid),
+		}, patchStatus(&nfri)...)...)
+
+		_, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(err).To(BeNil())
+		Expect(meta.FindStatusCondition(nfri.Status.Conditions, ConditionRendered).Reason).To(Equal(reasonRenderFailed))
+	})
+
+	It("Fail to get the template", func() {
+		nfri := newInstance(nil)
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		res, err := nfrir.Reconcile(ctx, &nfri)
+		Expect(res).To(Equal(reconcile.Result{}))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("templateInstances", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+	ctx := context.Background()
+
+	It("only the instances of the template are enqueued", func() {
+		nfrt := nfdv1openshiftioalpha1.NodeFeatureRuleTemplate{ObjectMeta: metav1.ObjectMeta{Name: "kernel-module"}}
+		clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, list *nfdv1openshiftioalpha1.NodeFeatureRuleInstanceList, _ ...clt.ListOption) error {
+				list.Items = []nfdv1openshiftioalpha1.NodeFeatureRuleInstance{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "vfio", Namespace: "team-a"},
+						Spec:       nfdv1openshiftioalpha1.NodeFeatureRuleInstanceSpec{TemplateName: "kernel-module"},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "team-b"},
+						Spec:       nfdv1openshiftioalpha1.NodeFeatureRuleInstanceSpec{TemplateName: "pci-device"},
+					},
+				}
+				return nil
+			})
+
+		requests := templateInstances(clnt)(ctx, &nfrt)
+		Expect(requests).To(Equal([]reconcile.Request{{NamespacedName: clt.ObjectKey{Namespace: "team-a", Name: "vfio"}}}))
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -36,7 +37,8 @@ import (
 
 type DaemonsetAPI interface {
 	SetTopologyDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, topologyDS *appsv1.DaemonSet, operandImage string) error
-	SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, workerDS *appsv1.DaemonSet, operandImage string) error
+	SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, profile *nfdv1.WorkerProfile, workerDS *appsv1.DaemonSet, operandImage string,
+		featureFilesConfigMap string) error
 	DeleteDaemonSet(ctx context.Context, namespace, name string) error
 	GetDaemonSet(ctx context.Context, namespace, name string) (*appsv1.DaemonSet, error)
 }
@@ -56,7 +58,7 @@ func NewDaemonsetAPI(client client.Client, scheme *runtime.Scheme) DaemonsetAPI
 func (d *daemonset) SetTopologyDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, topologyDS *appsv1.DaemonSet, operandImage string) error {
 	topologyDS.ObjectMeta.Labels = map[string]string{"app": "nfd"}
 
-	podLabels := map[string]string{"app": "nfd-topology-updater"}
+	podLabels := map[string]string{"app": nfdInstance.ComponentName("nfd-topology-updater")}
 	topologyDS.Spec = appsv1.DaemonSetSpec{
 		Selector: &metav1.LabelSelector{
 			MatchLabels: podLabels,
@@ -78,10 +80,16 @@ func (d *daemonset) SetTopologyDaemonsetAsDesired(ctx context.Context, nfdInstan
 						Args:            getArgs(nfdInstance),
 						Env:             getTopologyEnvs(),
 						SecurityContext: getSecurityContext(),
-						VolumeMounts:    getVolumeMounts(),
+						VolumeMounts:    getVolumeMounts(nfdInstance),
+						Resources:       getTopologyResources(nfdInstance),
+						LivenessProbe:   getLivenessProbe(),
+						ReadinessProbe:  getReadinessProbe(),
+						Ports:           getPorts(nfdInstance.Spec.Operand.TopologyUpdaterPort()),
 					},
 				},
-				Volumes: getVolumes(),
+				Volumes:      getVolumes(nfdInstance),
+				NodeSelector: nfdInstance.Spec.Operand.TopologyUpdaterNodeSelector,
+				Tolerations:  nfdInstance.Spec.Operand.TopologyUpdaterTolerations,
 			},
 		},
 	}
@@ -116,10 +124,17 @@ func getImagePullPolicy(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.PullPoli
 }
 
 func getArgs(nfdInstance *nfdv1.NodeFeatureDiscovery) []string {
-	return []string{
-		"-podresources-socket=/host-var/lib/kubelet/pod-resources/kubelet.sock",
-		"-sleep-interval=3s",
+	config := getTopologyUpdaterConfig(nfdInstance)
+	args := []string{
+		"-podresources-socket=" + topologyPodResourcesSocketMountPath,
+		"-kubelet-state-dir=" + topologyKubeletStateDirMountPath,
+		"-sleep-interval=" + config.SleepInterval,
+		fmt.Sprintf("-port=%d", nfdInstance.Spec.Operand.TopologyUpdaterPort()),
 	}
+	if config.KubeletConfigFile != "" {
+		args = append(args, "-kubelet-config-uri=file://"+topologyKubeletConfigFileMountPath)
+	}
+	return args
 }
 
 func getWorkerEnvs(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.EnvVar {
@@ -193,11 +208,11 @@ func getSecurityContext() *corev1.SecurityContext {
 	}
 }
 
-func getVolumeMounts() []corev1.VolumeMount {
-	return []corev1.VolumeMount{
+func getVolumeMounts(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
 		{
 			Name:      "kubelet-podresources-sock",
-			MountPath: "/host-var/lib/kubelet/pod-resources/kubelet.sock",
+			MountPath: topologyPodResourcesSocketMountPath,
 		},
 		{
 			Name:      "host-sys",
@@ -205,19 +220,33 @@ func getVolumeMounts() []corev1.VolumeMount {
 		},
 		{
 			Name:      "kubelet-state-files",
-			MountPath: "/host-var/lib/kubelet",
+			MountPath: topologyKubeletStateDirMountPath,
+			ReadOnly:  true,
+		},
+		{
+			Name:      "nfd-topology-updater-conf",
+			MountPath: "/etc/kubernetes/node-feature-discovery",
 			ReadOnly:  true,
 		},
 	}
+	if getTopologyUpdaterConfig(nfdInstance).KubeletConfigFile != "" {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      "kubelet-config",
+			MountPath: topologyKubeletConfigFileMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
 }
 
-func getVolumes() []corev1.Volume {
-	return []corev1.Volume{
+func getVolumes(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.Volume {
+	config := getTopologyUpdaterConfig(nfdInstance)
+	volumes := []corev1.Volume{
 		{
 			Name: "kubelet-podresources-sock",
 			VolumeSource: corev1.VolumeSource{
 				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/var/lib/kubelet/pod-resources/kubelet.sock",
+					Path: config.PodResourcesSocketPath,
 					Type: ptr.To[corev1.HostPathType](corev1.HostPathSocket),
 				},
 			},
@@ -235,14 +264,99 @@ func getVolumes() []corev1.Volume {
 			Name: "kubelet-state-files",
 			VolumeSource: corev1.VolumeSource{
 				HostPath: &corev1.HostPathVolumeSource{
-					Path: "/var/lib/kubelet",
+					Path: config.KubeletStateDir,
 					Type: ptr.To[corev1.HostPathType](corev1.HostPathDirectory),
 				},
 			},
 		},
+		{
+			Name: "nfd-topology-updater-conf",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: nfdInstance.ComponentName("nfd-topology-updater")},
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "nfd-topology-updater.conf",
+							Path: "nfd-topology-updater.conf",
+						},
+					},
+				},
+			},
+		},
+	}
+	if config.KubeletConfigFile != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: "kubelet-config",
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: config.KubeletConfigFile,
+					Type: ptr.To[corev1.HostPathType](corev1.HostPathFile),
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func getLivenessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		InitialDelaySeconds: 10,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.IntOrString{
+					Type:   intstr.String,
+					StrVal: "http",
+				},
+			},
+		},
+	}
+}
+
+func getReadinessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		InitialDelaySeconds: 5,
+		FailureThreshold:    10,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.IntOrString{
+					Type:   intstr.String,
+					StrVal: "http",
+				},
+			},
+		},
 	}
 }
 
+// getPorts returns the health and metrics port of the container. For the
+// host-networked worker this is also the port opened on the node
+func getPorts(port int32) []corev1.ContainerPort {
+	return []corev1.ContainerPort{
+		{
+			ContainerPort: port,
+			Name:          "http",
+		},
+	}
+}
+
+func getWorkerResources(profile *nfdv1.WorkerProfile) corev1.ResourceRequirements {
+	if profile.Resources != nil {
+		return *profile.Resources
+	}
+	return corev1.ResourceRequirements{
+		Requests: getRequests(),
+		Limits:   getLimits(),
+	}
+}
+
+func getTopologyResources(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.ResourceRequirements {
+	if nfdInstance.Spec.Operand.TopologyUpdaterResources != nil {
+		return *nfdInstance.Spec.Operand.TopologyUpdaterResources
+	}
+	return corev1.ResourceRequirements{}
+}
+
 func getLimits() corev1.ResourceList {
 	return corev1.ResourceList{
 		corev1.ResourceCPU:    resource.MustParse("200m"),
@@ -257,20 +371,45 @@ func getRequests() corev1.ResourceList {
 	}
 }
 
-func (d *daemonset) SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, workerDS *appsv1.DaemonSet, operandImage string) error {
+// SetWorkerDaemonsetAsDesired sets the worker DaemonSet of the profile. When
+// featureFilesConfigMap is set, the feature files it holds are installed in
+// features.d along with the ones of the host
+func (d *daemonset) SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, profile *nfdv1.WorkerProfile,
+	workerDS *appsv1.DaemonSet, operandImage string, featureFilesConfigMap string) error {
 	workerDS.ObjectMeta.Labels = map[string]string{"app": "nfd"}
+	workerName := nfdInstance.WorkerProfileName(profile.Name)
+	podLabels := getWorkerLabelsAForApp(workerName)
+	if profile.Name != "" {
+		workerDS.ObjectMeta.Labels[WorkerProfileLabel] = profile.Name
+		// all the worker pods share the same app label, so that the
+		// worker NetworkPolicy selects the pods of every profile
+		podLabels = getWorkerLabelsAForApp(nfdInstance.ComponentName("nfd-worker"))
+		podLabels[WorkerProfileLabel] = profile.Name
+	}
+
+	configVolumeSource := corev1.ConfigMapVolumeSource{
+		LocalObjectReference: corev1.LocalObjectReference{Name: workerName},
+		Items:                []corev1.KeyToPath{{Key: "nfd-worker-conf", Path: "nfd-worker.conf"}},
+	}
+	if ref := profile.WorkerConfig.ConfigMapRef; ref != nil {
+		configVolumeSource = corev1.ConfigMapVolumeSource{
+			LocalObjectReference: ref.LocalObjectReference,
+			Items:                []corev1.KeyToPath{{Key: ref.Key, Path: "nfd-worker.conf"}},
+			Optional:             ref.Optional,
+		}
+	}
 
 	workerDS.Spec = appsv1.DaemonSetSpec{
 		Selector: &metav1.LabelSelector{
-			MatchLabels: getWorkerLabelsAForApp("nfd-worker"),
+			MatchLabels: podLabels,
 		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: getWorkerLabelsAForApp("nfd-worker"),
+				Labels: podLabels,
 			},
 			Spec: corev1.PodSpec{
-				Tolerations: getWorkerTolerations(nfdInstance),
-				Affinity:    getWorkerAffinity(),
+				Tolerations: getWorkerTolerations(profile),
+				Affinity:    getWorkerProfileAffinity(profile),
 
 				ServiceAccountName: "nfd-worker",
 				HostNetwork:        true,
@@ -280,21 +419,24 @@ func (d *daemonset) SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance
 						Image:           operandImage,
 						Name:            "nfd-worker",
 						Command:         []string{"nfd-worker"},
-						Args:            []string{},
+						Args:            []string{fmt.Sprintf("-port=%d", nfdInstance.Spec.Operand.WorkerPort())},
 						VolumeMounts:    *getWorkerVolumeMounts(),
 						ImagePullPolicy: getImagePullPolicy(nfdInstance),
 						SecurityContext: getWorkerSecurityContext(),
-						Resources: corev1.ResourceRequirements{
-							Requests: getRequests(),
-							Limits:   getLimits(),
-						},
+						Resources:       getWorkerResources(profile),
+						LivenessProbe:   getLivenessProbe(),
+						ReadinessProbe:  getReadinessProbe(),
+						Ports:           getPorts(nfdInstance.Spec.Operand.WorkerPort()),
 					},
 				},
-				Volumes:           getWorkerVolumes(),
-				NodeSelector:      nfdInstance.Spec.Operand.WorkerNodeSelector,
-				PriorityClassName: nfdInstance.Spec.Operand.WorkerPriorityClassName,
+				Volumes:           getWorkerVolumes(configVolumeSource),
+				NodeSelector:      profile.NodeSelector,
+				PriorityClassName: profile.PriorityClassName,
 			},
 		},
 	}
+	if featureFilesConfigMap != "" {
+		addFeatureFiles(&workerDS.Spec.Template.Spec, featureFilesConfigMap, operandImage, getImagePullPolicy(nfdInstance))
+	}
 	return controllerutil.SetControllerReference(nfdInstance, workerDS, d.scheme)
 }
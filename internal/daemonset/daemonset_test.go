@@ -29,8 +29,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
@@ -77,6 +79,55 @@ var _ = Describe("SetTopologyDaemonsetAsDesired", func() {
 		Expect(err).To(BeNil())
 		Expect(topologyDS).To(BeComparableTo(testTopologyDS))
 	})
+
+	It("typed topology updater config flows into the args and host paths", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				TopologyUpdaterConfig: &nfdv1.TopologyUpdaterConfig{
+					SleepInterval:          "30s",
+					KubeletStateDir:        "/var/lib/custom-kubelet",
+					PodResourcesSocketPath: "/var/lib/custom-kubelet/pod-resources/kubelet.sock",
+					KubeletConfigFile:      "/etc/kubernetes/kubelet.conf",
+				},
+				Operand: nfdv1.OperandSpec{
+					Image:                       "test-image",
+					TopologyUpdaterNodeSelector: map[string]string{"numa": "true"},
+					TopologyUpdaterTolerations:  []corev1.Toleration{{Key: "numa", Operator: corev1.TolerationOpExists}},
+				},
+			},
+		}
+		topologyDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetTopologyDaemonsetAsDesired(ctx, &nfdCR, &topologyDS, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		podSpec := topologyDS.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{"numa": "true"}))
+		Expect(podSpec.Tolerations).To(Equal([]corev1.Toleration{{Key: "numa", Operator: corev1.TolerationOpExists}}))
+		Expect(podSpec.Containers[0].Args).To(ContainElements(
+			"-sleep-interval=30s",
+			"-kubelet-config-uri=file:///host-var/kubelet-config",
+		))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "kubelet-config",
+			MountPath: "/host-var/kubelet-config",
+			ReadOnly:  true,
+		}))
+		hostPaths := map[string]string{}
+		for _, volume := range podSpec.Volumes {
+			if volume.HostPath != nil {
+				hostPaths[volume.Name] = volume.HostPath.Path
+			}
+		}
+		Expect(hostPaths).To(Equal(map[string]string{
+			"kubelet-podresources-sock": "/var/lib/custom-kubelet/pod-resources/kubelet.sock",
+			"host-sys":                  "/sys",
+			"kubelet-state-files":       "/var/lib/custom-kubelet",
+			"kubelet-config":            "/etc/kubernetes/kubelet.conf",
+		}))
+		Expect(podSpec.Volumes).To(ContainElement(HaveField("VolumeSource.ConfigMap.Name", "nfd-topology-updater-team-a")))
+	})
 })
 
 var _ = Describe("SetWorkerDaemonsetAsDesired", func() {
@@ -111,7 +162,7 @@ var _ = Describe("SetWorkerDaemonsetAsDesired", func() {
 			},
 		}
 
-		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &actualWorkerDS, nfdCR.Spec.Operand.Image)
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
 
 		Expect(err).To(BeNil())
 		expectedYAMLFile, err := os.ReadFile("testdata/test_worker_daemonset.yaml")
@@ -123,6 +174,212 @@ var _ = Describe("SetWorkerDaemonsetAsDesired", func() {
 		Expect(err).To(BeNil())
 		Expect(&expectedWorkerDS).To(BeComparableTo(&actualWorkerDS))
 	})
+
+	It("worker pods and config map are suffixed with the instance name", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		Expect(actualWorkerDS.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", "nfd-worker-team-a"))
+		Expect(actualWorkerDS.Spec.Template.Labels).To(HaveKeyWithValue("app", "nfd-worker-team-a"))
+		Expect(actualWorkerDS.Spec.Template.Spec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-worker-config"),
+			HaveField("VolumeSource.ConfigMap.LocalObjectReference.Name", "nfd-worker-team-a"),
+		)))
+	})
+
+	It("worker port flows into the args and host network container port", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+					Ports: nfdv1.OperandPorts{Worker: 8181},
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		Expect(actualWorkerDS.Spec.Template.Spec.HostNetwork).To(BeTrue())
+		container := actualWorkerDS.Spec.Template.Spec.Containers[0]
+		Expect(container.Args).To(Equal([]string{"-port=8181"}))
+		Expect(container.Ports).To(Equal([]corev1.ContainerPort{{Name: "http", ContainerPort: 8181}}))
+	})
+
+	It("configured resources replace the default ones", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("32Mi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:           "test-image",
+					WorkerResources: &resources,
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		Expect(actualWorkerDS.Spec.Template.Spec.Containers[0].Resources).To(Equal(resources))
+	})
+
+	It("referenced user configmap is mounted instead of the generated one", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+						Key:                  "worker.yaml",
+					},
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		Expect(actualWorkerDS.Spec.Template.Spec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-worker-config"),
+			HaveField("VolumeSource.ConfigMap.LocalObjectReference.Name", "user-worker-config"),
+			HaveField("VolumeSource.ConfigMap.Items", []corev1.KeyToPath{{Key: "worker.yaml", Path: "nfd-worker.conf"}}),
+		)))
+	})
+
+	It("feature files replace the host features.d with one filled by a sidecar", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "nfd-feature-files")
+
+		Expect(err).To(BeNil())
+		podSpec := actualWorkerDS.Spec.Template.Spec
+		Expect(podSpec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-features"),
+			HaveField("VolumeSource.EmptyDir", Not(BeNil())),
+			HaveField("VolumeSource.HostPath", BeNil()),
+		)))
+		Expect(podSpec.Volumes).To(ContainElement(And(
+			HaveField("Name", "host-nfd-features"),
+			HaveField("VolumeSource.HostPath.Path", "/etc/kubernetes/node-feature-discovery/features.d"),
+		)))
+		Expect(podSpec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-feature-files"),
+			HaveField("VolumeSource.Projected.Sources", And(
+				HaveLen(17),
+				ContainElement(HaveField("ConfigMap.LocalObjectReference.Name", "nfd-feature-files")),
+				ContainElement(And(
+					HaveField("ConfigMap.LocalObjectReference.Name", "nfd-feature-files-nodes-15"),
+					HaveField("ConfigMap.Optional", ptr.To(true)),
+				)),
+			)),
+		)))
+		Expect(podSpec.Containers).To(HaveLen(2))
+		sidecar := podSpec.Containers[1]
+		Expect(sidecar.Name).To(Equal("nfd-feature-files"))
+		Expect(sidecar.Image).To(Equal("test-image"))
+		Expect(sidecar.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "host-nfd-features", MountPath: "/host-features.d", ReadOnly: true}))
+		Expect(sidecar.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "nfd-features", MountPath: "/features.d"}))
+	})
+
+	It("without feature files the worker uses the host features.d", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		podSpec := actualWorkerDS.Spec.Template.Spec
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-features"),
+			HaveField("VolumeSource.HostPath.Path", "/etc/kubernetes/node-feature-discovery/features.d"),
+		)))
+	})
+
+	It("worker profile settings replace the top-level worker ones", func() {
+		profileResources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+		profileAffinity := corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{Key: "nvidia.com/gpu.present", Operator: corev1.NodeSelectorOpExists},
+					},
+				}},
+			},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				Operand: nfdv1.OperandSpec{
+					Image:                   "test-image",
+					WorkerNodeSelector:      map[string]string{"worker-pod": "true"},
+					WorkerPriorityClassName: "check-priority-class",
+				},
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{
+						Name:              "gpu",
+						NodeSelector:      map[string]string{"pool": "gpu"},
+						NodeAffinity:      &profileAffinity,
+						Tolerations:       []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+						PriorityClassName: "gpu-priority-class",
+						Resources:         &profileResources,
+					},
+				},
+			},
+		}
+		actualWorkerDS := appsv1.DaemonSet{}
+
+		err := daemonsetAPI.SetWorkerDaemonsetAsDesired(ctx, &nfdCR, &nfdCR.GetWorkerProfiles()[0], &actualWorkerDS, nfdCR.Spec.Operand.Image, "")
+
+		Expect(err).To(BeNil())
+		Expect(actualWorkerDS.Labels).To(HaveKeyWithValue(WorkerProfileLabel, "gpu"))
+		podLabels := map[string]string{"app": "nfd-worker-team-a", WorkerProfileLabel: "gpu"}
+		Expect(actualWorkerDS.Spec.Selector.MatchLabels).To(Equal(podLabels))
+		Expect(actualWorkerDS.Spec.Template.Labels).To(Equal(podLabels))
+		podSpec := actualWorkerDS.Spec.Template.Spec
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{"pool": "gpu"}))
+		Expect(podSpec.Affinity.NodeAffinity).To(Equal(&profileAffinity))
+		Expect(podSpec.Tolerations).To(ContainElement(HaveField("Key", "nvidia.com/gpu")))
+		Expect(podSpec.PriorityClassName).To(Equal("gpu-priority-class"))
+		Expect(podSpec.Containers[0].Resources).To(Equal(profileResources))
+		Expect(podSpec.Volumes).To(ContainElement(And(
+			HaveField("Name", "nfd-worker-config"),
+			HaveField("VolumeSource.ConfigMap.LocalObjectReference.Name", "nfd-worker-gpu-team-a"),
+		)))
+	})
 })
 
 var _ = Describe("DeleteDaemonSet", func() {
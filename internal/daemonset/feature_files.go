@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-nfd-operator/internal/featurefile"
+)
+
+const (
+	featureFilesContainerName = "nfd-feature-files"
+	featureFilesVolumeName    = "nfd-feature-files"
+	hostFeaturesVolumeName    = "host-nfd-features"
+)
+
+// featureFilesSyncScript fills the features.d directory of nfd-worker with
+// the feature files of the host and the ones listed for the node in the
+// feature files ConfigMap, and removes the files no longer listed. Files are
+// written under a hidden name first, which nfd-worker ignores, and moved in
+// place so that nfd-worker never reads a partial file
+const featureFilesSyncScript = `while true; do
+  wanted=""
+  for f in /host-features.d/*; do
+    [ -f "$f" ] || continue
+    name=$(basename "$f")
+    cp "$f" /features.d/.tmp && mv /features.d/.tmp "/features.d/$name"
+    wanted="$wanted $name"
+  done
+  if [ -f "/feature-files/node.$NODE_NAME" ]; then
+    for name in $(cat "/feature-files/node.$NODE_NAME"); do
+      [ -f "/feature-files/$name" ] || continue
+      cp "/feature-files/$name" /features.d/.tmp && mv /features.d/.tmp "/features.d/$name"
+      wanted="$wanted $name"
+    done
+  fi
+  for f in /features.d/*; do
+    [ -f "$f" ] || continue
+    case " $wanted " in *" $(basename "$f") "*) ;; *) rm -f "$f" ;; esac
+  done
+  sleep 10
+done
+`
+
+// addFeatureFiles replaces the host features.d directory of nfd-worker with
+// one filled by a sidecar container, which adds the feature files of the
+// NodeFeatureFiles selecting the node to the ones of the host. The ConfigMap
+// of the files and those of the buckets listing the files of each node are
+// projected in a single directory
+func addFeatureFiles(podSpec *corev1.PodSpec, configMapName string, operandImage string, imagePullPolicy corev1.PullPolicy) {
+	sources := []corev1.VolumeProjection{
+		{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: configMapName}}},
+	}
+	for bucket := 0; bucket < featurefile.NodeFilesBuckets; bucket++ {
+		sources = append(sources, corev1.VolumeProjection{ConfigMap: &corev1.ConfigMapProjection{
+			LocalObjectReference: corev1.LocalObjectReference{Name: featurefile.NodeFilesConfigMapName(configMapName, bucket)},
+			Optional:             ptr.To(true),
+		}})
+	}
+
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == "nfd-features" {
+			podSpec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		}
+	}
+	podSpec.Volumes = append(podSpec.Volumes,
+		corev1.Volume{
+			Name: hostFeaturesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				HostPath: &corev1.HostPathVolumeSource{
+					Path: "/etc/kubernetes/node-feature-discovery/features.d",
+				},
+			},
+		},
+		corev1.Volume{
+			Name: featureFilesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+			},
+		},
+	)
+
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:            featureFilesContainerName,
+		Image:           operandImage,
+		ImagePullPolicy: imagePullPolicy,
+		Command:         []string{"/bin/sh", "-c", featureFilesSyncScript},
+		Env:             getBasicEnvs(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "nfd-features",
+				MountPath: "/features.d",
+			},
+			{
+				Name:      hostFeaturesVolumeName,
+				MountPath: "/host-features.d",
+				ReadOnly:  true,
+			},
+			{
+				Name:      featureFilesVolumeName,
+				MountPath: "/feature-files",
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: getWorkerSecurityContext(),
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1m"),
+				corev1.ResourceMemory: resource.MustParse("8Mi"),
+			},
+		},
+	})
+}
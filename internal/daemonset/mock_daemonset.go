@@ -86,15 +86,15 @@ func (mr *MockDaemonsetAPIMockRecorder) SetTopologyDaemonsetAsDesired(ctx, nfdIn
 }
 
 // SetWorkerDaemonsetAsDesired mocks base method.
-func (m *MockDaemonsetAPI) SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, workerDS *v10.DaemonSet, operandImage string) error {
+func (m *MockDaemonsetAPI) SetWorkerDaemonsetAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, profile *v1.WorkerProfile, workerDS *v10.DaemonSet, operandImage, featureFilesConfigMap string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetWorkerDaemonsetAsDesired", ctx, nfdInstance, workerDS, operandImage)
+	ret := m.ctrl.Call(m, "SetWorkerDaemonsetAsDesired", ctx, nfdInstance, profile, workerDS, operandImage, featureFilesConfigMap)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetWorkerDaemonsetAsDesired indicates an expected call of SetWorkerDaemonsetAsDesired.
-func (mr *MockDaemonsetAPIMockRecorder) SetWorkerDaemonsetAsDesired(ctx, nfdInstance, workerDS, operandImage any) *gomock.Call {
+func (mr *MockDaemonsetAPIMockRecorder) SetWorkerDaemonsetAsDesired(ctx, nfdInstance, profile, workerDS, operandImage, featureFilesConfigMap any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkerDaemonsetAsDesired", reflect.TypeOf((*MockDaemonsetAPI)(nil).SetWorkerDaemonsetAsDesired), ctx, nfdInstance, workerDS, operandImage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWorkerDaemonsetAsDesired", reflect.TypeOf((*MockDaemonsetAPI)(nil).SetWorkerDaemonsetAsDesired), ctx, nfdInstance, profile, workerDS, operandImage, featureFilesConfigMap)
 }
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+const (
+	defaultTopologySleepInterval          = "3s"
+	defaultTopologyKubeletStateDir        = "/var/lib/kubelet"
+	defaultTopologyPodResourcesSocketPath = "/var/lib/kubelet/pod-resources/kubelet.sock"
+
+	// paths the host files and directories are mounted on in the
+	// nfd-topology-updater container
+	topologyPodResourcesSocketMountPath = "/host-var/lib/kubelet/pod-resources/kubelet.sock"
+	topologyKubeletStateDirMountPath    = "/host-var/lib/kubelet"
+	topologyKubeletConfigFileMountPath  = "/host-var/kubelet-config"
+)
+
+// getTopologyUpdaterConfig returns the topology updater configuration of the
+// NFD CR with the defaults set for the unset options
+func getTopologyUpdaterConfig(nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.TopologyUpdaterConfig {
+	config := nfdv1.TopologyUpdaterConfig{}
+	if nfdInstance.Spec.TopologyUpdaterConfig != nil {
+		config = *nfdInstance.Spec.TopologyUpdaterConfig.DeepCopy()
+	}
+	if config.SleepInterval == "" {
+		config.SleepInterval = defaultTopologySleepInterval
+	}
+	if config.KubeletStateDir == "" {
+		config.KubeletStateDir = defaultTopologyKubeletStateDir
+	}
+	if config.PodResourcesSocketPath == "" {
+		config.PodResourcesSocketPath = defaultTopologyPodResourcesSocketPath
+	}
+	return config
+}
@@ -123,7 +123,7 @@ func getWorkerVolumeMounts() *[]corev1.VolumeMount {
 	return &containerVolumeMounts
 }
 
-func getWorkerVolumes() []corev1.Volume {
+func getWorkerVolumes(configVolumeSource corev1.ConfigMapVolumeSource) []corev1.Volume {
 	containerVolume := []corev1.Volume{
 		{
 			Name: "host-boot",
@@ -168,15 +168,7 @@ func getWorkerVolumes() []corev1.Volume {
 		{
 			Name: "nfd-worker-config",
 			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{Name: "nfd-worker"},
-					Items: []corev1.KeyToPath{
-						{
-							Key:  "nfd-worker-conf",
-							Path: "nfd-worker.conf",
-						},
-					},
-				},
+				ConfigMap: &configVolumeSource,
 			},
 		},
 		{
@@ -211,7 +203,7 @@ func getWorkerLabelsAForApp(name string) map[string]string {
 	return map[string]string{"app": name}
 }
 
-func getWorkerTolerations(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.Toleration {
+func getWorkerTolerations(profile *nfdv1.WorkerProfile) []corev1.Toleration {
 	basicTolerations := []corev1.Toleration{
 		{
 			Operator: "Exists",
@@ -219,5 +211,5 @@ func getWorkerTolerations(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.Tole
 		},
 	}
 
-	return append(basicTolerations, nfdInstance.Spec.Operand.WorkerTolerations...)
+	return append(basicTolerations, profile.Tolerations...)
 }
@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+// WorkerProfileLabel is set, with the name of the profile as value, on the
+// DaemonSets and pods generated for the worker profiles
+const WorkerProfileLabel = "nfd.openshift.io/worker-profile"
+
+// getWorkerProfileAffinity returns the affinity of the worker pods of the
+// profile. The required terms of the profile node affinity replace the
+// default worker ones
+func getWorkerProfileAffinity(profile *nfdv1.WorkerProfile) *corev1.Affinity {
+	affinity := getWorkerAffinity()
+	if profile.NodeAffinity == nil {
+		return affinity
+	}
+
+	nodeAffinity := profile.NodeAffinity.DeepCopy()
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	}
+	return &corev1.Affinity{NodeAffinity: nodeAffinity}
+}
+
+// FindWorkerProfileOverlaps returns a description of every node selected by
+// more than one of the given worker profiles, so that no node ends up
+// running two nfd-worker pods
+func FindWorkerProfileOverlaps(profiles []nfdv1.WorkerProfile, nodes []corev1.Node) []string {
+	overlaps := []string{}
+	for i := range nodes {
+		selectedBy := []string{}
+		for j := range profiles {
+			if profileSelectsNode(&profiles[j], &nodes[i]) {
+				selectedBy = append(selectedBy, profiles[j].Name)
+			}
+		}
+		if len(selectedBy) > 1 {
+			overlaps = append(overlaps, fmt.Sprintf("node %s is selected by profiles %s",
+				nodes[i].Name, strings.Join(selectedBy, ", ")))
+		}
+	}
+	sort.Strings(overlaps)
+	return overlaps
+}
+
+// SelectWorkerNodes returns the names of the nodes selected by any of the
+// worker profiles, i.e. the nodes running an nfd-worker pod of the instance
+func SelectWorkerNodes(profiles []nfdv1.WorkerProfile, nodes []corev1.Node) sets.Set[string] {
+	selected := sets.New[string]()
+	for i := range nodes {
+		for j := range profiles {
+			if profileSelectsNode(&profiles[j], &nodes[i]) {
+				selected.Insert(nodes[i].Name)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func profileSelectsNode(profile *nfdv1.WorkerProfile, node *corev1.Node) bool {
+	if !labels.SelectorFromSet(profile.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	required := getWorkerProfileAffinity(profile).NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	for _, term := range required.NodeSelectorTerms {
+		if nodeSelectorTermMatches(term, node) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeSelectorTermMatches evaluates the term the way the scheduler does: all
+// the requirements must match and an empty term matches no node
+func nodeSelectorTermMatches(term corev1.NodeSelectorTerm, node *corev1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, req := range term.MatchExpressions {
+		if !nodeSelectorRequirementMatches(req, labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, req := range term.MatchFields {
+		// metadata.name is the only field supported by the scheduler
+		if req.Key != "metadata.name" || !nodeSelectorRequirementMatches(req, labels.Set{req.Key: node.Name}) {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeSelectorRequirementMatches(req corev1.NodeSelectorRequirement, nodeLabels labels.Set) bool {
+	var op selection.Operator
+	switch req.Operator {
+	case corev1.NodeSelectorOpIn:
+		op = selection.In
+	case corev1.NodeSelectorOpNotIn:
+		op = selection.NotIn
+	case corev1.NodeSelectorOpExists:
+		op = selection.Exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		op = selection.DoesNotExist
+	case corev1.NodeSelectorOpGt:
+		op = selection.GreaterThan
+	case corev1.NodeSelectorOpLt:
+		op = selection.LessThan
+	default:
+		return false
+	}
+	requirement, err := labels.NewRequirement(req.Key, op, req.Values)
+	if err != nil {
+		return false
+	}
+	return requirement.Matches(nodeLabels)
+}
@@ -0,0 +1,103 @@
+package daemonset
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("FindWorkerProfileOverlaps", func() {
+	workerNode := func(name string, nodeLabels map[string]string) corev1.Node {
+		nodeLabels["node-role.kubernetes.io/worker"] = ""
+		nodeLabels["kubernetes.io/os"] = "linux"
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels}}
+	}
+	nodes := []corev1.Node{
+		workerNode("gpu-node", map[string]string{"pool": "gpu"}),
+		workerNode("sriov-node", map[string]string{"pool": "sriov"}),
+		workerNode("compute-node", map[string]string{}),
+		{ObjectMeta: metav1.ObjectMeta{Name: "master-node", Labels: map[string]string{
+			"node-role.kubernetes.io/master": "", "kubernetes.io/os": "linux"}}},
+	}
+
+	It("disjoint profiles do not overlap", func() {
+		profiles := []nfdv1.WorkerProfile{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "sriov", NodeSelector: map[string]string{"pool": "sriov"}},
+			{Name: "compute", NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "node-role.kubernetes.io/worker", Operator: corev1.NodeSelectorOpExists},
+							{Key: "pool", Operator: corev1.NodeSelectorOpDoesNotExist},
+						},
+					}},
+				},
+			}},
+		}
+
+		Expect(FindWorkerProfileOverlaps(profiles, nodes)).To(BeEmpty())
+	})
+
+	It("nodes selected by several profiles are reported", func() {
+		profiles := []nfdv1.WorkerProfile{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "accelerators", NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu", "sriov"}},
+						},
+					}},
+				},
+			}},
+			{Name: "all"},
+		}
+
+		Expect(FindWorkerProfileOverlaps(profiles, nodes)).To(Equal([]string{
+			"node gpu-node is selected by profiles gpu, accelerators, all",
+			"node sriov-node is selected by profiles accelerators, all",
+		}))
+	})
+})
+
+var _ = Describe("SelectWorkerNodes", func() {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{
+			"node-role.kubernetes.io/worker": "", "kubernetes.io/os": "linux", "pool": "gpu"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "compute-node", Labels: map[string]string{
+			"node-role.kubernetes.io/worker": "", "kubernetes.io/os": "linux"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "master-node", Labels: map[string]string{
+			"node-role.kubernetes.io/master": "", "kubernetes.io/os": "linux"}}},
+	}
+
+	It("the nodes selected by any of the profiles are returned", func() {
+		profiles := []nfdv1.WorkerProfile{
+			{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+			{Name: "all"},
+		}
+
+		Expect(SelectWorkerNodes(profiles, nodes).UnsortedList()).To(ConsistOf("gpu-node", "compute-node"))
+		Expect(SelectWorkerNodes(profiles[:1], nodes).UnsortedList()).To(ConsistOf("gpu-node"))
+	})
+})
+
+var _ = Describe("getWorkerProfileAffinity", func() {
+	It("default worker affinity is used when the profile has no node affinity", func() {
+		Expect(getWorkerProfileAffinity(&nfdv1.WorkerProfile{})).To(Equal(getWorkerAffinity()))
+	})
+
+	It("preferred terms alone keep the default required worker terms", func() {
+		preferred := []corev1.PreferredSchedulingTerm{{Weight: 1, Preference: corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpExists}},
+		}}}
+		profile := nfdv1.WorkerProfile{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: preferred}}
+
+		affinity := getWorkerProfileAffinity(&profile)
+		Expect(affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal(preferred))
+		Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution).
+			To(Equal(getWorkerAffinity().NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution))
+	})
+})
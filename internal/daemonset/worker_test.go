@@ -22,7 +22,7 @@ var _ = Describe("getWorkerToleration", func() {
 			},
 		}
 
-		res := getWorkerTolerations(&nfdCR)
+		res := getWorkerTolerations(&nfdCR.GetWorkerProfiles()[0])
 		Expect(res).To(Equal(expectedTolerations))
 	})
 
@@ -65,7 +65,7 @@ var _ = Describe("getWorkerToleration", func() {
 			},
 		}
 
-		res := getWorkerTolerations(&nfdCR)
+		res := getWorkerTolerations(&nfdCR.GetWorkerProfiles()[0])
 		Expect(res).To(Equal(expectedTolerations))
 	})
 })
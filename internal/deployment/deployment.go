@@ -22,7 +22,9 @@ import (
 	"strings"
 
 	"k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,9 +36,11 @@ import (
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 )
 
-const (
-	defaultServicePort int = 12000
-)
+// MasterLeaseName is the name of the Lease the nfd-master replicas use for
+// their leader election, in the namespace of the NodeFeatureDiscovery. It is
+// hardcoded in nfd-master, hence shared by the instances of a namespace, of
+// which the webhook only lets one run more than one replica
+const MasterLeaseName = "nfd-master.nfd.kubernetes.io"
 
 //go:generate mockgen -source=deployment.go -package=deployment -destination=mock_deployment.go DeploymentAPI
 
@@ -45,6 +49,7 @@ type DeploymentAPI interface {
 	SetGCDeploymentAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, gcDep *v1.Deployment, operandImage string) error
 	DeleteDeployment(ctx context.Context, namespace, name string) error
 	GetDeployment(ctx context.Context, namespace, name string) (*v1.Deployment, error)
+	GetMasterLeader(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (string, error)
 }
 
 type deployment struct {
@@ -60,11 +65,11 @@ func NewDeploymentAPI(client client.Client, scheme *runtime.Scheme) DeploymentAP
 }
 
 func (d *deployment) SetMasterDeploymentAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, masterDep *v1.Deployment, operandImage string) error {
-	standartLabels := map[string]string{"app": "nfd-master"}
+	standartLabels := map[string]string{"app": nfdInstance.ComponentName("nfd-master")}
 	masterDep.ObjectMeta.Labels = standartLabels
 
 	masterDep.Spec = v1.DeploymentSpec{
-		Replicas: ptr.To[int32](1),
+		Replicas: ptr.To(nfdInstance.Spec.Operand.MasterReplicaCount()),
 		Selector: &metav1.LabelSelector{
 			MatchLabels: standartLabels,
 		},
@@ -73,10 +78,11 @@ func (d *deployment) SetMasterDeploymentAsDesired(nfdInstance *nfdv1.NodeFeature
 				Labels: standartLabels,
 			},
 			Spec: corev1.PodSpec{
-				ServiceAccountName: "nfd-master",
-				RestartPolicy:      corev1.RestartPolicyAlways,
-				Tolerations:        getPodsTolerations(nfdInstance),
-				Affinity:           getPodsAffinity(),
+				ServiceAccountName:        "nfd-master",
+				RestartPolicy:             corev1.RestartPolicyAlways,
+				Tolerations:               getPodsTolerations(nfdInstance),
+				Affinity:                  getMasterAffinity(nfdInstance, standartLabels),
+				TopologySpreadConstraints: getMasterTopologySpreadConstraints(nfdInstance, standartLabels),
 				Containers: []corev1.Container{
 					{
 						Name:            "nfd-master",
@@ -88,14 +94,11 @@ func (d *deployment) SetMasterDeploymentAsDesired(nfdInstance *nfdv1.NodeFeature
 						Args:            getArgs(nfdInstance),
 						Env:             getMasterEnvs(nfdInstance),
 						SecurityContext: getMasterSecurityContext(),
-						Resources: corev1.ResourceRequirements{
-							Requests: getRequests(),
-							Limits:   getLimits(),
-						},
-						LivenessProbe:  getLivenessProbe(),
-						ReadinessProbe: getReadinessProbe(),
-						StartupProbe:   getStartupProbe(),
-						Ports:          getPorts(),
+						Resources:       getMasterResources(nfdInstance),
+						LivenessProbe:   getLivenessProbe(),
+						ReadinessProbe:  getReadinessProbe(),
+						StartupProbe:    getStartupProbe(),
+						Ports:           getPorts(nfdInstance.Spec.Operand.MasterPort()),
 					},
 				},
 			},
@@ -106,7 +109,7 @@ func (d *deployment) SetMasterDeploymentAsDesired(nfdInstance *nfdv1.NodeFeature
 
 func (d *deployment) SetGCDeploymentAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, gcDep *v1.Deployment, operandImage string) error {
 	gcDep.ObjectMeta.Labels = map[string]string{"app": "nfd"}
-	matchLabels := map[string]string{"app": "nfd-gc"}
+	matchLabels := map[string]string{"app": nfdInstance.ComponentName("nfd-gc")}
 	gcDep.Spec = v1.DeploymentSpec{
 		Replicas: ptr.To[int32](1),
 		Selector: &metav1.LabelSelector{
@@ -127,11 +130,13 @@ func (d *deployment) SetGCDeploymentAsDesired(nfdInstance *nfdv1.NodeFeatureDisc
 						Command: []string{
 							"nfd-gc",
 						},
+						Args:            []string{fmt.Sprintf("-port=%d", nfdInstance.Spec.Operand.GCPort())},
 						Env:             getEnvs(),
 						SecurityContext: getGCSecurityContext(),
+						Resources:       getGCResources(nfdInstance),
 						LivenessProbe:   getLivenessProbe(),
 						ReadinessProbe:  getReadinessProbe(),
-						Ports:           getPorts(),
+						Ports:           getPorts(nfdInstance.Spec.Operand.GCPort()),
 					},
 				},
 				NodeSelector: nfdInstance.Spec.Operand.GCNodeSelector,
@@ -162,6 +167,24 @@ func (d *deployment) GetDeployment(ctx context.Context, namespace, name string)
 	return dep, err
 }
 
+// GetMasterLeader returns the identity of the nfd-master replica holding the
+// leader election lease, or an empty string when the replicas do not run a
+// leader election or no leader has been elected yet
+func (d *deployment) GetMasterLeader(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (string, error) {
+	if !nfdInstance.Spec.Operand.MasterLeaderElection() {
+		return "", nil
+	}
+	lease := &coordinationv1.Lease{}
+	err := d.client.Get(ctx, client.ObjectKey{Namespace: nfdInstance.Namespace, Name: MasterLeaseName}, lease)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get lease %s/%s: %w", nfdInstance.Namespace, MasterLeaseName, err)
+	}
+	return ptr.Deref(lease.Spec.HolderIdentity, ""), nil
+}
+
 func getPodsTolerations(nfdInstance *nfdv1.NodeFeatureDiscovery) []corev1.Toleration {
 	basicTolerations := []corev1.Toleration{
 		{
@@ -212,6 +235,45 @@ func getPodsAffinity() *corev1.Affinity {
 	}
 }
 
+// getMasterAffinity returns the affinity of the nfd-master pods. With several
+// replicas, the pods prefer not to share a node so that draining a single
+// control-plane node leaves a master running
+func getMasterAffinity(nfdInstance *nfdv1.NodeFeatureDiscovery, podLabels map[string]string) *corev1.Affinity {
+	affinity := getPodsAffinity()
+	if !nfdInstance.Spec.Operand.MasterLeaderElection() {
+		return affinity
+	}
+	affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: podLabels},
+					TopologyKey:   corev1.LabelHostname,
+				},
+			},
+		},
+	}
+	return affinity
+}
+
+// getMasterTopologySpreadConstraints spreads the nfd-master replicas across
+// the zones of the cluster, on a best effort basis so that clusters with a
+// single zone can still schedule all of them
+func getMasterTopologySpreadConstraints(nfdInstance *nfdv1.NodeFeatureDiscovery, podLabels map[string]string) []corev1.TopologySpreadConstraint {
+	if !nfdInstance.Spec.Operand.MasterLeaderElection() {
+		return nil
+	}
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       corev1.LabelTopologyZone,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: podLabels},
+		},
+	}
+}
+
 func getImagePullPolicy(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.PullPolicy {
 	if nfdInstance.Spec.Operand.ImagePullPolicy != "" {
 		return corev1.PullPolicy(nfdInstance.Spec.Operand.ImagePullPolicy)
@@ -220,7 +282,11 @@ func getImagePullPolicy(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.PullPoli
 }
 
 func getArgs(nfdInstance *nfdv1.NodeFeatureDiscovery) []string {
-	args := make([]string, 0, 4)
+	args := make([]string, 0, 7)
+	args = append(args, fmt.Sprintf("--port=%d", nfdInstance.Spec.Operand.MasterPort()))
+	if nfdInstance.Spec.Instance != "" {
+		args = append(args, fmt.Sprintf("--instance=%s", nfdInstance.Spec.Instance))
+	}
 	if len(nfdInstance.Spec.ExtraLabelNs) != 0 {
 		args = append(args, fmt.Sprintf("--extra-label-ns=%s", strings.Join(nfdInstance.Spec.ExtraLabelNs, ",")))
 	}
@@ -236,6 +302,10 @@ func getArgs(nfdInstance *nfdv1.NodeFeatureDiscovery) []string {
 		args = append(args, "--enable-taints")
 	}
 
+	if nfdInstance.Spec.Operand.MasterLeaderElection() {
+		args = append(args, "--enable-leader-election")
+	}
+
 	return args
 }
 
@@ -338,15 +408,32 @@ func getStartupProbe() *corev1.Probe {
 	}
 }
 
-func getPorts() []corev1.ContainerPort {
+func getPorts(port int32) []corev1.ContainerPort {
 	return []corev1.ContainerPort{
 		{
-			ContainerPort: 8080,
+			ContainerPort: port,
 			Name:          "http",
 		},
 	}
 }
 
+func getMasterResources(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.ResourceRequirements {
+	if nfdInstance.Spec.Operand.MasterResources != nil {
+		return *nfdInstance.Spec.Operand.MasterResources
+	}
+	return corev1.ResourceRequirements{
+		Requests: getRequests(),
+		Limits:   getLimits(),
+	}
+}
+
+func getGCResources(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.ResourceRequirements {
+	if nfdInstance.Spec.Operand.GCResources != nil {
+		return *nfdInstance.Spec.Operand.GCResources
+	}
+	return corev1.ResourceRequirements{}
+}
+
 func getLimits() corev1.ResourceList {
 	return corev1.ResourceList{
 		corev1.ResourceCPU:    resource.MustParse("300m"),
@@ -27,10 +27,13 @@ import (
 	"github.com/openshift/cluster-nfd-operator/internal/client"
 	"go.uber.org/mock/gomock"
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
@@ -75,6 +78,129 @@ var _ = Describe("SetMasterDeploymentAsDesired", func() {
 		Expect(err).To(BeNil())
 		Expect(masterDep).To(BeComparableTo(testMasterDep))
 	})
+
+	It("instance name is passed to nfd-master and suffixed to its labels", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+			},
+		}
+		masterDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetMasterDeploymentAsDesired(&nfdCR, &masterDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		expectedLabels := map[string]string{"app": "nfd-master-team-a"}
+		Expect(masterDep.Labels).To(Equal(expectedLabels))
+		Expect(masterDep.Spec.Selector.MatchLabels).To(Equal(expectedLabels))
+		Expect(masterDep.Spec.Template.Labels).To(Equal(expectedLabels))
+		Expect(masterDep.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--instance=team-a"))
+	})
+
+	It("service port flows into the args and container port of nfd-master", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:       "test-image",
+					ServicePort: 12345,
+				},
+			},
+		}
+		masterDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetMasterDeploymentAsDesired(&nfdCR, &masterDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		container := masterDep.Spec.Template.Spec.Containers[0]
+		Expect(container.Args).To(ContainElement("--port=12345"))
+		Expect(container.Ports).To(Equal([]corev1.ContainerPort{{Name: "http", ContainerPort: 12345}}))
+		Expect(container.LivenessProbe.HTTPGet.Port.StrVal).To(Equal("http"))
+	})
+
+	It("configured resources replace the default ones", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:           "test-image",
+					MasterResources: &resources,
+				},
+			},
+		}
+		masterDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetMasterDeploymentAsDesired(&nfdCR, &masterDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		container := masterDep.Spec.Template.Spec.Containers[0]
+		Expect(container.Resources).To(Equal(resources))
+		Expect(container.Env).To(ContainElement(HaveField("ValueFrom.ResourceFieldRef.Resource", "limits.memory")))
+	})
+
+	It("several replicas elect a leader and are spread across nodes and zones", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:          "test-image",
+					MasterReplicas: ptr.To[int32](3),
+				},
+			},
+		}
+		masterDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetMasterDeploymentAsDesired(&nfdCR, &masterDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		podLabels := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nfd-master"}}
+		podSpec := masterDep.Spec.Template.Spec
+		Expect(masterDep.Spec.Replicas).To(Equal(ptr.To[int32](3)))
+		Expect(podSpec.Containers[0].Args).To(ContainElement("--enable-leader-election"))
+		Expect(podSpec.Affinity.NodeAffinity).To(Equal(getPodsAffinity().NodeAffinity))
+		Expect(podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.WeightedPodAffinityTerm{
+			{
+				Weight: 100,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: podLabels,
+					TopologyKey:   "kubernetes.io/hostname",
+				},
+			},
+		}))
+		Expect(podSpec.TopologySpreadConstraints).To(Equal([]corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     podLabels,
+			},
+		}))
+	})
+
+	It("a single replica does not elect a leader", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:          "test-image",
+					MasterReplicas: ptr.To[int32](1),
+				},
+			},
+		}
+		masterDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetMasterDeploymentAsDesired(&nfdCR, &masterDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		podSpec := masterDep.Spec.Template.Spec
+		Expect(masterDep.Spec.Replicas).To(Equal(ptr.To[int32](1)))
+		Expect(podSpec.Containers[0].Args).NotTo(ContainElement("--enable-leader-election"))
+		Expect(podSpec.Affinity.PodAntiAffinity).To(BeNil())
+		Expect(podSpec.TopologySpreadConstraints).To(BeNil())
+	})
 })
 
 var _ = Describe("SetGCDeploymentAsDesired", func() {
@@ -126,6 +252,26 @@ var _ = Describe("SetGCDeploymentAsDesired", func() {
 		Expect(err).To(BeNil())
 		Expect(masterDep).To(BeComparableTo(testMasterDep))
 	})
+
+	It("configured resources are applied to nfd-gc", func() {
+		resources := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:       "test-image",
+					GCResources: &resources,
+				},
+			},
+		}
+		gcDep := appsv1.Deployment{}
+
+		err := deploymentAPI.SetGCDeploymentAsDesired(&nfdCR, &gcDep, nfdCR.Spec.Operand.Image)
+
+		Expect(err).To(BeNil())
+		Expect(gcDep.Spec.Template.Spec.Containers[0].Resources).To(Equal(resources))
+	})
 })
 
 var _ = Describe("DeleteDeployment", func() {
@@ -213,6 +359,60 @@ var _ = Describe("GetDeployment", func() {
 	})
 })
 
+var _ = Describe("GetMasterLeader", func() {
+	var (
+		ctrl          *gomock.Controller
+		clnt          *client.MockClient
+		deploymentAPI DeploymentAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		deploymentAPI = NewDeploymentAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	haCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+		Spec: nfdv1.NodeFeatureDiscoverySpec{
+			Operand: nfdv1.OperandSpec{MasterReplicas: ptr.To[int32](2)},
+		},
+	}
+	leaseKey := ctrlclient.ObjectKey{Namespace: "test-namespace", Name: MasterLeaseName}
+
+	It("the holder of the lease is the leader", func() {
+		clnt.EXPECT().Get(ctx, leaseKey, gomock.Any()).DoAndReturn(
+			func(_ interface{}, _ interface{}, lease *coordinationv1.Lease, _ ...ctrlclient.GetOption) error {
+				lease.Spec.HolderIdentity = ptr.To("nfd-master-7d9f-abcde")
+				return nil
+			},
+		)
+		leader, err := deploymentAPI.GetMasterLeader(ctx, &haCR)
+		Expect(err).To(BeNil())
+		Expect(leader).To(Equal("nfd-master-7d9f-abcde"))
+	})
+
+	It("no leader elected yet", func() {
+		clnt.EXPECT().Get(ctx, leaseKey, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+		leader, err := deploymentAPI.GetMasterLeader(ctx, &haCR)
+		Expect(err).To(BeNil())
+		Expect(leader).To(BeEmpty())
+	})
+
+	It("error flow", func() {
+		clnt.EXPECT().Get(ctx, leaseKey, gomock.Any()).Return(fmt.Errorf("some error"))
+		_, err := deploymentAPI.GetMasterLeader(ctx, &haCR)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("a single replica has no leader", func() {
+		leader, err := deploymentAPI.GetMasterLeader(ctx, &nfdv1.NodeFeatureDiscovery{})
+		Expect(err).To(BeNil())
+		Expect(leader).To(BeEmpty())
+	})
+})
+
 var _ = Describe("getPodsTolerations", func() {
 	It("no tolerations defined in the NFD CR", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{
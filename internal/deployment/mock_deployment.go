@@ -71,6 +71,21 @@ func (mr *MockDeploymentAPIMockRecorder) GetDeployment(ctx, namespace, name any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployment", reflect.TypeOf((*MockDeploymentAPI)(nil).GetDeployment), ctx, namespace, name)
 }
 
+// GetMasterLeader mocks base method.
+func (m *MockDeploymentAPI) GetMasterLeader(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMasterLeader", ctx, nfdInstance)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMasterLeader indicates an expected call of GetMasterLeader.
+func (mr *MockDeploymentAPIMockRecorder) GetMasterLeader(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMasterLeader", reflect.TypeOf((*MockDeploymentAPI)(nil).GetMasterLeader), ctx, nfdInstance)
+}
+
 // SetGCDeploymentAsDesired mocks base method.
 func (m *MockDeploymentAPI) SetGCDeploymentAsDesired(nfdInstance *v1.NodeFeatureDiscovery, gcDep *v10.Deployment, operandImage string) error {
 	m.ctrl.T.Helper()
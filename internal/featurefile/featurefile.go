@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featurefile
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+const (
+	// nodeKeyPrefix prefixes the ConfigMap keys listing the feature files of
+	// each node. File keys always contain a "_", which node names never do
+	nodeKeyPrefix = "node."
+
+	// NodeFilesBuckets is the number of ConfigMaps the keys listing the
+	// feature files of the nodes are spread over, by a hash of the node name,
+	// so that they fit in ConfigMaps whatever the size of the cluster and a
+	// change of a node only rewrites the ConfigMap of its bucket
+	NodeFilesBuckets = 16
+)
+
+// ConfigMapName returns the name of the ConfigMap holding the feature files
+// of the NodeFeatureFiles of the instance
+func ConfigMapName(nfdInstance *nfdv1.NodeFeatureDiscovery) string {
+	return nfdInstance.ComponentName("nfd-feature-files")
+}
+
+// NodeFilesConfigMapName returns the name of the ConfigMap holding the keys
+// listing the feature files of the nodes of a bucket, next to the ConfigMap
+// holding the feature files
+func NodeFilesConfigMapName(configMapName string, bucket int) string {
+	return fmt.Sprintf("%s-nodes-%d", configMapName, bucket)
+}
+
+// FileKey returns the ConfigMap key of a feature file of a NodeFeatureFile,
+// which is also the name of the file installed in features.d. Prefixing the
+// file name with the NodeFeatureFile name keeps the files of different
+// NodeFeatureFiles apart
+func FileKey(nffName, fileName string) string {
+	return nffName + "_" + fileName
+}
+
+// NodeKey returns the ConfigMap key listing the feature files of a node
+func NodeKey(nodeName string) string {
+	return nodeKeyPrefix + nodeName
+}
+
+// NodeBucket returns the bucket of the ConfigMap listing the feature files of
+// the node
+func NodeBucket(nodeName string) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(nodeName))
+	return int(hash.Sum32() % NodeFilesBuckets)
+}
+
+// SelectNodes returns the sorted names of the nodes selected by the node
+// selector of the NodeFeatureFile. A missing selector selects all the nodes
+func SelectNodes(nff *nfdopenshiftiov1alpha1.NodeFeatureFile, nodes []corev1.Node) ([]string, error) {
+	selector := labels.Everything()
+	if nff.Spec.NodeSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(nff.Spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node selector of NodeFeatureFile %s/%s: %w", nff.Namespace, nff.Name, err)
+		}
+	}
+
+	names := []string{}
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			names = append(names, node.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+//go:generate mockgen -source=featurefile.go -package=featurefile -destination=mock_featurefile.go FeatureFileAPI
+
+type FeatureFileAPI interface {
+	GetNodeFeatureFiles(ctx context.Context, namespace string) ([]nfdopenshiftiov1alpha1.NodeFeatureFile, error)
+	GetWorkerNodeNames(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (sets.Set[string], error)
+	SetFeatureFilesConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+		nffs []nfdopenshiftiov1alpha1.NodeFeatureFile, cm *corev1.ConfigMap) error
+	SetNodeFilesConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, nodeFiles map[string]string,
+		cm *corev1.ConfigMap) error
+	UpdateNodeFeatureFileStatus(ctx context.Context, nff *nfdopenshiftiov1alpha1.NodeFeatureFile, nodes []corev1.Node, workerNodes sets.Set[string]) error
+}
+
+type featureFile struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func NewFeatureFileAPI(client client.Client, scheme *runtime.Scheme) FeatureFileAPI {
+	return &featureFile{
+		client: client,
+		scheme: scheme,
+	}
+}
+
+// GetNodeFeatureFiles returns the NodeFeatureFiles of the namespace sorted by
+// name
+func (f *featureFile) GetNodeFeatureFiles(ctx context.Context, namespace string) ([]nfdopenshiftiov1alpha1.NodeFeatureFile, error) {
+	nffList := nfdopenshiftiov1alpha1.NodeFeatureFileList{}
+	if err := f.client.List(ctx, &nffList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatureFiles in %s: %w", namespace, err)
+	}
+	nffs := nffList.Items
+	sort.Slice(nffs, func(i, j int) bool { return nffs[i].Name < nffs[j].Name })
+	return nffs, nil
+}
+
+// GetWorkerNodeNames returns the names of the nodes running a nfd-worker pod
+// of the instance, whatever its worker profile
+func (f *featureFile) GetWorkerNodeNames(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) (sets.Set[string], error) {
+	pods := corev1.PodList{}
+	err := f.client.List(ctx, &pods, client.InNamespace(nfdInstance.Namespace),
+		client.MatchingLabels{"app": nfdInstance.ComponentName("nfd-worker")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker pods in %s: %w", nfdInstance.Namespace, err)
+	}
+	names := sets.New[string]()
+	for _, pod := range pods.Items {
+		if pod.Spec.NodeName != "" {
+			names.Insert(pod.Spec.NodeName)
+		}
+	}
+	return names, nil
+}
+
+// GetNodeFiles returns, for each bucket, the keys listing the feature files
+// to install on each of its nodes. NodeFeatureFiles with an invalid node
+// selector are left out
+func GetNodeFiles(nffs []nfdopenshiftiov1alpha1.NodeFeatureFile, nodes []corev1.Node) []map[string]string {
+	nodeFiles := map[string][]string{}
+	for i := range nffs {
+		nff := &nffs[i]
+		nodeNames, err := SelectNodes(nff, nodes)
+		if err != nil {
+			continue
+		}
+		fileKeys := make([]string, 0, len(nff.Spec.Files))
+		for name := range nff.Spec.Files {
+			fileKeys = append(fileKeys, FileKey(nff.Name, name))
+		}
+		for _, nodeName := range nodeNames {
+			nodeFiles[nodeName] = append(nodeFiles[nodeName], fileKeys...)
+		}
+	}
+
+	buckets := make([]map[string]string, NodeFilesBuckets)
+	for i := range buckets {
+		buckets[i] = map[string]string{}
+	}
+	for nodeName, fileKeys := range nodeFiles {
+		sort.Strings(fileKeys)
+		buckets[NodeBucket(nodeName)][NodeKey(nodeName)] = strings.Join(fileKeys, "\n") + "\n"
+	}
+	return buckets
+}
+
+// SetFeatureFilesConfigMapAsDesired stores the feature files of the
+// NodeFeatureFiles in the ConfigMap, the nodes they are installed on are
+// listed in the ConfigMaps of the buckets. NodeFeatureFiles with an invalid
+// node selector are left out
+func (f *featureFile) SetFeatureFilesConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	nffs []nfdopenshiftiov1alpha1.NodeFeatureFile, cm *corev1.ConfigMap) error {
+	data := map[string]string{}
+	for i := range nffs {
+		if _, err := SelectNodes(&nffs[i], nil); err != nil {
+			continue
+		}
+		for name, content := range nffs[i].Spec.Files {
+			data[FileKey(nffs[i].Name, name)] = content
+		}
+	}
+
+	cm.Data = data
+
+	return controllerutil.SetControllerReference(nfdInstance, cm, f.scheme)
+}
+
+// SetNodeFilesConfigMapAsDesired stores the keys listing the feature files of
+// the nodes of a bucket in its ConfigMap
+func (f *featureFile) SetNodeFilesConfigMapAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	nodeFiles map[string]string, cm *corev1.ConfigMap) error {
+	cm.Data = nodeFiles
+
+	return controllerutil.SetControllerReference(nfdInstance, cm, f.scheme)
+}
+
+// UpdateNodeFeatureFileStatus reports the nodes selected by the
+// NodeFeatureFile that run a nfd-worker pod, the status is only patched if it
+// changes. The files of a NodeFeatureFile with an invalid node selector are
+// not installed on any node
+func (f *featureFile) UpdateNodeFeatureFileStatus(ctx context.Context, nff *nfdopenshiftiov1alpha1.NodeFeatureFile,
+	nodes []corev1.Node, workerNodes sets.Set[string]) error {
+	// an invalid selector is reported by the caller, the NodeFeatureFile
+	// then applies to no node
+	selected, _ := SelectNodes(nff, nodes)
+	applied := []string{}
+	for _, name := range selected {
+		if workerNodes.Has(name) {
+			applied = append(applied, name)
+		}
+	}
+	if sets.New(applied...).Equal(sets.New(nff.Status.Nodes...)) {
+		return nil
+	}
+
+	unmodified := nff.DeepCopy()
+	nff.Status.Nodes = applied
+	if err := f.client.Status().Patch(ctx, nff, client.MergeFrom(unmodified)); err != nil {
+		return fmt.Errorf("failed to patch the status of NodeFeatureFile %s/%s: %w", nff.Namespace, nff.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featurefile
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+func newNode(name string, labels map[string]string) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newNodeFeatureFile(name string, selector *metav1.LabelSelector, files map[string]string) nfdopenshiftiov1alpha1.NodeFeatureFile {
+	return nfdopenshiftiov1alpha1.NodeFeatureFile{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+		Spec: nfdopenshiftiov1alpha1.NodeFeatureFileSpec{
+			NodeSelector: selector,
+			Files:        files,
+		},
+	}
+}
+
+var _ = Describe("SelectNodes", func() {
+	nodes := []corev1.Node{
+		newNode("node-b", map[string]string{"pool": "gpu"}),
+		newNode("node-a", map[string]string{"pool": "gpu"}),
+		newNode("node-c", map[string]string{"pool": "cpu"}),
+	}
+
+	It("a missing selector selects all the nodes", func() {
+		nff := newNodeFeatureFile("all", nil, nil)
+
+		names, err := SelectNodes(&nff, nodes)
+		Expect(err).To(BeNil())
+		Expect(names).To(Equal([]string{"node-a", "node-b", "node-c"}))
+	})
+
+	It("the selector selects the nodes with matching labels", func() {
+		nff := newNodeFeatureFile("gpu", &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}}, nil)
+
+		names, err := SelectNodes(&nff, nodes)
+		Expect(err).To(BeNil())
+		Expect(names).To(Equal([]string{"node-a", "node-b"}))
+	})
+
+	It("error flow, invalid selector", func() {
+		nff := newNodeFeatureFile("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: "Unknown"},
+		}}, nil)
+
+		_, err := SelectNodes(&nff, nodes)
+		Expect(err).To(MatchError(ContainSubstring("invalid node selector of NodeFeatureFile test-namespace/invalid")))
+	})
+})
+
+var _ = Describe("GetNodeFeatureFiles", func() {
+	var (
+		ctrl           *gomock.Controller
+		clnt           *client.MockClient
+		featureFileAPI FeatureFileAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		featureFileAPI = NewFeatureFileAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("the NodeFeatureFiles of the namespace are sorted by name", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("test-namespace")).DoAndReturn(
+			func(_ interface{}, list *nfdopenshiftiov1alpha1.NodeFeatureFileList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdopenshiftiov1alpha1.NodeFeatureFile{
+					newNodeFeatureFile("b", nil, nil),
+					newNodeFeatureFile("a", nil, nil),
+				}
+				return nil
+			},
+		)
+
+		nffs, err := featureFileAPI.GetNodeFeatureFiles(ctx, "test-namespace")
+		Expect(err).To(BeNil())
+		Expect(nffs).To(HaveLen(2))
+		Expect(nffs[0].Name).To(Equal("a"))
+		Expect(nffs[1].Name).To(Equal("b"))
+	})
+
+	It("error flow, failed to list the NodeFeatureFiles", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := featureFileAPI.GetNodeFeatureFiles(ctx, "test-namespace")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetWorkerNodeNames", func() {
+	var (
+		ctrl           *gomock.Controller
+		clnt           *client.MockClient
+		featureFileAPI FeatureFileAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		featureFileAPI = NewFeatureFileAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("the nodes of the scheduled worker pods of the instance are returned", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{Instance: "team-a"},
+		}
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("test-namespace"),
+			ctrlclient.MatchingLabels{"app": "nfd-worker-team-a"}).DoAndReturn(
+			func(_ interface{}, list *corev1.PodList, _ ...ctrlclient.ListOption) error {
+				list.Items = []corev1.Pod{
+					{Spec: corev1.PodSpec{NodeName: "node-a"}},
+					{Spec: corev1.PodSpec{NodeName: "node-b"}},
+					{Spec: corev1.PodSpec{}},
+				}
+				return nil
+			},
+		)
+
+		names, err := featureFileAPI.GetWorkerNodeNames(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(names).To(Equal(sets.New("node-a", "node-b")))
+	})
+})
+
+var _ = Describe("SetFeatureFilesConfigMapAsDesired", func() {
+	var (
+		featureFileAPI FeatureFileAPI
+	)
+
+	BeforeEach(func() {
+		featureFileAPI = NewFeatureFileAPI(nil, scheme)
+	})
+
+	ctx := context.Background()
+
+	nfdCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+	}
+	nffs := []nfdopenshiftiov1alpha1.NodeFeatureFile{
+		newNodeFeatureFile("all", nil, map[string]string{"site": "site=paris\n"}),
+		newNodeFeatureFile("gpu", &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+			map[string]string{"gpu": "gpu.present\n", "cuda": "cuda=12\n"}),
+		newNodeFeatureFile("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: "Unknown"},
+		}}, map[string]string{"invalid": "invalid\n"}),
+	}
+
+	It("the files are stored in the configmap", func() {
+		cm := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-feature-files", Namespace: "test-namespace"},
+		}
+
+		err := featureFileAPI.SetFeatureFilesConfigMapAsDesired(ctx, &nfdCR, nffs, &cm)
+		Expect(err).To(BeNil())
+		Expect(cm.Data).To(Equal(map[string]string{
+			"all_site": "site=paris\n",
+			"gpu_gpu":  "gpu.present\n",
+			"gpu_cuda": "cuda=12\n",
+		}))
+		Expect(metav1.IsControlledBy(&cm, &nfdCR)).To(BeTrue())
+	})
+
+	It("the files of the nodes of a bucket are stored in its configmap", func() {
+		cm := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-feature-files-nodes-0", Namespace: "test-namespace"},
+		}
+		nodeFiles := map[string]string{"node.node-a": "all_site\n"}
+
+		err := featureFileAPI.SetNodeFilesConfigMapAsDesired(ctx, &nfdCR, nodeFiles, &cm)
+		Expect(err).To(BeNil())
+		Expect(cm.Data).To(Equal(nodeFiles))
+		Expect(metav1.IsControlledBy(&cm, &nfdCR)).To(BeTrue())
+	})
+})
+
+var _ = Describe("GetNodeFiles", func() {
+	nffs := []nfdopenshiftiov1alpha1.NodeFeatureFile{
+		newNodeFeatureFile("all", nil, map[string]string{"site": "site=paris\n"}),
+		newNodeFeatureFile("gpu", &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}},
+			map[string]string{"gpu": "gpu.present\n", "cuda": "cuda=12\n"}),
+		newNodeFeatureFile("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: "Unknown"},
+		}}, map[string]string{"invalid": "invalid\n"}),
+	}
+
+	It("the files of each node are listed in the bucket of the node", func() {
+		nodes := []corev1.Node{
+			newNode("node-a", map[string]string{"pool": "gpu"}),
+			newNode("node-b", map[string]string{"pool": "cpu"}),
+		}
+
+		expected := make([]map[string]string, NodeFilesBuckets)
+		for bucket := range expected {
+			expected[bucket] = map[string]string{}
+		}
+		expected[NodeBucket("node-a")]["node.node-a"] = "all_site\ngpu_cuda\ngpu_gpu\n"
+		expected[NodeBucket("node-b")]["node.node-b"] = "all_site\n"
+
+		Expect(GetNodeFiles(nffs, nodes)).To(Equal(expected))
+	})
+})
+
+var _ = Describe("UpdateNodeFeatureFileStatus", func() {
+	var (
+		ctrl           *gomock.Controller
+		clnt           *client.MockClient
+		statusWriter   *client.MockStatusWriter
+		featureFileAPI FeatureFileAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		statusWriter = client.NewMockStatusWriter(ctrl)
+		featureFileAPI = NewFeatureFileAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	nodes := []corev1.Node{
+		newNode("node-a", map[string]string{"pool": "gpu"}),
+		newNode("node-b", map[string]string{"pool": "gpu"}),
+		newNode("node-c", map[string]string{"pool": "cpu"}),
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "gpu"}}
+
+	It("the selected nodes running a worker are reported", func() {
+		nff := newNodeFeatureFile("gpu", selector, nil)
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &nff, gomock.Any()).Return(nil),
+		)
+
+		err := featureFileAPI.UpdateNodeFeatureFileStatus(ctx, &nff, nodes, sets.New("node-a", "node-c"))
+		Expect(err).To(BeNil())
+		Expect(nff.Status.Nodes).To(Equal([]string{"node-a"}))
+	})
+
+	It("the status is not patched when the nodes are unchanged", func() {
+		nff := newNodeFeatureFile("gpu", selector, nil)
+		nff.Status.Nodes = []string{"node-b", "node-a"}
+
+		err := featureFileAPI.UpdateNodeFeatureFileStatus(ctx, &nff, nodes, sets.New("node-a", "node-b"))
+		Expect(err).To(BeNil())
+	})
+
+	It("no node is reported for an invalid selector", func() {
+		nff := newNodeFeatureFile("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: "Unknown"},
+		}}, nil)
+		nff.Status.Nodes = []string{"node-a"}
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &nff, gomock.Any()).Return(nil),
+		)
+
+		err := featureFileAPI.UpdateNodeFeatureFileStatus(ctx, &nff, nodes, sets.New("node-a"))
+		Expect(err).To(BeNil())
+		Expect(nff.Status.Nodes).To(BeEmpty())
+	})
+
+	It("error flow, failed to patch the status", func() {
+		nff := newNodeFeatureFile("gpu", selector, nil)
+		gomock.InOrder(
+			clnt.EXPECT().Status().Return(statusWriter),
+			statusWriter.EXPECT().Patch(ctx, &nff, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := featureFileAPI.UpdateNodeFeatureFileStatus(ctx, &nff, nodes, sets.New("node-a"))
+		Expect(err).To(HaveOccurred())
+	})
+})
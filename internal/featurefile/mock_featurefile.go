@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: featurefile.go
+//
+// Generated by this command:
+//
+//	mockgen -source=featurefile.go -package=featurefile -destination=mock_featurefile.go FeatureFileAPI
+//
+
+// Package featurefile is a generated GoMock package.
+package featurefile
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	v1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	gomock "go.uber.org/mock/gomock"
+	v10 "k8s.io/api/core/v1"
+	sets "k8s.io/apimachinery/pkg/util/sets"
+)
+
+// MockFeatureFileAPI is a mock of FeatureFileAPI interface.
+type MockFeatureFileAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeatureFileAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockFeatureFileAPIMockRecorder is the mock recorder for MockFeatureFileAPI.
+type MockFeatureFileAPIMockRecorder struct {
+	mock *MockFeatureFileAPI
+}
+
+// NewMockFeatureFileAPI creates a new mock instance.
+func NewMockFeatureFileAPI(ctrl *gomock.Controller) *MockFeatureFileAPI {
+	mock := &MockFeatureFileAPI{ctrl: ctrl}
+	mock.recorder = &MockFeatureFileAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFeatureFileAPI) EXPECT() *MockFeatureFileAPIMockRecorder {
+	return m.recorder
+}
+
+// GetNodeFeatureFiles mocks base method.
+func (m *MockFeatureFileAPI) GetNodeFeatureFiles(ctx context.Context, namespace string) ([]v1alpha1.NodeFeatureFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNodeFeatureFiles", ctx, namespace)
+	ret0, _ := ret[0].([]v1alpha1.NodeFeatureFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNodeFeatureFiles indicates an expected call of GetNodeFeatureFiles.
+func (mr *MockFeatureFileAPIMockRecorder) GetNodeFeatureFiles(ctx, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodeFeatureFiles", reflect.TypeOf((*MockFeatureFileAPI)(nil).GetNodeFeatureFiles), ctx, namespace)
+}
+
+// GetWorkerNodeNames mocks base method.
+func (m *MockFeatureFileAPI) GetWorkerNodeNames(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) (sets.Set[string], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkerNodeNames", ctx, nfdInstance)
+	ret0, _ := ret[0].(sets.Set[string])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkerNodeNames indicates an expected call of GetWorkerNodeNames.
+func (mr *MockFeatureFileAPIMockRecorder) GetWorkerNodeNames(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkerNodeNames", reflect.TypeOf((*MockFeatureFileAPI)(nil).GetWorkerNodeNames), ctx, nfdInstance)
+}
+
+// SetFeatureFilesConfigMapAsDesired mocks base method.
+func (m *MockFeatureFileAPI) SetFeatureFilesConfigMapAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, nffs []v1alpha1.NodeFeatureFile, cm *v10.ConfigMap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFeatureFilesConfigMapAsDesired", ctx, nfdInstance, nffs, cm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetFeatureFilesConfigMapAsDesired indicates an expected call of SetFeatureFilesConfigMapAsDesired.
+func (mr *MockFeatureFileAPIMockRecorder) SetFeatureFilesConfigMapAsDesired(ctx, nfdInstance, nffs, cm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFeatureFilesConfigMapAsDesired", reflect.TypeOf((*MockFeatureFileAPI)(nil).SetFeatureFilesConfigMapAsDesired), ctx, nfdInstance, nffs, cm)
+}
+
+// SetNodeFilesConfigMapAsDesired mocks base method.
+func (m *MockFeatureFileAPI) SetNodeFilesConfigMapAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, nodeFiles map[string]string, cm *v10.ConfigMap) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNodeFilesConfigMapAsDesired", ctx, nfdInstance, nodeFiles, cm)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNodeFilesConfigMapAsDesired indicates an expected call of SetNodeFilesConfigMapAsDesired.
+func (mr *MockFeatureFileAPIMockRecorder) SetNodeFilesConfigMapAsDesired(ctx, nfdInstance, nodeFiles, cm any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNodeFilesConfigMapAsDesired", reflect.TypeOf((*MockFeatureFileAPI)(nil).SetNodeFilesConfigMapAsDesired), ctx, nfdInstance, nodeFiles, cm)
+}
+
+// UpdateNodeFeatureFileStatus mocks base method.
+func (m *MockFeatureFileAPI) UpdateNodeFeatureFileStatus(ctx context.Context, nff *v1alpha1.NodeFeatureFile, nodes []v10.Node, workerNodes sets.Set[string]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNodeFeatureFileStatus", ctx, nff, nodes, workerNodes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNodeFeatureFileStatus indicates an expected call of UpdateNodeFeatureFileStatus.
+func (mr *MockFeatureFileAPIMockRecorder) UpdateNodeFeatureFileStatus(ctx, nff, nodes, workerNodes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodeFeatureFileStatus", reflect.TypeOf((*MockFeatureFileAPI)(nil).UpdateNodeFeatureFileStatus), ctx, nff, nodes, workerNodes)
+}
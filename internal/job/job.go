@@ -64,7 +64,7 @@ func (j *job) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job,
 func (j *job) CreatePruneJob(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, operandImage string) error {
 	pruneJob := batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "nfd-prune",
+			Name:      nfdInstance.ComponentName("nfd-prune"),
 			Namespace: nfdInstance.Namespace,
 			Labels:    map[string]string{"app": "nfd"},
 		},
@@ -72,7 +72,7 @@ func (j *job) CreatePruneJob(ctx context.Context, nfdInstance *nfdv1.NodeFeature
 			Completions: ptr.To[int32](1),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{"app": "nfd-prune"},
+					Labels: map[string]string{"app": nfdInstance.ComponentName("nfd-prune")},
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: "nfd-prune",
@@ -87,9 +87,10 @@ func (j *job) CreatePruneJob(ctx context.Context, nfdInstance *nfdv1.NodeFeature
 							Command: []string{
 								"nfd-master",
 							},
-							Args:            []string{"-prune"},
+							Args:            getPruneArgs(nfdInstance),
 							Env:             getEnvs(),
 							SecurityContext: getSecurityContext(),
+							Resources:       getPruneResources(nfdInstance),
 						},
 					},
 				},
@@ -105,6 +106,21 @@ func (j *job) CreatePruneJob(ctx context.Context, nfdInstance *nfdv1.NodeFeature
 	return j.client.Create(ctx, &pruneJob)
 }
 
+func getPruneResources(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.ResourceRequirements {
+	if nfdInstance.Spec.Operand.PruneResources != nil {
+		return *nfdInstance.Spec.Operand.PruneResources
+	}
+	return corev1.ResourceRequirements{}
+}
+
+func getPruneArgs(nfdInstance *nfdv1.NodeFeatureDiscovery) []string {
+	args := []string{"-prune"}
+	if nfdInstance.Spec.Instance != "" {
+		args = append(args, fmt.Sprintf("-instance=%s", nfdInstance.Spec.Instance))
+	}
+	return args
+}
+
 func getImagePullPolicy(nfdInstance *nfdv1.NodeFeatureDiscovery) corev1.PullPolicy {
 	if nfdInstance.Spec.Operand.ImagePullPolicy != "" {
 		return corev1.PullPolicy(nfdInstance.Spec.Operand.ImagePullPolicy)
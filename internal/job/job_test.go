@@ -28,6 +28,7 @@ import (
 	"go.uber.org/mock/gomock"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -132,4 +133,58 @@ var _ = Describe("CreatePruneJob", func() {
 		Expect(err).To(BeNil())
 		Expect(createdJob.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullPolicy("IfNotPresent")))
 	})
+	It("passes the instance name to the prune job and suffixes its name", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "nfd",
+			},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				Operand: nfdv1.OperandSpec{
+					Image: "test-image",
+				},
+			},
+		}
+
+		var createdJob *batchv1.Job
+		clnt.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&batchv1.Job{})).DoAndReturn(
+			func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+				createdJob = obj.(*batchv1.Job)
+				return nil
+			})
+
+		err := jobAPI.CreatePruneJob(ctx, &nfdCR, "test-image")
+		Expect(err).To(BeNil())
+		Expect(createdJob.Name).To(Equal("nfd-prune-team-a"))
+		Expect(createdJob.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"-prune", "-instance=team-a"}))
+	})
+	It("applies the configured resources to the prune job", func() {
+		resources := corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+		}
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      "nfd",
+			},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					Image:          "test-image",
+					PruneResources: &resources,
+				},
+			},
+		}
+
+		var createdJob *batchv1.Job
+		clnt.EXPECT().Create(ctx, gomock.AssignableToTypeOf(&batchv1.Job{})).DoAndReturn(
+			func(_ context.Context, obj ctrlclient.Object, _ ...ctrlclient.CreateOption) error {
+				createdJob = obj.(*batchv1.Job)
+				return nil
+			})
+
+		err := jobAPI.CreatePruneJob(ctx, &nfdCR, "test-image")
+		Expect(err).To(BeNil())
+		Expect(createdJob.Spec.Template.Spec.Containers[0].Resources).To(Equal(resources))
+	})
 })
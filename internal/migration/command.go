@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const commandUsage = `Usage: %s migrate-rules [--dry-run] [--report-namespace <namespace>]
+
+Migrates every nfd.openshift.io NodeFeatureRule to a standalone
+nfd.k8s-sigs.io NodeFeatureRule, which is kept when the nfd.openshift.io one
+is deleted. The outcome is listed in the %s ConfigMap, or in the %s
+ConfigMap with --dry-run, which changes nothing.
+
+A single NodeFeatureRule is migrated by the operator when annotated with
+%s: "true".
+
+  --dry-run           Only report what the migration would do
+  --report-namespace  Namespace of the report ConfigMap (default "%s")
+`
+
+// defaultReportNamespace is the namespace the operator is installed in by
+// default
+const defaultReportNamespace = "openshift-nfd"
+
+// RunCommand runs the migrate-rules subcommand with its arguments against the
+// cluster reached with the client returned by newClient, and returns the exit
+// code of the program
+func RunCommand(ctx context.Context, programName string, args []string, stdout, stderr io.Writer,
+	newClient func() (client.Client, error)) int {
+	usage := func() {
+		fmt.Fprintf(stderr, commandUsage, programName, ReportConfigMapName, DryRunReportConfigMapName,
+			MigrateAnnotation, defaultReportNamespace)
+	}
+
+	flags := flag.NewFlagSet(programName+" migrate-rules", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = usage
+	dryRun := flags.Bool("dry-run", false, "Only report what the migration would do.")
+	reportNamespace := flags.String("report-namespace", defaultReportNamespace, "Namespace of the report ConfigMap.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if len(flags.Args()) > 0 {
+		usage()
+		return 2
+	}
+
+	clnt, err := newClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to create the client: %v\n", err)
+		return 1
+	}
+	migrationAPI := NewMigrationAPI(clnt, *reportNamespace)
+
+	var records []Record
+	if *dryRun {
+		records, err = migrationAPI.PlanMigration(ctx)
+	} else {
+		records, err = migrationAPI.MigrateAll(ctx)
+	}
+	printRecords(stdout, records, *dryRun)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+
+	// the NodeFeatureRules migrated before an error are reported as well
+	if reportErr := migrationAPI.WriteReport(ctx, records, *dryRun); reportErr != nil {
+		fmt.Fprintln(stderr, reportErr)
+		return 1
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+func printRecords(out io.Writer, records []Record, dryRun bool) {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tACTION")
+	conflicts := 0
+	for _, record := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", record.Namespace, record.Name, record.Action)
+		if record.Action == ActionConflict {
+			conflicts++
+		}
+	}
+	w.Flush()
+
+	if dryRun {
+		fmt.Fprintln(out, "Dry run, nothing was changed.")
+	}
+	if conflicts > 0 {
+		fmt.Fprintf(out, "%d NodeFeatureRules are not migrated: a nfd.k8s-sigs.io NodeFeatureRule with the same name "+
+			"already exists and does not mirror them.\n", conflicts)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+const (
+	// MigrateAnnotation set to "true" on a nfd.openshift.io NodeFeatureRule
+	// requests its migration. The operator stops mirroring the migrated
+	// NodeFeatureRules
+	MigrateAnnotation = "nfd.openshift.io/migrate"
+
+	// MigratedFromAnnotation is set on the migrated nfd.k8s-sigs.io
+	// NodeFeatureRules to the namespace/name of their former source
+	MigratedFromAnnotation = "nfd.openshift.io/migrated-from"
+
+	// ReportConfigMapName is the name of the ConfigMap listing the migrated
+	// NodeFeatureRules
+	ReportConfigMapName = "nfd-rule-migration"
+
+	// DryRunReportConfigMapName is the name of the ConfigMap listing what a
+	// migration would do
+	DryRunReportConfigMapName = "nfd-rule-migration-dry-run"
+)
+
+// Action is what the migration does, or would do, for one NodeFeatureRule
+type Action string
+
+const (
+	// ActionCreate creates the missing nfd.k8s-sigs.io NodeFeatureRule
+	ActionCreate Action = "Create"
+	// ActionRelease drops the owner reference of the mirror, restoring its
+	// rules if they were modified
+	ActionRelease Action = "Release"
+	// ActionConflict leaves a nfd.k8s-sigs.io NodeFeatureRule with the same
+	// name that does not mirror the source untouched, the source is not
+	// migrated
+	ActionConflict Action = "Conflict"
+	// ActionNone is reported for NodeFeatureRules already migrated
+	ActionNone Action = "AlreadyMigrated"
+)
+
+// Record is the migration of one nfd.openshift.io NodeFeatureRule
+type Record struct {
+	Namespace string
+	Name      string
+	Action    Action
+}
+
+// IsMigrationRequested returns true if the NodeFeatureRule is to be migrated
+// rather than mirrored
+func IsMigrationRequested(nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) bool {
+	return nfr.Annotations[MigrateAnnotation] == "true"
+}
+
+//go:generate mockgen -source=migration.go -package=migration -destination=mock_migration.go MigrationAPI
+
+type MigrationAPI interface {
+	PlanMigration(ctx context.Context) ([]Record, error)
+	MigrateAll(ctx context.Context) ([]Record, error)
+	Migrate(ctx context.Context, nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) (Record, error)
+	WriteReport(ctx context.Context, records []Record, dryRun bool) error
+}
+
+type migration struct {
+	client          client.Client
+	reportNamespace string
+}
+
+func NewMigrationAPI(client client.Client, reportNamespace string) MigrationAPI {
+	return &migration{
+		client:          client,
+		reportNamespace: reportNamespace,
+	}
+}
+
+// PlanMigration returns what the migration of every nfd.openshift.io
+// NodeFeatureRule would do, without changing anything
+func (m *migration) PlanMigration(ctx context.Context) ([]Record, error) {
+	sources, err := m.listSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(sources))
+	for i := range sources {
+		nfr := &sources[i]
+		mirror, err := m.getMirror(ctx, nfr)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, Record{Namespace: nfr.Namespace, Name: nfr.Name, Action: getAction(nfr, mirror)})
+	}
+	return records, nil
+}
+
+// MigrateAll migrates every nfd.openshift.io NodeFeatureRule, see Migrate.
+// The records of the NodeFeatureRules migrated before an error are returned
+// with the error
+func (m *migration) MigrateAll(ctx context.Context) ([]Record, error) {
+	sources, err := m.listSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(sources))
+	for i := range sources {
+		record, err := m.Migrate(ctx, &sources[i])
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Migrate turns the nfd.k8s-sigs.io mirror of the NodeFeatureRule into a
+// standalone object with the rules of the source, which is annotated so that
+// it is not mirrored anymore. Deleting the source afterwards keeps the
+// migrated object
+func (m *migration) Migrate(ctx context.Context, nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) (Record, error) {
+	record := Record{Namespace: nfr.Namespace, Name: nfr.Name}
+	mirror, err := m.getMirror(ctx, nfr)
+	if err != nil {
+		return record, err
+	}
+	record.Action = getAction(nfr, mirror)
+	if record.Action == ActionConflict || record.Action == ActionNone {
+		return record, nil
+	}
+
+	// the source is marked first, so that it is not mirrored again
+	if !IsMigrationRequested(nfr) {
+		unmodified := nfr.DeepCopy()
+		metav1.SetMetaDataAnnotation(&nfr.ObjectMeta, MigrateAnnotation, "true")
+		if err := m.client.Patch(ctx, nfr, client.MergeFrom(unmodified)); err != nil {
+			return record, fmt.Errorf("failed to annotate NodeFeatureRule %s/%s: %w", nfr.Namespace, nfr.Name, err)
+		}
+	}
+
+	if mirror == nil {
+		mirror = &nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        nfr.Name,
+				Namespace:   nfr.Namespace,
+				Labels:      nfr.Labels,
+				Annotations: map[string]string{MigratedFromAnnotation: nfr.Namespace + "/" + nfr.Name},
+			},
+			Spec: *nfr.Spec.DeepCopy(),
+		}
+		if err := m.client.Create(ctx, mirror); err != nil {
+			return record, fmt.Errorf("failed to create NodeFeatureRule %s/%s in nfd.k8s-sigs.io group: %w", nfr.Namespace, nfr.Name, err)
+		}
+		return record, nil
+	}
+
+	unmodified := mirror.DeepCopy()
+	ownerReferences := []metav1.OwnerReference{}
+	for _, ref := range mirror.OwnerReferences {
+		if ref.UID != nfr.UID {
+			ownerReferences = append(ownerReferences, ref)
+		}
+	}
+	mirror.OwnerReferences = ownerReferences
+	metav1.SetMetaDataAnnotation(&mirror.ObjectMeta, MigratedFromAnnotation, nfr.Namespace+"/"+nfr.Name)
+	mirror.Spec = *nfr.Spec.DeepCopy()
+	if err := m.client.Patch(ctx, mirror, client.MergeFrom(unmodified)); err != nil {
+		return record, fmt.Errorf("failed to release NodeFeatureRule %s/%s in nfd.k8s-sigs.io group: %w", nfr.Namespace, nfr.Name, err)
+	}
+	return record, nil
+}
+
+// WriteReport stores the records in the report ConfigMap, one key per
+// NodeFeatureRule. The dry run report is replaced on each run, while the
+// records of the migration report are added to the previous ones
+func (m *migration) WriteReport(ctx context.Context, records []Record, dryRun bool) error {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ReportConfigMapName,
+			Namespace: m.reportNamespace,
+		},
+	}
+	if dryRun {
+		cm.Name = DryRunReportConfigMapName
+	}
+
+	_, err := controllerutil.CreateOrPatch(ctx, m.client, cm, func() error {
+		if dryRun || cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		for _, record := range records {
+			cm.Data[record.Namespace+"."+record.Name] = string(record.Action)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write the migration report %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return nil
+}
+
+// listSources returns the nfd.openshift.io NodeFeatureRules sorted by
+// namespace and name
+func (m *migration) listSources(ctx context.Context) ([]nfdopenshiftiov1alpha1.NodeFeatureRule, error) {
+	nfrList := nfdopenshiftiov1alpha1.NodeFeatureRuleList{}
+	if err := m.client.List(ctx, &nfrList); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatureRules: %w", err)
+	}
+	sources := nfrList.Items
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Namespace != sources[j].Namespace {
+			return sources[i].Namespace < sources[j].Namespace
+		}
+		return sources[i].Name < sources[j].Name
+	})
+	return sources, nil
+}
+
+// getMirror returns the nfd.k8s-sigs.io NodeFeatureRule with the same name
+// as the source, or nil if there is none
+func (m *migration) getMirror(ctx context.Context,
+	nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) (*nfdk8ssigsiov1alpha1.NodeFeatureRule, error) {
+	mirror := &nfdk8ssigsiov1alpha1.NodeFeatureRule{}
+	err := m.client.Get(ctx, client.ObjectKey{Namespace: nfr.Namespace, Name: nfr.Name}, mirror)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NodeFeatureRule %s/%s in nfd.k8s-sigs.io group: %w", nfr.Namespace, nfr.Name, err)
+	}
+	return mirror, nil
+}
+
+func getAction(nfr *nfdopenshiftiov1alpha1.NodeFeatureRule, mirror *nfdk8ssigsiov1alpha1.NodeFeatureRule) Action {
+	switch {
+	case mirror == nil:
+		return ActionCreate
+	case metav1.IsControlledBy(mirror, nfr):
+		return ActionRelease
+	case mirror.Annotations[MigratedFromAnnotation] == nfr.Namespace+"/"+nfr.Name:
+		// the migrated object belongs to the users, it may have been modified
+		return ActionNone
+	default:
+		return ActionConflict
+	}
+}
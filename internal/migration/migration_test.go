@@ -0,0 +1,320 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+func newSource(name string) nfdopenshiftiov1alpha1.NodeFeatureRule {
+	return nfdopenshiftiov1alpha1.NodeFeatureRule{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace", UID: k8stypes.UID("uid-" + name)},
+		Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdopenshiftiov1alpha1.Rule{{Name: name, Labels: map[string]string{name: "true"}}},
+		},
+	}
+}
+
+func newOwnedMirror(source *nfdopenshiftiov1alpha1.NodeFeatureRule) nfdk8ssigsiov1alpha1.NodeFeatureRule {
+	mirror := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+		ObjectMeta: metav1.ObjectMeta{Name: source.Name, Namespace: source.Namespace},
+	}
+	Expect(controllerutil.SetControllerReference(source, &mirror, scheme)).To(Succeed())
+	return mirror
+}
+
+var _ = Describe("PlanMigration", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		mAPI MigrationAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mAPI = NewMigrationAPI(clnt, "openshift-nfd")
+	})
+
+	ctx := context.Background()
+
+	It("every NodeFeatureRule gets an action, nothing is changed", func() {
+		mirrored := newSource("mirrored")
+		missing := newSource("missing")
+		conflicting := newSource("conflicting")
+		migrated := newSource("migrated")
+
+		mirrors := map[string]*nfdk8ssigsiov1alpha1.NodeFeatureRule{}
+		owned := newOwnedMirror(&mirrored)
+		mirrors["mirrored"] = &owned
+		mirrors["conflicting"] = &nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "conflicting", Namespace: "test-namespace"},
+		}
+		mirrors["migrated"] = &nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "migrated",
+				Namespace:   "test-namespace",
+				Annotations: map[string]string{MigratedFromAnnotation: "test-namespace/migrated"},
+			},
+		}
+
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdopenshiftiov1alpha1.NodeFeatureRuleList{})).DoAndReturn(
+			func(_ context.Context, list *nfdopenshiftiov1alpha1.NodeFeatureRuleList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdopenshiftiov1alpha1.NodeFeatureRule{mirrored, missing, conflicting, migrated}
+				return nil
+			})
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{})).DoAndReturn(
+			func(_ context.Context, key ctrlclient.ObjectKey, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...ctrlclient.GetOption) error {
+				mirror, ok := mirrors[key.Name]
+				if !ok {
+					return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				*obj = *mirror
+				return nil
+			}).Times(4)
+
+		records, err := mAPI.PlanMigration(ctx)
+
+		Expect(err).To(BeNil())
+		Expect(records).To(Equal([]Record{
+			{Namespace: "test-namespace", Name: "conflicting", Action: ActionConflict},
+			{Namespace: "test-namespace", Name: "migrated", Action: ActionNone},
+			{Namespace: "test-namespace", Name: "mirrored", Action: ActionRelease},
+			{Namespace: "test-namespace", Name: "missing", Action: ActionCreate},
+		}))
+	})
+
+	It("failure to list the NodeFeatureRules", func() {
+		clnt.EXPECT().List(ctx, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		records, err := mAPI.PlanMigration(ctx)
+
+		Expect(err).To(HaveOccurred())
+		Expect(records).To(BeNil())
+	})
+})
+
+var _ = Describe("Migrate", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		mAPI MigrationAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mAPI = NewMigrationAPI(clnt, "openshift-nfd")
+	})
+
+	ctx := context.Background()
+
+	It("the owner reference of the mirror is dropped and its rules restored", func() {
+		source := newSource("test")
+		mirror := newOwnedMirror(&source)
+		mirror.Spec.Rules = []nfdopenshiftiov1alpha1.Rule{{Name: "edited"}}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{})).DoAndReturn(
+				func(_ context.Context, _ ctrlclient.ObjectKey, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...ctrlclient.GetOption) error {
+					*obj = mirror
+					return nil
+				}),
+			clnt.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdopenshiftiov1alpha1.NodeFeatureRule{}), gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj *nfdopenshiftiov1alpha1.NodeFeatureRule, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+					Expect(IsMigrationRequested(obj)).To(BeTrue())
+					return nil
+				}),
+			clnt.EXPECT().Patch(ctx, gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}), gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+					Expect(obj.OwnerReferences).To(BeEmpty())
+					Expect(obj.Annotations).To(HaveKeyWithValue(MigratedFromAnnotation, "test-namespace/test"))
+					Expect(obj.Spec).To(Equal(source.Spec))
+					return nil
+				}),
+		)
+
+		record, err := mAPI.Migrate(ctx, &source)
+
+		Expect(err).To(BeNil())
+		Expect(record).To(Equal(Record{Namespace: "test-namespace", Name: "test", Action: ActionRelease}))
+	})
+
+	It("the missing mirror is created without owner", func() {
+		source := newSource("test")
+		source.Annotations = map[string]string{MigrateAnnotation: "true"}
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "test")),
+			clnt.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, obj *nfdk8ssigsiov1alpha1.NodeFeatureRule, _ ...ctrlclient.CreateOption) error {
+					Expect(obj.OwnerReferences).To(BeEmpty())
+					Expect(obj.Annotations).To(HaveKeyWithValue(MigratedFromAnnotation, "test-namespace/test"))
+					Expect(obj.Spec).To(Equal(source.Spec))
+					return nil
+				}),
+		)
+
+		record, err := mAPI.Migrate(ctx, &source)
+
+		Expect(err).To(BeNil())
+		Expect(record.Action).To(Equal(ActionCreate))
+	})
+
+	It("a conflicting object is left untouched", func() {
+		source := newSource("test")
+
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+		record, err := mAPI.Migrate(ctx, &source)
+
+		Expect(err).To(BeNil())
+		Expect(record.Action).To(Equal(ActionConflict))
+	})
+
+	It("failure to annotate the source", func() {
+		source := newSource("test")
+
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "test")),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		_, err := mAPI.Migrate(ctx, &source)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("WriteReport", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		mAPI MigrationAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		mAPI = NewMigrationAPI(clnt, "openshift-nfd")
+	})
+
+	ctx := context.Background()
+	records := []Record{{Namespace: "test-namespace", Name: "test", Action: ActionRelease}}
+
+	It("the records are added to the migration report", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Namespace: "openshift-nfd", Name: ReportConfigMapName}, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ ctrlclient.ObjectKey, cm *corev1.ConfigMap, _ ...ctrlclient.GetOption) error {
+					cm.Data = map[string]string{"other-namespace.previous": string(ActionCreate)}
+					return nil
+				}),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, cm *corev1.ConfigMap, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+					Expect(cm.Data).To(Equal(map[string]string{
+						"other-namespace.previous": "Create",
+						"test-namespace.test":      "Release",
+					}))
+					return nil
+				}),
+		)
+
+		Expect(mAPI.WriteReport(ctx, records, false)).To(Succeed())
+	})
+
+	It("the dry run report is replaced", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Namespace: "openshift-nfd", Name: DryRunReportConfigMapName}, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ ctrlclient.ObjectKey, cm *corev1.ConfigMap, _ ...ctrlclient.GetOption) error {
+					cm.Data = map[string]string{"test-namespace.deleted": string(ActionCreate)}
+					return nil
+				}),
+			clnt.EXPECT().Patch(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, cm *corev1.ConfigMap, _ ctrlclient.Patch, _ ...ctrlclient.PatchOption) error {
+					Expect(cm.Data).To(Equal(map[string]string{"test-namespace.test": "Release"}))
+					return nil
+				}),
+		)
+
+		Expect(mAPI.WriteReport(ctx, records, true)).To(Succeed())
+	})
+})
+
+var _ = Describe("RunCommand", func() {
+	var (
+		ctrl           *gomock.Controller
+		clnt           *client.MockClient
+		stdout, stderr *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	ctx := context.Background()
+	newClient := func() (ctrlclient.Client, error) {
+		return clnt, nil
+	}
+
+	It("the dry run only writes its report", func() {
+		source := newSource("test")
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any()).DoAndReturn(
+				func(_ context.Context, list *nfdopenshiftiov1alpha1.NodeFeatureRuleList, _ ...ctrlclient.ListOption) error {
+					list.Items = []nfdopenshiftiov1alpha1.NodeFeatureRule{source}
+					return nil
+				}),
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "test")),
+			clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Namespace: "nfd", Name: DryRunReportConfigMapName}, gomock.Any()).
+				Return(apierrors.NewNotFound(schema.GroupResource{}, DryRunReportConfigMapName)),
+			clnt.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		)
+
+		code := RunCommand(ctx, "nfd-operator", []string{"--dry-run", "--report-namespace", "nfd"}, stdout, stderr, newClient)
+
+		Expect(code).To(Equal(0))
+		Expect(stdout.String()).To(ContainSubstring("test-namespace  test  Create"))
+		Expect(stdout.String()).To(ContainSubstring("Dry run"))
+	})
+
+	It("unknown arguments print the usage", func() {
+		code := RunCommand(ctx, "nfd-operator", []string{"now"}, stdout, stderr, newClient)
+
+		Expect(code).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("Usage: nfd-operator migrate-rules"))
+	})
+})
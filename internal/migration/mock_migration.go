@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: migration.go
+//
+// Generated by this command:
+//
+//	mockgen -source=migration.go -package=migration -destination=mock_migration.go MigrationAPI
+//
+
+// Package migration is a generated GoMock package.
+package migration
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMigrationAPI is a mock of MigrationAPI interface.
+type MockMigrationAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockMigrationAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockMigrationAPIMockRecorder is the mock recorder for MockMigrationAPI.
+type MockMigrationAPIMockRecorder struct {
+	mock *MockMigrationAPI
+}
+
+// NewMockMigrationAPI creates a new mock instance.
+func NewMockMigrationAPI(ctrl *gomock.Controller) *MockMigrationAPI {
+	mock := &MockMigrationAPI{ctrl: ctrl}
+	mock.recorder = &MockMigrationAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMigrationAPI) EXPECT() *MockMigrationAPIMockRecorder {
+	return m.recorder
+}
+
+// Migrate mocks base method.
+func (m *MockMigrationAPI) Migrate(ctx context.Context, nfr *v1alpha1.NodeFeatureRule) (Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Migrate", ctx, nfr)
+	ret0, _ := ret[0].(Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Migrate indicates an expected call of Migrate.
+func (mr *MockMigrationAPIMockRecorder) Migrate(ctx, nfr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Migrate", reflect.TypeOf((*MockMigrationAPI)(nil).Migrate), ctx, nfr)
+}
+
+// MigrateAll mocks base method.
+func (m *MockMigrationAPI) MigrateAll(ctx context.Context) ([]Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrateAll", ctx)
+	ret0, _ := ret[0].([]Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrateAll indicates an expected call of MigrateAll.
+func (mr *MockMigrationAPIMockRecorder) MigrateAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrateAll", reflect.TypeOf((*MockMigrationAPI)(nil).MigrateAll), ctx)
+}
+
+// PlanMigration mocks base method.
+func (m *MockMigrationAPI) PlanMigration(ctx context.Context) ([]Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanMigration", ctx)
+	ret0, _ := ret[0].([]Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanMigration indicates an expected call of PlanMigration.
+func (mr *MockMigrationAPIMockRecorder) PlanMigration(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanMigration", reflect.TypeOf((*MockMigrationAPI)(nil).PlanMigration), ctx)
+}
+
+// WriteReport mocks base method.
+func (m *MockMigrationAPI) WriteReport(ctx context.Context, records []Record, dryRun bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WriteReport", ctx, records, dryRun)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WriteReport indicates an expected call of WriteReport.
+func (mr *MockMigrationAPIMockRecorder) WriteReport(ctx, records, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WriteReport", reflect.TypeOf((*MockMigrationAPI)(nil).WriteReport), ctx, records, dryRun)
+}
@@ -54,12 +54,12 @@ func NewNetworkPolicyAPI(client client.Client, scheme *runtime.Scheme) NetworkPo
 
 func (n *networkPolicy) SetMasterNetworkPolicyAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, np *networkingv1.NetworkPolicy) error {
 	podSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{"app": "nfd-master"},
+		MatchLabels: map[string]string{"app": nfdInstance.ComponentName("nfd-master")},
 	}
 	np.Spec = networkingv1.NetworkPolicySpec{
 		PodSelector: podSelector,
 		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
-		Ingress:     healthProbeIngressRules(),
+		Ingress:     healthProbeIngressRules(nfdInstance.Spec.Operand.MasterPort()),
 		Egress:      requiredEgressRules(),
 	}
 	return controllerutil.SetControllerReference(nfdInstance, np, n.scheme)
@@ -67,12 +67,12 @@ func (n *networkPolicy) SetMasterNetworkPolicyAsDesired(nfdInstance *nfdv1.NodeF
 
 func (n *networkPolicy) SetWorkerNetworkPolicyAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, np *networkingv1.NetworkPolicy) error {
 	podSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{"app": "nfd-worker"},
+		MatchLabels: map[string]string{"app": nfdInstance.ComponentName("nfd-worker")},
 	}
 	np.Spec = networkingv1.NetworkPolicySpec{
 		PodSelector: podSelector,
 		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
-		Ingress:     []networkingv1.NetworkPolicyIngressRule{},
+		Ingress:     healthProbeIngressRules(nfdInstance.Spec.Operand.WorkerPort()),
 		Egress:      requiredEgressRules(),
 	}
 	return controllerutil.SetControllerReference(nfdInstance, np, n.scheme)
@@ -80,12 +80,12 @@ func (n *networkPolicy) SetWorkerNetworkPolicyAsDesired(nfdInstance *nfdv1.NodeF
 
 func (n *networkPolicy) SetGCNetworkPolicyAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, np *networkingv1.NetworkPolicy) error {
 	podSelector := metav1.LabelSelector{
-		MatchLabels: map[string]string{"app": "nfd-gc"},
+		MatchLabels: map[string]string{"app": nfdInstance.ComponentName("nfd-gc")},
 	}
 	np.Spec = networkingv1.NetworkPolicySpec{
 		PodSelector: podSelector,
 		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
-		Ingress:     healthProbeIngressRules(),
+		Ingress:     healthProbeIngressRules(nfdInstance.Spec.Operand.GCPort()),
 		Egress:      requiredEgressRules(),
 	}
 	return controllerutil.SetControllerReference(nfdInstance, np, n.scheme)
@@ -105,8 +105,10 @@ func (n *networkPolicy) DeleteNetworkPolicy(ctx context.Context, namespace, name
 	return nil
 }
 
-func healthProbeIngressRules() []networkingv1.NetworkPolicyIngressRule {
-	httpPort := intstr.FromInt32(8080)
+// healthProbeIngressRules allows ingress to the health and metrics port
+// of a component, on which it is probed by the kubelet and scraped
+func healthProbeIngressRules(port int32) []networkingv1.NetworkPolicyIngressRule {
+	httpPort := intstr.FromInt32(port)
 	tcp := corev1.ProtocolTCP
 	return []networkingv1.NetworkPolicyIngressRule{
 		{
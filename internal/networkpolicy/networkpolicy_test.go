@@ -56,7 +56,7 @@ var _ = Describe("SetMasterNetworkPolicyAsDesired", func() {
 		Expect(np.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}))
 		Expect(np.Spec.Ingress).To(HaveLen(1))
 
-		httpPort := intstr.FromInt32(8080)
+		httpPort := intstr.FromInt32(12000)
 		tcp := corev1.ProtocolTCP
 		Expect(np.Spec.Ingress[0].Ports).To(Equal([]networkingv1.NetworkPolicyPort{
 			{Protocol: &tcp, Port: &httpPort},
@@ -64,6 +64,24 @@ var _ = Describe("SetMasterNetworkPolicyAsDesired", func() {
 
 		assertRequiredEgressRules(np.Spec.Egress)
 	})
+
+	It("should allow ingress on the configured service port", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{ServicePort: 12345},
+			},
+		}
+		np := networkingv1.NetworkPolicy{}
+
+		err := npAPI.SetMasterNetworkPolicyAsDesired(&nfdCR, &np)
+		Expect(err).To(BeNil())
+
+		httpPort := intstr.FromInt32(12345)
+		tcp := corev1.ProtocolTCP
+		Expect(np.Spec.Ingress).To(Equal([]networkingv1.NetworkPolicyIngressRule{
+			{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &httpPort}}},
+		}))
+	})
 })
 
 var _ = Describe("SetWorkerNetworkPolicyAsDesired", func() {
@@ -73,7 +91,7 @@ var _ = Describe("SetWorkerNetworkPolicyAsDesired", func() {
 		npAPI = NewNetworkPolicyAPI(nil, scheme)
 	})
 
-	It("should populate worker network policy with deny-all ingress", func() {
+	It("should populate worker network policy with ingress on the worker port only", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{}
 		np := networkingv1.NetworkPolicy{
 			ObjectMeta: metav1.ObjectMeta{
@@ -87,7 +105,13 @@ var _ = Describe("SetWorkerNetworkPolicyAsDesired", func() {
 
 		Expect(np.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app": "nfd-worker"}))
 		Expect(np.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}))
-		Expect(np.Spec.Ingress).To(BeEmpty())
+		Expect(np.Spec.Ingress).To(HaveLen(1))
+
+		httpPort := intstr.FromInt32(8080)
+		tcp := corev1.ProtocolTCP
+		Expect(np.Spec.Ingress[0].Ports).To(Equal([]networkingv1.NetworkPolicyPort{
+			{Protocol: &tcp, Port: &httpPort},
+		}))
 
 		assertRequiredEgressRules(np.Spec.Egress)
 	})
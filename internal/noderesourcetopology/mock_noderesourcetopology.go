@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: noderesourcetopology.go
+//
+// Generated by this command:
+//
+//	mockgen -source=noderesourcetopology.go -package=noderesourcetopology -destination=mock_noderesourcetopology.go NodeResourceTopologyAPI
+//
+
+// Package noderesourcetopology is a generated GoMock package.
+package noderesourcetopology
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+// MockNodeResourceTopologyAPI is a mock of NodeResourceTopologyAPI interface.
+type MockNodeResourceTopologyAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockNodeResourceTopologyAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockNodeResourceTopologyAPIMockRecorder is the mock recorder for MockNodeResourceTopologyAPI.
+type MockNodeResourceTopologyAPIMockRecorder struct {
+	mock *MockNodeResourceTopologyAPI
+}
+
+// NewMockNodeResourceTopologyAPI creates a new mock instance.
+func NewMockNodeResourceTopologyAPI(ctrl *gomock.Controller) *MockNodeResourceTopologyAPI {
+	mock := &MockNodeResourceTopologyAPI{ctrl: ctrl}
+	mock.recorder = &MockNodeResourceTopologyAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNodeResourceTopologyAPI) EXPECT() *MockNodeResourceTopologyAPIMockRecorder {
+	return m.recorder
+}
+
+// DeleteCRD mocks base method.
+func (m *MockNodeResourceTopologyAPI) DeleteCRD(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCRD", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCRD indicates an expected call of DeleteCRD.
+func (mr *MockNodeResourceTopologyAPIMockRecorder) DeleteCRD(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCRD", reflect.TypeOf((*MockNodeResourceTopologyAPI)(nil).DeleteCRD), ctx)
+}
+
+// SetCRDAsDesired mocks base method.
+func (m *MockNodeResourceTopologyAPI) SetCRDAsDesired(crd *v1.CustomResourceDefinition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCRDAsDesired", crd)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCRDAsDesired indicates an expected call of SetCRDAsDesired.
+func (mr *MockNodeResourceTopologyAPIMockRecorder) SetCRDAsDesired(crd any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCRDAsDesired", reflect.TypeOf((*MockNodeResourceTopologyAPI)(nil).SetCRDAsDesired), crd)
+}
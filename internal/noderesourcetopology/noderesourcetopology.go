@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CRDName is the name of the NodeResourceTopology CRD, the objects of
+	// which are published by nfd-topology-updater
+	CRDName = "noderesourcetopologies.topology.node.k8s.io"
+
+	managedByLabel = "app.kubernetes.io/managed-by"
+	managedByValue = "cluster-nfd-operator"
+)
+
+//go:embed crd/noderesourcetopologies.yaml
+var crdManifest []byte
+
+//go:generate mockgen -source=noderesourcetopology.go -package=noderesourcetopology -destination=mock_noderesourcetopology.go NodeResourceTopologyAPI
+
+type NodeResourceTopologyAPI interface {
+	SetCRDAsDesired(crd *apiextensionsv1.CustomResourceDefinition) error
+	DeleteCRD(ctx context.Context) error
+}
+
+type nodeResourceTopology struct {
+	client client.Client
+}
+
+func NewNodeResourceTopologyAPI(client client.Client) NodeResourceTopologyAPI {
+	return &nodeResourceTopology{
+		client: client,
+	}
+}
+
+// SetCRDAsDesired sets the NodeResourceTopology CRD shipped with the operator.
+// A CRD installed by someone else, e.g. along with a topology aware scheduler,
+// is left untouched. The CRD is cluster scoped and is shared by all the
+// NodeFeatureDiscovery instances, so it has no owner reference
+func (n *nodeResourceTopology) SetCRDAsDesired(crd *apiextensionsv1.CustomResourceDefinition) error {
+	if crd.ResourceVersion != "" && !isManagedByOperator(crd) {
+		return nil
+	}
+
+	desired := apiextensionsv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(crdManifest, &desired); err != nil {
+		return fmt.Errorf("failed to decode the %s CRD manifest: %w", CRDName, err)
+	}
+
+	if crd.Labels == nil {
+		crd.Labels = map[string]string{}
+	}
+	crd.Labels[managedByLabel] = managedByValue
+	if crd.Annotations == nil {
+		crd.Annotations = map[string]string{}
+	}
+	for key, value := range desired.Annotations {
+		crd.Annotations[key] = value
+	}
+	crd.Spec = desired.Spec
+	return nil
+}
+
+// DeleteCRD deletes the NodeResourceTopology CRD, and with it all the
+// NodeResourceTopology objects, if it was installed by the operator
+func (n *nodeResourceTopology) DeleteCRD(ctx context.Context) error {
+	crd := apiextensionsv1.CustomResourceDefinition{}
+	err := n.client.Get(ctx, client.ObjectKey{Name: CRDName}, &crd)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get CRD %s: %w", CRDName, err)
+	}
+	if !isManagedByOperator(&crd) {
+		return nil
+	}
+
+	err = n.client.Delete(ctx, &crd)
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete CRD %s: %w", CRDName, err)
+	}
+	return nil
+}
+
+func isManagedByOperator(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return crd.Labels[managedByLabel] == managedByValue
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package noderesourcetopology
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"go.uber.org/mock/gomock"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("SetCRDAsDesired", func() {
+	var nrtAPI NodeResourceTopologyAPI
+
+	BeforeEach(func() {
+		nrtAPI = NewNodeResourceTopologyAPI(nil)
+	})
+
+	It("should populate a new CRD with the shipped manifest", func() {
+		crd := apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: CRDName}}
+
+		err := nrtAPI.SetCRDAsDesired(&crd)
+		Expect(err).To(BeNil())
+
+		Expect(crd.Labels).To(HaveKeyWithValue(managedByLabel, managedByValue))
+		Expect(crd.Annotations).To(HaveKey("api-approved.kubernetes.io"))
+		Expect(crd.Spec.Group).To(Equal("topology.node.k8s.io"))
+		Expect(crd.Spec.Scope).To(Equal(apiextensionsv1.ClusterScoped))
+		Expect(crd.Spec.Names.Plural + "." + crd.Spec.Group).To(Equal(CRDName))
+		Expect(crd.Spec.Versions).To(HaveLen(1))
+		Expect(crd.Spec.Versions[0].Name).To(Equal("v1alpha2"))
+		Expect(crd.Spec.Versions[0].Storage).To(BeTrue())
+	})
+
+	It("should leave a CRD installed by someone else untouched", func() {
+		crd := apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: CRDName, ResourceVersion: "1"},
+			Spec:       apiextensionsv1.CustomResourceDefinitionSpec{Group: "topology.node.k8s.io"},
+		}
+		expected := crd.DeepCopy()
+
+		err := nrtAPI.SetCRDAsDesired(&crd)
+		Expect(err).To(BeNil())
+		Expect(&crd).To(Equal(expected))
+	})
+})
+
+var _ = Describe("DeleteCRD", func() {
+	var (
+		ctrl   *gomock.Controller
+		clnt   *client.MockClient
+		nrtAPI NodeResourceTopologyAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		nrtAPI = NewNodeResourceTopologyAPI(clnt)
+	})
+
+	ctx := context.Background()
+	getCRD := func(labels map[string]string) func(_ interface{}, _ interface{}, crd *apiextensionsv1.CustomResourceDefinition, _ ...interface{}) error {
+		return func(_ interface{}, _ interface{}, crd *apiextensionsv1.CustomResourceDefinition, _ ...interface{}) error {
+			crd.Name = CRDName
+			crd.Labels = labels
+			return nil
+		}
+	}
+
+	It("CRD installed by the operator is deleted", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getCRD(map[string]string{managedByLabel: managedByValue})),
+			clnt.EXPECT().Delete(ctx, gomock.Any()).Return(nil),
+		)
+
+		err := nrtAPI.DeleteCRD(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("CRD installed by someone else is not deleted", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getCRD(nil))
+
+		err := nrtAPI.DeleteCRD(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("CRD does not exist", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, CRDName))
+
+		err := nrtAPI.DeleteCRD(ctx)
+		Expect(err).To(BeNil())
+	})
+
+	It("failure to get the CRD", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		err := nrtAPI.DeleteCRD(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("failure to delete the CRD", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getCRD(map[string]string{managedByLabel: managedByValue})),
+			clnt.EXPECT().Delete(ctx, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := nrtAPI.DeleteCRD(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: poddisruptionbudget.go
+//
+// Generated by this command:
+//
+//	mockgen -source=poddisruptionbudget.go -package=poddisruptionbudget -destination=mock_poddisruptionbudget.go PodDisruptionBudgetAPI
+//
+
+// Package poddisruptionbudget is a generated GoMock package.
+package poddisruptionbudget
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	gomock "go.uber.org/mock/gomock"
+	v10 "k8s.io/api/policy/v1"
+)
+
+// MockPodDisruptionBudgetAPI is a mock of PodDisruptionBudgetAPI interface.
+type MockPodDisruptionBudgetAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockPodDisruptionBudgetAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockPodDisruptionBudgetAPIMockRecorder is the mock recorder for MockPodDisruptionBudgetAPI.
+type MockPodDisruptionBudgetAPIMockRecorder struct {
+	mock *MockPodDisruptionBudgetAPI
+}
+
+// NewMockPodDisruptionBudgetAPI creates a new mock instance.
+func NewMockPodDisruptionBudgetAPI(ctrl *gomock.Controller) *MockPodDisruptionBudgetAPI {
+	mock := &MockPodDisruptionBudgetAPI{ctrl: ctrl}
+	mock.recorder = &MockPodDisruptionBudgetAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPodDisruptionBudgetAPI) EXPECT() *MockPodDisruptionBudgetAPIMockRecorder {
+	return m.recorder
+}
+
+// DeletePodDisruptionBudget mocks base method.
+func (m *MockPodDisruptionBudgetAPI) DeletePodDisruptionBudget(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePodDisruptionBudget", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePodDisruptionBudget indicates an expected call of DeletePodDisruptionBudget.
+func (mr *MockPodDisruptionBudgetAPIMockRecorder) DeletePodDisruptionBudget(ctx, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePodDisruptionBudget", reflect.TypeOf((*MockPodDisruptionBudgetAPI)(nil).DeletePodDisruptionBudget), ctx, namespace, name)
+}
+
+// SetMasterPodDisruptionBudgetAsDesired mocks base method.
+func (m *MockPodDisruptionBudgetAPI) SetMasterPodDisruptionBudgetAsDesired(nfdInstance *v1.NodeFeatureDiscovery, pdb *v10.PodDisruptionBudget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMasterPodDisruptionBudgetAsDesired", nfdInstance, pdb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMasterPodDisruptionBudgetAsDesired indicates an expected call of SetMasterPodDisruptionBudgetAsDesired.
+func (mr *MockPodDisruptionBudgetAPIMockRecorder) SetMasterPodDisruptionBudgetAsDesired(nfdInstance, pdb any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMasterPodDisruptionBudgetAsDesired", reflect.TypeOf((*MockPodDisruptionBudgetAPI)(nil).SetMasterPodDisruptionBudgetAsDesired), nfdInstance, pdb)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poddisruptionbudget
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+//go:generate mockgen -source=poddisruptionbudget.go -package=poddisruptionbudget -destination=mock_poddisruptionbudget.go PodDisruptionBudgetAPI
+
+type PodDisruptionBudgetAPI interface {
+	SetMasterPodDisruptionBudgetAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, pdb *policyv1.PodDisruptionBudget) error
+	DeletePodDisruptionBudget(ctx context.Context, namespace, name string) error
+}
+
+type podDisruptionBudget struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func NewPodDisruptionBudgetAPI(client client.Client, scheme *runtime.Scheme) PodDisruptionBudgetAPI {
+	return &podDisruptionBudget{
+		client: client,
+		scheme: scheme,
+	}
+}
+
+// SetMasterPodDisruptionBudgetAsDesired keeps all but one nfd-master replica
+// available during voluntary disruptions such as node drains, so that a
+// leader can always be elected
+func (p *podDisruptionBudget) SetMasterPodDisruptionBudgetAsDesired(nfdInstance *nfdv1.NodeFeatureDiscovery, pdb *policyv1.PodDisruptionBudget) error {
+	maxUnavailable := intstr.FromInt32(1)
+	pdb.Spec = policyv1.PodDisruptionBudgetSpec{
+		MaxUnavailable: &maxUnavailable,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": nfdInstance.ComponentName("nfd-master")},
+		},
+	}
+	return controllerutil.SetControllerReference(nfdInstance, pdb, p.scheme)
+}
+
+func (p *podDisruptionBudget) DeletePodDisruptionBudget(ctx context.Context, namespace, name string) error {
+	pdb := policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	err := p.client.Delete(ctx, &pdb)
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete PodDisruptionBudget %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poddisruptionbudget
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"go.uber.org/mock/gomock"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("SetMasterPodDisruptionBudgetAsDesired", func() {
+	var pdbAPI PodDisruptionBudgetAPI
+
+	BeforeEach(func() {
+		pdbAPI = NewPodDisruptionBudgetAPI(nil, scheme)
+	})
+
+	It("should populate master pod disruption budget with correct values", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+		}
+		pdb := policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nfd-master",
+				Namespace: "test-namespace",
+			},
+		}
+
+		err := pdbAPI.SetMasterPodDisruptionBudgetAsDesired(&nfdCR, &pdb)
+		Expect(err).To(BeNil())
+
+		maxUnavailable := intstr.FromInt32(1)
+		Expect(pdb.Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "nfd-master"}))
+		Expect(pdb.OwnerReferences).To(HaveLen(1))
+		Expect(pdb.OwnerReferences[0].Name).To(Equal("nfd-cr"))
+	})
+
+	It("should select the master pods of the instance", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{Instance: "team-a"},
+		}
+		pdb := policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace"}}
+
+		err := pdbAPI.SetMasterPodDisruptionBudgetAsDesired(&nfdCR, &pdb)
+		Expect(err).To(BeNil())
+
+		Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": "nfd-master-team-a"}))
+	})
+})
+
+var _ = Describe("DeletePodDisruptionBudget", func() {
+	var (
+		ctrl   *gomock.Controller
+		clnt   *client.MockClient
+		pdbAPI PodDisruptionBudgetAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		pdbAPI = NewPodDisruptionBudgetAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	name := "pdb-name"
+	namespace := "pdb-namespace"
+	expectedPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+
+	It("failure to delete pod disruption budget from the cluster", func() {
+		clnt.EXPECT().Delete(ctx, expectedPDB).Return(fmt.Errorf("some error"))
+
+		err := pdbAPI.DeletePodDisruptionBudget(ctx, namespace, name)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("pod disruption budget is not present in the cluster", func() {
+		clnt.EXPECT().Delete(ctx, expectedPDB).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+
+		err := pdbAPI.DeletePodDisruptionBudget(ctx, namespace, name)
+		Expect(err).To(BeNil())
+	})
+
+	It("pod disruption budget deleted successfully", func() {
+		clnt.EXPECT().Delete(ctx, expectedPDB).Return(nil)
+
+		err := pdbAPI.DeletePodDisruptionBudget(ctx, namespace, name)
+		Expect(err).To(BeNil())
+	})
+})
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: presets.go
+//
+// Generated by this command:
+//
+//	mockgen -source=presets.go -package=presets -destination=mock_presets.go PresetAPI
+//
+
+// Package presets is a generated GoMock package.
+package presets
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	v1temp1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPresetAPI is a mock of PresetAPI interface.
+type MockPresetAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockPresetAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockPresetAPIMockRecorder is the mock recorder for MockPresetAPI.
+type MockPresetAPIMockRecorder struct {
+	mock *MockPresetAPI
+}
+
+// NewMockPresetAPI creates a new mock instance.
+func NewMockPresetAPI(ctrl *gomock.Controller) *MockPresetAPI {
+	mock := &MockPresetAPI{ctrl: ctrl}
+	mock.recorder = &MockPresetAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPresetAPI) EXPECT() *MockPresetAPIMockRecorder {
+	return m.recorder
+}
+
+// DeletePresetRule mocks base method.
+func (m *MockPresetAPI) DeletePresetRule(ctx context.Context, namespace, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePresetRule", ctx, namespace, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePresetRule indicates an expected call of DeletePresetRule.
+func (mr *MockPresetAPIMockRecorder) DeletePresetRule(ctx, namespace, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePresetRule", reflect.TypeOf((*MockPresetAPI)(nil).DeletePresetRule), ctx, namespace, name)
+}
+
+// GetPresetRules mocks base method.
+func (m *MockPresetAPI) GetPresetRules(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) ([]v1temp1.NodeFeatureRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPresetRules", ctx, nfdInstance)
+	ret0, _ := ret[0].([]v1temp1.NodeFeatureRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPresetRules indicates an expected call of GetPresetRules.
+func (mr *MockPresetAPIMockRecorder) GetPresetRules(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPresetRules", reflect.TypeOf((*MockPresetAPI)(nil).GetPresetRules), ctx, nfdInstance)
+}
+
+// SetPresetRuleAsDesired mocks base method.
+func (m *MockPresetAPI) SetPresetRuleAsDesired(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, preset *Preset, nfr *v1temp1.NodeFeatureRule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPresetRuleAsDesired", ctx, nfdInstance, preset, nfr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPresetRuleAsDesired indicates an expected call of SetPresetRuleAsDesired.
+func (mr *MockPresetAPIMockRecorder) SetPresetRuleAsDesired(ctx, nfdInstance, preset, nfr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPresetRuleAsDesired", reflect.TypeOf((*MockPresetAPI)(nil).SetPresetRuleAsDesired), ctx, nfdInstance, preset, nfr)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presets
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+const (
+	// PresetLabel is set on the NodeFeatureRules created from a preset to the
+	// name of the preset
+	PresetLabel = "nfd.openshift.io/rule-preset"
+
+	// PresetVersionAnnotation is set on the NodeFeatureRules created from a
+	// preset to the version of the preset
+	PresetVersionAnnotation = "nfd.openshift.io/rule-preset-version"
+)
+
+//go:embed catalog/*.yaml
+var catalogFS embed.FS
+
+// catalog holds the presets shipped with the operator, sorted by name
+var catalog = mustLoadCatalog(catalogFS)
+
+// Preset is a curated NodeFeatureRule of the catalog. The version is bumped
+// whenever the rules change, so that the NodeFeatureRules created from an
+// older version are upgraded
+type Preset struct {
+	Name        string                                     `json:"name"`
+	Version     int                                        `json:"version"`
+	Description string                                     `json:"description"`
+	Spec        nfdopenshiftiov1alpha1.NodeFeatureRuleSpec `json:"spec"`
+}
+
+func mustLoadCatalog(fsys fs.FS) []Preset {
+	presets, err := loadCatalog(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return presets
+}
+
+// loadCatalog reads the presets of the catalog directory, one per file
+func loadCatalog(fsys fs.FS) ([]Preset, error) {
+	files, err := fs.Glob(fsys, "catalog/*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	presets := make([]Preset, 0, len(files))
+	names := map[string]bool{}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule preset %s: %w", file, err)
+		}
+		preset := Preset{}
+		if err := yaml.UnmarshalStrict(data, &preset); err != nil {
+			return nil, fmt.Errorf("failed to decode rule preset %s: %w", file, err)
+		}
+		if preset.Name == "" || preset.Version < 1 || len(preset.Spec.Rules) == 0 {
+			return nil, fmt.Errorf("rule preset %s must have a name, a version of at least 1 and rules", file)
+		}
+		if names[preset.Name] {
+			return nil, fmt.Errorf("rule preset %s is defined twice", preset.Name)
+		}
+		names[preset.Name] = true
+		presets = append(presets, preset)
+	}
+
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// Names returns the names of the presets of the catalog
+func Names() []string {
+	names := make([]string, 0, len(catalog))
+	for _, preset := range catalog {
+		names = append(names, preset.Name)
+	}
+	return names
+}
+
+// Get returns the preset of the catalog with the given name
+func Get(name string) (*Preset, bool) {
+	for i := range catalog {
+		if catalog[i].Name == name {
+			return &catalog[i], true
+		}
+	}
+	return nil, false
+}
+
+// RuleName returns the name of the NodeFeatureRule created from the preset
+// for the instance
+func RuleName(nfdInstance *nfdv1.NodeFeatureDiscovery, preset string) string {
+	return nfdInstance.ComponentName("nfd-preset-" + preset)
+}
+
+//go:generate mockgen -source=presets.go -package=presets -destination=mock_presets.go PresetAPI
+
+type PresetAPI interface {
+	SetPresetRuleAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, preset *Preset, nfr *nfdk8ssigsiov1alpha1.NodeFeatureRule) error
+	GetPresetRules(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) ([]nfdk8ssigsiov1alpha1.NodeFeatureRule, error)
+	DeletePresetRule(ctx context.Context, namespace, name string) error
+}
+
+type presets struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+func NewPresetAPI(client client.Client, scheme *runtime.Scheme) PresetAPI {
+	return &presets{
+		client: client,
+		scheme: scheme,
+	}
+}
+
+func (p *presets) SetPresetRuleAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, preset *Preset,
+	nfr *nfdk8ssigsiov1alpha1.NodeFeatureRule) error {
+	metav1.SetMetaDataLabel(&nfr.ObjectMeta, PresetLabel, preset.Name)
+	metav1.SetMetaDataAnnotation(&nfr.ObjectMeta, PresetVersionAnnotation, strconv.Itoa(preset.Version))
+	nfr.Spec = *preset.Spec.DeepCopy()
+
+	return controllerutil.SetControllerReference(nfdInstance, nfr, p.scheme)
+}
+
+// GetPresetRules returns the NodeFeatureRules created from a preset for the
+// instance
+func (p *presets) GetPresetRules(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) ([]nfdk8ssigsiov1alpha1.NodeFeatureRule, error) {
+	nfrList := nfdk8ssigsiov1alpha1.NodeFeatureRuleList{}
+	err := p.client.List(ctx, &nfrList, client.InNamespace(nfdInstance.Namespace), client.HasLabels{PresetLabel})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list preset NodeFeatureRules in %s: %w", nfdInstance.Namespace, err)
+	}
+	nfrs := []nfdk8ssigsiov1alpha1.NodeFeatureRule{}
+	for _, nfr := range nfrList.Items {
+		if metav1.IsControlledBy(&nfr, nfdInstance) {
+			nfrs = append(nfrs, nfr)
+		}
+	}
+	return nfrs, nil
+}
+
+func (p *presets) DeletePresetRule(ctx context.Context, namespace, name string) error {
+	nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	err := p.client.Delete(ctx, &nfr)
+	if err != nil && client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete preset NodeFeatureRule %s/%s: %w", namespace, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package presets
+
+import (
+	"context"
+	"fmt"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"github.com/openshift/cluster-nfd-operator/internal/rules"
+)
+
+var _ = Describe("catalog", func() {
+	It("the catalog holds the curated presets", func() {
+		Expect(Names()).To(Equal([]string{"amd-gpu", "avx512", "intel-sgx", "intel-tdx", "nvidia-gpu", "rdma", "sriov-nic"}))
+	})
+
+	It("every preset evaluates without error on a node without the features", func() {
+		features := &rules.Features{}
+		for _, name := range Names() {
+			preset, ok := Get(name)
+			Expect(ok).To(BeTrue())
+			result := rules.Evaluate(preset.Spec.Rules, features)
+			Expect(result.Failed()).To(BeFalse(), "preset %s: %v", name, result.Rules)
+			Expect(result.Labels).To(BeEmpty(), "preset %s", name)
+		}
+	})
+
+	DescribeTable("presets label the nodes having the features", func(name string, features *rules.Features, labels map[string]string) {
+		preset, ok := Get(name)
+		Expect(ok).To(BeTrue())
+
+		result := rules.Evaluate(preset.Spec.Rules, features)
+		Expect(result.Failed()).To(BeFalse(), "%v", result.Rules)
+		Expect(result.Labels).To(Equal(labels))
+	},
+		Entry("nvidia-gpu", "nvidia-gpu", &rules.Features{
+			Instances: map[string]rules.InstanceFeatureSet{"pci.device": {Elements: []rules.InstanceFeature{
+				{Attributes: map[string]string{"vendor": "8086", "class": "0300"}},
+				{Attributes: map[string]string{"vendor": "10de", "class": "0302"}},
+			}}},
+		}, map[string]string{"feature.node.kubernetes.io/nvidia-gpu.present": "true"}),
+		Entry("sriov-nic", "sriov-nic", &rules.Features{
+			Instances: map[string]rules.InstanceFeatureSet{"pci.device": {Elements: []rules.InstanceFeature{
+				{Attributes: map[string]string{"vendor": "8086", "class": "0200", "sriov_totalvfs": "64"}},
+			}}},
+		}, map[string]string{"feature.node.kubernetes.io/sriov-nic.capable": "true"}),
+		Entry("rdma", "rdma", &rules.Features{
+			Flags: map[string]rules.FlagFeatureSet{"kernel.loadedmodule": {Elements: map[string]struct{}{
+				"ib_uverbs": {}, "rdma_ucm": {},
+			}}},
+			Instances: map[string]rules.InstanceFeatureSet{"pci.device": {Elements: []rules.InstanceFeature{
+				{Attributes: map[string]string{"vendor": "15b3", "class": "0200"}},
+			}}},
+		}, map[string]string{"feature.node.kubernetes.io/rdma.capable": "true", "feature.node.kubernetes.io/rdma.available": "true"}),
+		Entry("avx512 tier 2", "avx512", &rules.Features{
+			Flags: map[string]rules.FlagFeatureSet{"cpu.cpuid": {Elements: map[string]struct{}{
+				"AVX512F": {}, "AVX512CD": {}, "AVX512BW": {}, "AVX512DQ": {}, "AVX512VL": {}, "AVX512VNNI": {},
+			}}},
+		}, map[string]string{"feature.node.kubernetes.io/cpu-avx512.tier": "2"}),
+	)
+
+	It("error flow, a preset without rules is rejected", func() {
+		fsys := fstest.MapFS{
+			"catalog/empty.yaml": {Data: []byte("name: empty\nversion: 1\n")},
+		}
+
+		_, err := loadCatalog(fsys)
+		Expect(err).To(MatchError(ContainSubstring("rule preset catalog/empty.yaml must have a name, a version of at least 1 and rules")))
+	})
+
+	It("error flow, a preset defined twice is rejected", func() {
+		preset := []byte("name: dup\nversion: 1\nspec:\n  rules:\n  - name: dup\n    labels:\n      dup: \"true\"\n")
+		fsys := fstest.MapFS{
+			"catalog/a.yaml": {Data: preset},
+			"catalog/b.yaml": {Data: preset},
+		}
+
+		_, err := loadCatalog(fsys)
+		Expect(err).To(MatchError(ContainSubstring("rule preset dup is defined twice")))
+	})
+})
+
+var _ = Describe("SetPresetRuleAsDesired", func() {
+	It("the rules, the preset label and version are set", func() {
+		presetAPI := NewPresetAPI(nil, scheme)
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace"},
+		}
+		preset, _ := Get("nvidia-gpu")
+		nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: RuleName(&nfdCR, "nvidia-gpu"), Namespace: "test-namespace"},
+		}
+
+		err := presetAPI.SetPresetRuleAsDesired(context.Background(), &nfdCR, preset, &nfr)
+		Expect(err).To(BeNil())
+		Expect(nfr.Name).To(Equal("nfd-preset-nvidia-gpu"))
+		Expect(nfr.Labels).To(HaveKeyWithValue(PresetLabel, "nvidia-gpu"))
+		Expect(nfr.Annotations).To(HaveKeyWithValue(PresetVersionAnnotation, "1"))
+		Expect(nfr.Spec).To(Equal(preset.Spec))
+		Expect(metav1.IsControlledBy(&nfr, &nfdCR)).To(BeTrue())
+	})
+})
+
+var _ = Describe("GetPresetRules", func() {
+	var (
+		ctrl      *gomock.Controller
+		clnt      *client.MockClient
+		presetAPI PresetAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		presetAPI = NewPresetAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("only the preset rules controlled by the instance are returned", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "test-namespace", UID: "nfd-uid"},
+		}
+		otherCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-cr", Namespace: "test-namespace", UID: "other-uid"},
+		}
+		owned := nfdk8ssigsiov1alpha1.NodeFeatureRule{ObjectMeta: metav1.ObjectMeta{Name: "nfd-preset-rdma", Namespace: "test-namespace"}}
+		Expect(controllerutil.SetControllerReference(&nfdCR, &owned, scheme)).To(Succeed())
+		other := nfdk8ssigsiov1alpha1.NodeFeatureRule{ObjectMeta: metav1.ObjectMeta{Name: "nfd-preset-rdma-other", Namespace: "test-namespace"}}
+		Expect(controllerutil.SetControllerReference(&otherCR, &other, scheme)).To(Succeed())
+
+		clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("test-namespace"), ctrlclient.HasLabels{PresetLabel}).DoAndReturn(
+			func(_ interface{}, list *nfdk8ssigsiov1alpha1.NodeFeatureRuleList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdk8ssigsiov1alpha1.NodeFeatureRule{owned, other}
+				return nil
+			},
+		)
+
+		nfrs, err := presetAPI.GetPresetRules(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(nfrs).To(Equal([]nfdk8ssigsiov1alpha1.NodeFeatureRule{owned}))
+	})
+
+	It("error flow, failed to list the rules", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := presetAPI.GetPresetRules(ctx, &nfdv1.NodeFeatureDiscovery{})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("DeletePresetRule", func() {
+	var (
+		ctrl      *gomock.Controller
+		clnt      *client.MockClient
+		presetAPI PresetAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		presetAPI = NewPresetAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+
+	It("a missing rule is not an error", func() {
+		clnt.EXPECT().Delete(ctx, gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "whatever"))
+
+		err := presetAPI.DeletePresetRule(ctx, "test-namespace", "nfd-preset-rdma")
+		Expect(err).To(BeNil())
+	})
+
+	It("error flow, failed to delete the rule", func() {
+		clnt.EXPECT().Delete(ctx, gomock.Any()).Return(fmt.Errorf("some error"))
+
+		err := presetAPI.DeletePresetRule(ctx, "test-namespace", "nfd-preset-rdma")
+		Expect(err).To(HaveOccurred())
+	})
+})
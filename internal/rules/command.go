@@ -0,0 +1,103 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+const commandUsage = `Usage: %s rules test --rules <file> --features <file>
+
+Evaluates NodeFeatureRule objects against the features of a node, without a
+cluster, and prints the resulting labels, annotations, taints and extended
+resources.
+
+  --rules     YAML file with one or more NodeFeatureRule objects
+  --features  NodeFeature object of the node, or the bare features dumped
+              from the node
+`
+
+// RunCommand runs the rules subcommand with its arguments, and returns the
+// exit code of the program. The exit code is 1 if any rule fails to evaluate
+func RunCommand(programName string, args []string, stdout, stderr io.Writer) int {
+	usage := func() {
+		fmt.Fprintf(stderr, commandUsage, programName)
+	}
+	if len(args) == 0 || args[0] != "test" {
+		usage()
+		return 2
+	}
+
+	flags := flag.NewFlagSet(programName+" rules test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = usage
+	rulesFile := flags.String("rules", "", "YAML file with NodeFeatureRule objects.")
+	featuresFile := flags.String("features", "", "NodeFeature object or features of the node.")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if *rulesFile == "" || *featuresFile == "" || len(flags.Args()) > 0 {
+		usage()
+		return 2
+	}
+
+	result, err := testRules(*rulesFile, *featuresFile)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	out, err := yaml.Marshal(result)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to encode the result: %v\n", err)
+		return 1
+	}
+	if _, err := stdout.Write(out); err != nil {
+		return 1
+	}
+	if result.Failed() {
+		return 1
+	}
+	return 0
+}
+
+// testRules loads the rules and the features, and evaluates them
+func testRules(rulesFile, featuresFile string) (*Result, error) {
+	data, err := os.ReadFile(rulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the rules: %w", err)
+	}
+	rules, err := LoadRules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = os.ReadFile(featuresFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the features: %w", err)
+	}
+	features, err := LoadFeatures(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Evaluate(rules, features), nil
+}
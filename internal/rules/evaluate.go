@@ -0,0 +1,185 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	nfdv1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+const (
+	// defaultLabelNs is the namespace given by nfd-master to the label and
+	// extended resource names without one
+	defaultLabelNs = "feature.node.kubernetes.io/"
+
+	// matchedRuleFeature is the attribute feature holding the labels and vars
+	// of the rules evaluated so far, for back-references in later rules
+	matchedRuleFeature = nfdv1alpha1.RuleBackrefDomain + "." + nfdv1alpha1.RuleBackrefFeature
+)
+
+// Result is the outcome of the evaluation of a set of rules on a node
+type Result struct {
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Taints            []corev1.Taint    `json:"taints,omitempty"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	Rules             []RuleResult      `json:"rules"`
+}
+
+// RuleResult tells whether one rule matched the node, and the labels,
+// annotations, taints and extended resources it produced
+type RuleResult struct {
+	Name              string            `json:"name"`
+	Matched           bool              `json:"matched"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+	Taints            []corev1.Taint    `json:"taints,omitempty"`
+	ExtendedResources map[string]string `json:"extendedResources,omitempty"`
+	Error             string            `json:"error,omitempty"`
+}
+
+// Failed returns true if any rule could not be evaluated
+func (r *Result) Failed() bool {
+	for _, rule := range r.Rules {
+		if rule.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeFeatureRule holds the fields of a NodeFeatureRule object, of either
+// API group, read by LoadRules
+type nodeFeatureRule struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Metadata   struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec nfdv1alpha1.NodeFeatureRuleSpec `json:"spec"`
+}
+
+// LoadRules reads the NodeFeatureRule objects, of either API group, from a
+// YAML stream with one or more documents. The rules are returned in the
+// order nfd-master processes them, i.e. sorted by object name
+func LoadRules(data []byte) ([]nfdv1alpha1.Rule, error) {
+	objects := []nodeFeatureRule{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		obj := nodeFeatureRule{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode the rules: %w", err)
+		}
+		if obj.Kind == "" && obj.APIVersion == "" {
+			// empty document
+			continue
+		}
+
+		group, _, _ := strings.Cut(obj.APIVersion, "/")
+		if obj.Kind != "NodeFeatureRule" ||
+			(group != nfdv1alpha1.GroupVersion.Group && group != nfdk8ssigsiov1alpha1.GroupVersion.Group) {
+			return nil, fmt.Errorf("unsupported object %s %s/%s, only NodeFeatureRule objects are supported",
+				obj.APIVersion, obj.Kind, obj.Metadata.Name)
+		}
+		objects = append(objects, obj)
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].Metadata.Name < objects[j].Metadata.Name
+	})
+
+	rules := []nfdv1alpha1.Rule{}
+	for _, obj := range objects {
+		rules = append(rules, obj.Spec.Rules...)
+	}
+	return rules, nil
+}
+
+// Evaluate runs the rules on the features the way nfd-master does. The rules
+// are evaluated in order, each one seeing the labels and vars of the previous
+// matching rules in the rule.matched feature. A rule that fails to evaluate
+// is reported in the result and does not stop the evaluation
+func Evaluate(rules []nfdv1alpha1.Rule, features *Features) *Result {
+	features = features.DeepCopy()
+	result := &Result{
+		Labels:            map[string]string{},
+		Annotations:       map[string]string{},
+		ExtendedResources: map[string]string{},
+		Rules:             make([]RuleResult, 0, len(rules)),
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		out, err := executeRule(rule, features)
+		if err != nil {
+			result.Rules = append(result.Rules, RuleResult{Name: rule.Name, Error: err.Error()})
+			continue
+		}
+		if out == nil {
+			result.Rules = append(result.Rules, RuleResult{Name: rule.Name})
+			continue
+		}
+
+		labels := make(map[string]string, len(out.Labels))
+		for key, value := range out.Labels {
+			labels[addDefaultNs(key)] = value
+			result.Labels[addDefaultNs(key)] = value
+		}
+		ruleResult := RuleResult{Name: rule.Name, Matched: true, Labels: labels, Taints: out.Taints}
+		for key, value := range out.Annotations {
+			if ruleResult.Annotations == nil {
+				ruleResult.Annotations = map[string]string{}
+			}
+			ruleResult.Annotations[key] = value
+			result.Annotations[key] = value
+		}
+		for key, value := range out.ExtendedResources {
+			if ruleResult.ExtendedResources == nil {
+				ruleResult.ExtendedResources = map[string]string{}
+			}
+			ruleResult.ExtendedResources[addDefaultNs(key)] = value
+			result.ExtendedResources[addDefaultNs(key)] = value
+		}
+		result.Rules = append(result.Rules, ruleResult)
+		result.Taints = append(result.Taints, out.Taints...)
+
+		features.insertAttributes(matchedRuleFeature, out.Labels)
+		features.insertAttributes(matchedRuleFeature, out.Vars)
+	}
+	return result
+}
+
+// addDefaultNs prefixes the name with the default namespace if it has none
+func addDefaultNs(name string) string {
+	if strings.Contains(name, "/") {
+		return name
+	}
+	return defaultLabelNs + name
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	nfdv1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+// matchValue evaluates the expression against one input value. valid tells
+// whether the input exists at all. A nil expression is evaluated like an
+// Exists expression, the way nfd-master decodes a null expression
+func matchValue(expr *nfdv1alpha1.MatchExpression, valid bool, value string) (bool, error) {
+	if expr == nil {
+		return valid, nil
+	}
+
+	switch expr.Op {
+	case nfdv1alpha1.MatchAny:
+		return true, nil
+	case nfdv1alpha1.MatchExists:
+		return valid, nil
+	case nfdv1alpha1.MatchDoesNotExist:
+		return !valid, nil
+	}
+	if !valid {
+		return false, nil
+	}
+
+	switch expr.Op {
+	case nfdv1alpha1.MatchIn:
+		for _, v := range expr.Value {
+			if value == v {
+				return true, nil
+			}
+		}
+		return false, nil
+	case nfdv1alpha1.MatchNotIn:
+		for _, v := range expr.Value {
+			if value == v {
+				return false, nil
+			}
+		}
+		return true, nil
+	case nfdv1alpha1.MatchInRegexp:
+		for _, v := range expr.Value {
+			re, err := regexp.Compile(v)
+			if err != nil {
+				return false, fmt.Errorf("invalid regexp %q in %s expression: %w", v, expr.Op, err)
+			}
+			if re.MatchString(value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case nfdv1alpha1.MatchGt, nfdv1alpha1.MatchGe, nfdv1alpha1.MatchLt, nfdv1alpha1.MatchLe:
+		if len(expr.Value) != 1 {
+			return false, fmt.Errorf("invalid %s expression: exactly one value is required, got %d", expr.Op, len(expr.Value))
+		}
+		input, err := strconv.Atoi(value)
+		if err != nil {
+			return false, fmt.Errorf("%s expression: input value %q is not an integer", expr.Op, value)
+		}
+		bound, err := strconv.Atoi(expr.Value[0])
+		if err != nil {
+			return false, fmt.Errorf("invalid %s expression: value %q is not an integer", expr.Op, expr.Value[0])
+		}
+		switch expr.Op {
+		case nfdv1alpha1.MatchGt:
+			return input > bound, nil
+		case nfdv1alpha1.MatchGe:
+			return input >= bound, nil
+		case nfdv1alpha1.MatchLt:
+			return input < bound, nil
+		}
+		return input <= bound, nil
+	case nfdv1alpha1.MatchGtLt, nfdv1alpha1.MatchGeLe:
+		if len(expr.Value) != 2 {
+			return false, fmt.Errorf("invalid %s expression: exactly two values are required, got %d", expr.Op, len(expr.Value))
+		}
+		input, err := strconv.Atoi(value)
+		if err != nil {
+			return false, fmt.Errorf("%s expression: input value %q is not an integer", expr.Op, value)
+		}
+		lower, err := strconv.Atoi(expr.Value[0])
+		if err != nil {
+			return false, fmt.Errorf("invalid %s expression: value %q is not an integer", expr.Op, expr.Value[0])
+		}
+		upper, err := strconv.Atoi(expr.Value[1])
+		if err != nil {
+			return false, fmt.Errorf("invalid %s expression: value %q is not an integer", expr.Op, expr.Value[1])
+		}
+		if expr.Op == nfdv1alpha1.MatchGeLe {
+			return input >= lower && input <= upper, nil
+		}
+		return input > lower && input < upper, nil
+	case nfdv1alpha1.MatchIsTrue:
+		return value == "true", nil
+	case nfdv1alpha1.MatchIsFalse:
+		return value == "false", nil
+	}
+	return false, fmt.Errorf("unsupported match operator %q", expr.Op)
+}
+
+// matchedElement is one element of a feature set matched by a rule, exposed
+// to the labels and vars templates. Flags have a Name, attributes a Name and
+// a Value, and instances have their attributes
+type matchedElement map[string]string
+
+// matchFlags evaluates the expressions against the names of a flag feature
+// set, see flagValues. Only Exists and DoesNotExist are meaningful for flags
+func matchFlags(exprs *nfdv1alpha1.MatchExpressionSet, elements map[string]string) (bool, []matchedElement, error) {
+	if exprs != nil {
+		for _, key := range sortedKeys(*exprs) {
+			expr := (*exprs)[key]
+			if key == nfdv1alpha1.MatchAllNames || expr == nil {
+				continue
+			}
+			switch expr.Op {
+			case nfdv1alpha1.MatchAny, nfdv1alpha1.MatchExists, nfdv1alpha1.MatchDoesNotExist:
+			default:
+				return false, nil, fmt.Errorf("invalid operator %q for flag %q, only %s and %s are supported",
+					expr.Op, key, nfdv1alpha1.MatchExists, nfdv1alpha1.MatchDoesNotExist)
+			}
+		}
+	}
+	return matchAttributes(exprs, elements)
+}
+
+// matchAttributes evaluates the expressions against the values of an
+// attribute feature set. All the expressions must match. The elements named
+// by the expressions are returned, or all the elements without expressions
+func matchAttributes(exprs *nfdv1alpha1.MatchExpressionSet, elements map[string]string) (bool, []matchedElement, error) {
+	if exprs == nil {
+		matched := make([]matchedElement, 0, len(elements))
+		for _, name := range sortedKeys(elements) {
+			matched = append(matched, matchedElement{"Name": name, "Value": elements[name]})
+		}
+		return true, matched, nil
+	}
+
+	matched := []matchedElement{}
+	for _, key := range sortedKeys(*exprs) {
+		expr := (*exprs)[key]
+		if key == nfdv1alpha1.MatchAllNames {
+			// the expression is evaluated against the names of the elements
+			isMatch, names, err := matchNames(expr, elements)
+			if err != nil || !isMatch {
+				return false, nil, err
+			}
+			matched = append(matched, names...)
+			continue
+		}
+
+		value, ok := elements[key]
+		isMatch, err := matchValue(expr, ok, value)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to match %q: %w", key, err)
+		}
+		if !isMatch {
+			return false, nil, nil
+		}
+		if ok {
+			matched = append(matched, matchedElement{"Name": key, "Value": value})
+		}
+	}
+	return true, matched, nil
+}
+
+// matchNames evaluates the expression against the names of the elements,
+// it matches if any of the names matches
+func matchNames(expr *nfdv1alpha1.MatchExpression, elements map[string]string) (bool, []matchedElement, error) {
+	matched := []matchedElement{}
+	for _, name := range sortedKeys(elements) {
+		isMatch, err := matchValue(expr, true, name)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to match name %q: %w", name, err)
+		}
+		if isMatch {
+			matched = append(matched, matchedElement{"Name": name, "Value": elements[name]})
+		}
+	}
+	return len(matched) > 0, matched, nil
+}
+
+// matchInstances returns the instances matching all the expressions, and
+// the name expression if any. It matches if any instance matches
+func matchInstances(exprs *nfdv1alpha1.MatchExpressionSet, nameExpr *nfdv1alpha1.MatchExpression,
+	instances []InstanceFeature) (bool, []matchedElement, error) {
+	matched := []matchedElement{}
+	for _, instance := range instances {
+		if nameExpr != nil {
+			isMatch, _, err := matchNames(nameExpr, instance.Attributes)
+			if err != nil {
+				return false, nil, err
+			}
+			if !isMatch {
+				continue
+			}
+		}
+		isMatch, _, err := matchAttributes(exprs, instance.Attributes)
+		if err != nil {
+			return false, nil, err
+		}
+		if isMatch {
+			elem := make(matchedElement, len(instance.Attributes))
+			for key, value := range instance.Attributes {
+				elem[key] = value
+			}
+			matched = append(matched, elem)
+		}
+	}
+	return len(matched) > 0, matched, nil
+}
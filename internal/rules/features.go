@@ -0,0 +1,176 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Features is the feature snapshot of a node, in the format published by
+// nfd-worker in the spec.features field of its NodeFeature object. The keys
+// of the feature sets are of the form <domain>.<feature>, e.g. cpu.cpuid
+type Features struct {
+	Flags      map[string]FlagFeatureSet      `json:"flags,omitempty"`
+	Attributes map[string]AttributeFeatureSet `json:"attributes,omitempty"`
+	Instances  map[string]InstanceFeatureSet  `json:"instances,omitempty"`
+}
+
+// FlagFeatureSet is a set of features having no value, only a name
+type FlagFeatureSet struct {
+	Elements map[string]struct{} `json:"elements"`
+}
+
+// AttributeFeatureSet is a set of features having a name and a value
+type AttributeFeatureSet struct {
+	Elements map[string]string `json:"elements"`
+}
+
+// InstanceFeatureSet is a list of features, each of which has a set of
+// attributes, e.g. the PCI devices of the node
+type InstanceFeatureSet struct {
+	Elements []InstanceFeature `json:"elements"`
+}
+
+// InstanceFeature is one instance of an InstanceFeatureSet
+type InstanceFeature struct {
+	Attributes map[string]string `json:"attributes"`
+}
+
+// nodeFeature holds the fields of a NodeFeature object read by LoadFeatures
+type nodeFeature struct {
+	Spec *struct {
+		Features Features `json:"features"`
+	} `json:"spec"`
+}
+
+// LoadFeatures reads a feature snapshot, either a whole NodeFeature object
+// or the bare features dumped from a node
+func LoadFeatures(data []byte) (*Features, error) {
+	nf := nodeFeature{}
+	if err := yaml.Unmarshal(data, &nf); err != nil {
+		return nil, fmt.Errorf("failed to decode the features: %w", err)
+	}
+	if nf.Spec != nil {
+		return &nf.Spec.Features, nil
+	}
+
+	features := Features{}
+	if err := yaml.Unmarshal(data, &features); err != nil {
+		return nil, fmt.Errorf("failed to decode the features: %w", err)
+	}
+	return &features, nil
+}
+
+// DeepCopy returns a copy of the features that can be extended, e.g. with
+// the back-references to the output of the rules, without altering f
+func (f *Features) DeepCopy() *Features {
+	out := &Features{
+		Flags:      make(map[string]FlagFeatureSet, len(f.Flags)),
+		Attributes: make(map[string]AttributeFeatureSet, len(f.Attributes)),
+		Instances:  make(map[string]InstanceFeatureSet, len(f.Instances)),
+	}
+	for name, set := range f.Flags {
+		elements := make(map[string]struct{}, len(set.Elements))
+		for key := range set.Elements {
+			elements[key] = struct{}{}
+		}
+		out.Flags[name] = FlagFeatureSet{Elements: elements}
+	}
+	for name, set := range f.Attributes {
+		elements := make(map[string]string, len(set.Elements))
+		for key, value := range set.Elements {
+			elements[key] = value
+		}
+		out.Attributes[name] = AttributeFeatureSet{Elements: elements}
+	}
+	for name, set := range f.Instances {
+		elements := make([]InstanceFeature, 0, len(set.Elements))
+		for _, instance := range set.Elements {
+			attributes := make(map[string]string, len(instance.Attributes))
+			for key, value := range instance.Attributes {
+				attributes[key] = value
+			}
+			elements = append(elements, InstanceFeature{Attributes: attributes})
+		}
+		out.Instances[name] = InstanceFeatureSet{Elements: elements}
+	}
+	return out
+}
+
+// Merge adds the features of other to f, e.g. to combine the NodeFeature
+// objects of one node. The elements of other replace those of f with the
+// same name, and the instances of other are appended
+func (f *Features) Merge(other *Features) {
+	if f.Flags == nil {
+		f.Flags = map[string]FlagFeatureSet{}
+	}
+	if f.Attributes == nil {
+		f.Attributes = map[string]AttributeFeatureSet{}
+	}
+	if f.Instances == nil {
+		f.Instances = map[string]InstanceFeatureSet{}
+	}
+	other = other.DeepCopy()
+	for name, set := range other.Flags {
+		current, ok := f.Flags[name]
+		if !ok || current.Elements == nil {
+			f.Flags[name] = set
+			continue
+		}
+		for key := range set.Elements {
+			current.Elements[key] = struct{}{}
+		}
+	}
+	for name, set := range other.Attributes {
+		f.insertAttributes(name, set.Elements)
+	}
+	for name, set := range other.Instances {
+		current := f.Instances[name]
+		current.Elements = append(current.Elements, set.Elements...)
+		f.Instances[name] = current
+	}
+}
+
+// insertAttributes adds the values to the attribute feature set, creating
+// the feature set if needed
+func (f *Features) insertAttributes(name string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	set, ok := f.Attributes[name]
+	if !ok || set.Elements == nil {
+		set = AttributeFeatureSet{Elements: map[string]string{}}
+	}
+	for key, value := range values {
+		set.Elements[key] = value
+	}
+	f.Attributes[name] = set
+}
+
+// sortedKeys returns the keys of the map in a stable order, so that the
+// evaluation does not depend on the map iteration order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+
+	nfdv1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+// templateData holds the elements matched by a rule, by domain and feature,
+// e.g. {{range .cpu.cpuid}} in a labels template
+type templateData map[string]map[string][]matchedElement
+
+// add records the elements matched by the term on the given feature
+func (t templateData) add(feature string, elements []matchedElement) {
+	domain, name, _ := strings.Cut(feature, ".")
+	if _, ok := t[domain]; !ok {
+		t[domain] = map[string][]matchedElement{}
+	}
+	t[domain][name] = append(t[domain][name], elements...)
+}
+
+// ruleOutput is the result of one rule matching the features, before the
+// label and extended resource names get their default namespace
+type ruleOutput struct {
+	Labels            map[string]string
+	Annotations       map[string]string
+	Vars              map[string]string
+	Taints            []corev1.Taint
+	ExtendedResources map[string]string
+}
+
+// matchFeatures evaluates the terms against the features. All the terms
+// must match. A feature missing from the features is an empty feature set
+func matchFeatures(matcher nfdv1alpha1.FeatureMatcher, features *Features) (bool, templateData, error) {
+	data := templateData{}
+	for _, term := range matcher {
+		var (
+			isMatch  bool
+			elements []matchedElement
+			err      error
+		)
+		if set, ok := features.Flags[term.Feature]; ok {
+			isMatch, elements, err = matchTerm(term, flagValues(set.Elements), matchFlags)
+			for _, elem := range elements {
+				delete(elem, "Value")
+			}
+		} else if set, ok := features.Instances[term.Feature]; ok {
+			isMatch, elements, err = matchInstances(term.MatchExpressions, term.MatchName, set.Elements)
+		} else {
+			isMatch, elements, err = matchTerm(term, features.Attributes[term.Feature].Elements, matchAttributes)
+		}
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to match feature %q: %w", term.Feature, err)
+		}
+		if !isMatch {
+			return false, nil, nil
+		}
+		data.add(term.Feature, elements)
+	}
+	return true, data, nil
+}
+
+// matchTerm evaluates the name expression and the expressions of the term
+// against a flag or attribute feature set, both must match when specified
+func matchTerm(term nfdv1alpha1.FeatureMatcherTerm, elements map[string]string,
+	match func(*nfdv1alpha1.MatchExpressionSet, map[string]string) (bool, []matchedElement, error)) (bool, []matchedElement, error) {
+	if term.MatchName == nil {
+		return match(term.MatchExpressions, elements)
+	}
+
+	isMatch, matched, err := matchNames(term.MatchName, elements)
+	if err != nil || !isMatch {
+		return false, nil, err
+	}
+	if term.MatchExpressions == nil {
+		return true, matched, nil
+	}
+	return match(term.MatchExpressions, elements)
+}
+
+// flagValues turns a flag feature set into an attribute-like map, so that
+// flags and attributes share the same matching code
+func flagValues(elements map[string]struct{}) map[string]string {
+	values := make(map[string]string, len(elements))
+	for name := range elements {
+		values[name] = ""
+	}
+	return values
+}
+
+// executeRule evaluates the rule against the features. A rule without any
+// matcher always matches. The output is nil if the rule does not match
+func executeRule(rule *nfdv1alpha1.Rule, features *Features) (*ruleOutput, error) {
+	labels := map[string]string{}
+	vars := map[string]string{}
+
+	if len(rule.MatchAny) > 0 {
+		// the first matching element is enough, unless templates need the
+		// output of all the matching elements
+		matched := false
+		for _, elem := range rule.MatchAny {
+			isMatch, data, err := matchFeatures(elem.MatchFeatures, features)
+			if err != nil {
+				return nil, err
+			}
+			if !isMatch {
+				continue
+			}
+			matched = true
+			if err := executeTemplates(rule, data, labels, vars); err != nil {
+				return nil, err
+			}
+			if rule.LabelsTemplate == "" && rule.VarsTemplate == "" {
+				break
+			}
+		}
+		if !matched {
+			return nil, nil
+		}
+	}
+
+	if len(rule.MatchFeatures) > 0 {
+		isMatch, data, err := matchFeatures(rule.MatchFeatures, features)
+		if err != nil {
+			return nil, err
+		}
+		if !isMatch {
+			return nil, nil
+		}
+		if err := executeTemplates(rule, data, labels, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	// static values have priority over the output of the templates
+	for key, value := range rule.Labels {
+		labels[key] = value
+	}
+	for key, value := range rule.Vars {
+		vars[key] = value
+	}
+
+	for key, value := range labels {
+		resolved, err := resolveDynamicValue(value, features)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of label %q: %w", key, err)
+		}
+		labels[key] = resolved
+	}
+	extendedResources := make(map[string]string, len(rule.ExtendedResources))
+	for key, value := range rule.ExtendedResources {
+		resolved, err := resolveDynamicValue(value, features)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of extended resource %q: %w", key, err)
+		}
+		extendedResources[key] = resolved
+	}
+
+	annotations := make(map[string]string, len(rule.Annotations))
+	for key, value := range rule.Annotations {
+		annotations[key] = value
+	}
+
+	return &ruleOutput{
+		Labels:            labels,
+		Annotations:       annotations,
+		Vars:              vars,
+		Taints:            append([]corev1.Taint(nil), rule.Taints...),
+		ExtendedResources: extendedResources,
+	}, nil
+}
+
+// executeTemplates runs the labels and vars templates of the rule on the
+// matched elements and adds their output to labels and vars
+func executeTemplates(rule *nfdv1alpha1.Rule, data templateData, labels, vars map[string]string) error {
+	if err := executeTemplate(rule.LabelsTemplate, data, labels); err != nil {
+		return fmt.Errorf("failed to execute the labels template: %w", err)
+	}
+	if err := executeTemplate(rule.VarsTemplate, data, vars); err != nil {
+		return fmt.Errorf("failed to execute the vars template: %w", err)
+	}
+	return nil
+}
+
+// executeTemplate runs the template and parses its output as one key=value
+// pair per line. Like nfd-master, a line without a value is an error
+func executeTemplate(text string, data templateData, out map[string]string) error {
+	if text == "" {
+		return nil
+	}
+	tmpl, err := template.New("").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("missing value in expanded template line %q, (format must be '<key>=<value>')", line)
+		}
+		out[key] = value
+	}
+	return nil
+}
+
+// resolveDynamicValue replaces a value of the form @<domain>.<feature>.<element>
+// by the value of the attribute it references
+func resolveDynamicValue(value string, features *Features) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	parts := strings.SplitN(value[1:], ".", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid dynamic value %q, expected @<domain>.<feature>.<element>", value)
+	}
+	attributes, ok := features.Attributes[parts[0]+"."+parts[1]]
+	if !ok {
+		return "", fmt.Errorf("dynamic value %q: feature %s.%s not found", value, parts[0], parts[1])
+	}
+	resolved, ok := attributes.Elements[parts[2]]
+	if !ok {
+		return "", fmt.Errorf("dynamic value %q: element %q not found", value, parts[2])
+	}
+	return resolved, nil
+}
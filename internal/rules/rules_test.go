@@ -0,0 +1,342 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"bytes"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	nfdv1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+func matchExpressions(key string, op nfdv1alpha1.MatchOp, values ...string) *nfdv1alpha1.MatchExpressionSet {
+	return &nfdv1alpha1.MatchExpressionSet{
+		key: &nfdv1alpha1.MatchExpression{Op: op, Value: values},
+	}
+}
+
+func loadTestFeatures() *Features {
+	data, err := os.ReadFile("testdata/features.yaml")
+	Expect(err).NotTo(HaveOccurred())
+	features, err := LoadFeatures(data)
+	Expect(err).NotTo(HaveOccurred())
+	return features
+}
+
+var _ = Describe("matchValue", func() {
+	DescribeTable("evaluating an expression", func(op nfdv1alpha1.MatchOp, values []string, valid bool, input string,
+		expected bool, expectErr bool) {
+		res, err := matchValue(&nfdv1alpha1.MatchExpression{Op: op, Value: values}, valid, input)
+		if expectErr {
+			Expect(err).To(HaveOccurred())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(expected))
+	},
+		Entry("Any on a missing input", nfdv1alpha1.MatchAny, nil, false, "", true, false),
+		Entry("Exists", nfdv1alpha1.MatchExists, nil, true, "", true, false),
+		Entry("DoesNotExist", nfdv1alpha1.MatchDoesNotExist, nil, true, "", false, false),
+		Entry("In", nfdv1alpha1.MatchIn, []string{"a", "b"}, true, "b", true, false),
+		Entry("In on a missing input", nfdv1alpha1.MatchIn, []string{""}, false, "", false, false),
+		Entry("NotIn", nfdv1alpha1.MatchNotIn, []string{"a", "b"}, true, "b", false, false),
+		Entry("InRegexp", nfdv1alpha1.MatchInRegexp, []string{"^x", "^5\\."}, true, "5.14", true, false),
+		Entry("InRegexp with an invalid regexp", nfdv1alpha1.MatchInRegexp, []string{"("}, true, "5", false, true),
+		Entry("Gt", nfdv1alpha1.MatchGt, []string{"4"}, true, "5", true, false),
+		Entry("Lt", nfdv1alpha1.MatchLt, []string{"4"}, true, "5", false, false),
+		Entry("Gt on a non-integer input", nfdv1alpha1.MatchGt, []string{"4"}, true, "five", false, true),
+		Entry("GtLt", nfdv1alpha1.MatchGtLt, []string{"1", "10"}, true, "5", true, false),
+		Entry("GtLt excludes the bounds", nfdv1alpha1.MatchGtLt, []string{"1", "10"}, true, "10", false, false),
+		Entry("GtLt with one value", nfdv1alpha1.MatchGtLt, []string{"1"}, true, "5", false, true),
+		Entry("Ge includes the bound", nfdv1alpha1.MatchGe, []string{"5"}, true, "5", true, false),
+		Entry("Le includes the bound", nfdv1alpha1.MatchLe, []string{"4"}, true, "5", false, false),
+		Entry("GeLe includes the bounds", nfdv1alpha1.MatchGeLe, []string{"1", "10"}, true, "10", true, false),
+		Entry("GeLe on a non-integer input", nfdv1alpha1.MatchGeLe, []string{"1", "10"}, true, "ten", false, true),
+		Entry("IsTrue", nfdv1alpha1.MatchIsTrue, nil, true, "true", true, false),
+		Entry("IsFalse", nfdv1alpha1.MatchIsFalse, nil, true, "true", false, false),
+		Entry("unknown operator", nfdv1alpha1.MatchOp("Near"), nil, true, "5", false, true),
+	)
+})
+
+var _ = Describe("LoadFeatures", func() {
+	It("should read the features of a NodeFeature object", func() {
+		features := loadTestFeatures()
+
+		Expect(features.Flags["cpu.cpuid"].Elements).To(HaveKey("AVX512F"))
+		Expect(features.Attributes["kernel.version"].Elements).To(HaveKeyWithValue("major", "5"))
+		Expect(features.Instances["pci.device"].Elements).To(HaveLen(2))
+	})
+
+	It("should read bare features", func() {
+		data := []byte("attributes:\n  kernel.version:\n    elements:\n      major: \"6\"\n")
+
+		features, err := LoadFeatures(data)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(features.Attributes["kernel.version"].Elements).To(HaveKeyWithValue("major", "6"))
+	})
+})
+
+var _ = Describe("Merge", func() {
+	It("should add the features of another snapshot", func() {
+		features := loadTestFeatures()
+		other := &Features{
+			Flags: map[string]FlagFeatureSet{
+				"cpu.cpuid": {Elements: map[string]struct{}{"AMX": {}}},
+			},
+			Attributes: map[string]AttributeFeatureSet{
+				"kernel.version": {Elements: map[string]string{"major": "6"}},
+				"my.feature":     {Elements: map[string]string{"enabled": "true"}},
+			},
+			Instances: map[string]InstanceFeatureSet{
+				"pci.device": {Elements: []InstanceFeature{{Attributes: map[string]string{"vendor": "1af4"}}}},
+			},
+		}
+
+		features.Merge(other)
+
+		Expect(features.Flags["cpu.cpuid"].Elements).To(HaveKey("AMX"))
+		Expect(features.Flags["cpu.cpuid"].Elements).To(HaveKey("AVX"))
+		Expect(features.Attributes["kernel.version"].Elements).To(HaveKeyWithValue("major", "6"))
+		Expect(features.Attributes["kernel.version"].Elements).To(HaveKeyWithValue("minor", "14"))
+		Expect(features.Attributes["my.feature"].Elements).To(HaveKeyWithValue("enabled", "true"))
+		Expect(features.Instances["pci.device"].Elements).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("LoadRules", func() {
+	It("should return the rules of both API groups sorted by object name", func() {
+		data, err := os.ReadFile("testdata/rules.yaml")
+		Expect(err).NotTo(HaveOccurred())
+
+		rules, err := LoadRules(data)
+
+		Expect(err).NotTo(HaveOccurred())
+		names := []string{}
+		for _, rule := range rules {
+			names = append(names, rule.Name)
+		}
+		Expect(names).To(Equal([]string{"gpu", "nic", "avx512", "kernel", "no sriov", "accelerated node"}))
+	})
+
+	It("should fail on other kinds of objects", func() {
+		data := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n")
+
+		_, err := LoadRules(data)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Evaluate", func() {
+	var features *Features
+
+	BeforeEach(func() {
+		features = loadTestFeatures()
+	})
+
+	It("should match flags, attributes and instances", func() {
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name:   "all",
+				Labels: map[string]string{"all": "true"},
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("AVX2", nfdv1alpha1.MatchExists)},
+					{Feature: "kernel.version", MatchExpressions: matchExpressions("minor", nfdv1alpha1.MatchGtLt, "10", "20")},
+					{Feature: "pci.device", MatchExpressions: matchExpressions("class", nfdv1alpha1.MatchIn, "0200")},
+					{Feature: "usb.device", MatchExpressions: matchExpressions("vendor", nfdv1alpha1.MatchDoesNotExist)},
+				},
+			},
+			{
+				Name:   "mismatch",
+				Labels: map[string]string{"mismatch": "true"},
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("SSE4", nfdv1alpha1.MatchExists)},
+				},
+			},
+		}
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Labels).To(Equal(map[string]string{"feature.node.kubernetes.io/all": "true"}))
+		Expect(result.Rules).To(Equal([]RuleResult{
+			{Name: "all", Matched: true, Labels: map[string]string{"feature.node.kubernetes.io/all": "true"}},
+			{Name: "mismatch", Matched: false},
+		}))
+		Expect(result.Failed()).To(BeFalse())
+	})
+
+	It("should run the templates on all the matching elements of matchAny", func() {
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name:           "pci",
+				LabelsTemplate: "{{range .pci.device}}pci-{{.vendor}}.present=true\n{{end}}",
+				MatchAny: []nfdv1alpha1.MatchAnyElem{
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{
+						{Feature: "pci.device", MatchExpressions: matchExpressions("vendor", nfdv1alpha1.MatchIn, "8086")},
+					}},
+					{MatchFeatures: nfdv1alpha1.FeatureMatcher{
+						{Feature: "pci.device", MatchExpressions: matchExpressions("vendor", nfdv1alpha1.MatchIn, "10de")},
+					}},
+				},
+			},
+		}
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Labels).To(Equal(map[string]string{
+			"feature.node.kubernetes.io/pci-8086.present": "true",
+			"feature.node.kubernetes.io/pci-10de.present": "true",
+		}))
+	})
+
+	It("should match the element names with the * key", func() {
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name:           "avx",
+				LabelsTemplate: "{{range .cpu.cpuid}}{{.Name}}=yes\n{{end}}",
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "cpu.cpuid", MatchExpressions: matchExpressions(nfdv1alpha1.MatchAllNames, nfdv1alpha1.MatchInRegexp, "^AVX5")},
+				},
+			},
+		}
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Labels).To(Equal(map[string]string{"feature.node.kubernetes.io/AVX512F": "yes"}))
+	})
+
+	It("should reject the template lines without a value", func() {
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name:           "avx",
+				LabelsTemplate: "{{range .cpu.cpuid}}{{.Name}}\n{{end}}",
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "cpu.cpuid", MatchExpressions: matchExpressions(nfdv1alpha1.MatchAllNames, nfdv1alpha1.MatchInRegexp, "^AVX5")},
+				},
+			},
+		}
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Failed()).To(BeTrue())
+		Expect(result.Rules[0].Error).To(ContainSubstring(`missing value in expanded template line "AVX512F"`))
+		Expect(result.Labels).To(BeEmpty())
+	})
+
+	It("should report the rules that fail and carry on", func() {
+		rules := []nfdv1alpha1.Rule{
+			{
+				Name: "invalid flag operator",
+				MatchFeatures: nfdv1alpha1.FeatureMatcher{
+					{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("AVX", nfdv1alpha1.MatchIn, "x")},
+				},
+			},
+			{
+				Name:   "missing dynamic value",
+				Labels: map[string]string{"missing": "@kernel.version.patch"},
+			},
+			{
+				Name:   "static",
+				Labels: map[string]string{"example.com/static": "true"},
+			},
+		}
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Failed()).To(BeTrue())
+		Expect(result.Rules[0].Error).To(ContainSubstring("invalid operator"))
+		Expect(result.Rules[1].Error).To(ContainSubstring("not found"))
+		Expect(result.Rules[2]).To(Equal(RuleResult{
+			Name:    "static",
+			Matched: true,
+			Labels:  map[string]string{"example.com/static": "true"},
+		}))
+		Expect(result.Labels).To(Equal(map[string]string{"example.com/static": "true"}))
+	})
+
+	It("should not modify the features", func() {
+		rules := []nfdv1alpha1.Rule{{Name: "static", Labels: map[string]string{"static": "true"}}}
+
+		Evaluate(rules, features)
+
+		Expect(features.Attributes).NotTo(HaveKey(matchedRuleFeature))
+	})
+
+	It("should evaluate the test rules", func() {
+		data, err := os.ReadFile("testdata/rules.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		rules, err := LoadRules(data)
+		Expect(err).NotTo(HaveOccurred())
+
+		result := Evaluate(rules, features)
+
+		Expect(result.Failed()).To(BeFalse())
+		Expect(result.Labels).To(Equal(map[string]string{
+			"feature.node.kubernetes.io/nvidia-gpu":   "true",
+			"feature.node.kubernetes.io/cpu-AVX512F":  "true",
+			"feature.node.kubernetes.io/kernel-major": "5",
+			"feature.node.kubernetes.io/accelerated":  "true",
+		}))
+		Expect(result.Annotations).To(Equal(map[string]string{"example.com/nic": "ice"}))
+		Expect(result.ExtendedResources).To(Equal(map[string]string{"vendor.io/gpus": "1"}))
+		Expect(result.Taints).To(Equal([]corev1.Taint{
+			{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule},
+		}))
+		Expect(result.Rules[4]).To(Equal(RuleResult{Name: "no sriov", Matched: false}))
+		Expect(result.Rules).To(ContainElement(HaveField("Annotations", map[string]string{"example.com/nic": "ice"})))
+		Expect(result.Rules).To(ContainElement(HaveField("ExtendedResources", map[string]string{"vendor.io/gpus": "1"})))
+	})
+})
+
+var _ = Describe("RunCommand", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+	})
+
+	It("should print the result of the evaluation", func() {
+		code := RunCommand("nfd-operator",
+			[]string{"test", "--rules", "testdata/rules.yaml", "--features", "testdata/features.yaml"}, stdout, stderr)
+
+		Expect(code).To(Equal(0))
+		Expect(stdout.String()).To(ContainSubstring("feature.node.kubernetes.io/accelerated: \"true\""))
+		Expect(stdout.String()).To(ContainSubstring("vendor.io/gpus: \"1\""))
+		Expect(stderr.String()).To(BeEmpty())
+	})
+
+	It("should print the usage without the required flags", func() {
+		code := RunCommand("nfd-operator", []string{"test", "--rules", "testdata/rules.yaml"}, stdout, stderr)
+
+		Expect(code).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("Usage: nfd-operator rules test"))
+	})
+
+	It("should fail when a file cannot be read", func() {
+		code := RunCommand("nfd-operator",
+			[]string{"test", "--rules", "testdata/missing.yaml", "--features", "testdata/features.yaml"}, stdout, stderr)
+
+		Expect(code).To(Equal(1))
+		Expect(stderr.String()).To(ContainSubstring("failed to read the rules"))
+	})
+})
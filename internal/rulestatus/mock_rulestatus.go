@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: rulestatus.go
+//
+// Generated by this command:
+//
+//	mockgen -source=rulestatus.go -package=rulestatus -destination=mock_rulestatus.go RuleStatusAPI
+//
+
+// Package rulestatus is a generated GoMock package.
+package rulestatus
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRuleStatusAPI is a mock of RuleStatusAPI interface.
+type MockRuleStatusAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleStatusAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockRuleStatusAPIMockRecorder is the mock recorder for MockRuleStatusAPI.
+type MockRuleStatusAPIMockRecorder struct {
+	mock *MockRuleStatusAPI
+}
+
+// NewMockRuleStatusAPI creates a new mock instance.
+func NewMockRuleStatusAPI(ctrl *gomock.Controller) *MockRuleStatusAPI {
+	mock := &MockRuleStatusAPI{ctrl: ctrl}
+	mock.recorder = &MockRuleStatusAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleStatusAPI) EXPECT() *MockRuleStatusAPIMockRecorder {
+	return m.recorder
+}
+
+// GetRuleStatus mocks base method.
+func (m *MockRuleStatusAPI) GetRuleStatus(ctx context.Context, nfr *v1alpha1.NodeFeatureRule) (*v1alpha1.NodeFeatureRuleStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuleStatus", ctx, nfr)
+	ret0, _ := ret[0].(*v1alpha1.NodeFeatureRuleStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuleStatus indicates an expected call of GetRuleStatus.
+func (mr *MockRuleStatusAPIMockRecorder) GetRuleStatus(ctx, nfr any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuleStatus", reflect.TypeOf((*MockRuleStatusAPI)(nil).GetRuleStatus), ctx, nfr)
+}
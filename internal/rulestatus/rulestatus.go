@@ -0,0 +1,541 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rulestatus
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/rules"
+)
+
+const (
+	// MaxReportedNodes is the maximum number of matching nodes listed in the
+	// status of each rule
+	MaxReportedNodes = 10
+
+	// maxReportedErrors is the maximum number of evaluation errors listed in
+	// the message of the Degraded condition
+	maxReportedErrors = 5
+
+	// ConditionDegraded indicates that some rules fail to evaluate on some nodes
+	ConditionDegraded = "Degraded"
+
+	reasonEvaluationFailed    = "EvaluationFailed"
+	reasonEvaluationSucceeded = "EvaluationSucceeded"
+
+	// ConditionConflicting indicates that some rules set an output that
+	// rules of other NodeFeatureRules of the namespace set to a different
+	// value on the same node
+	ConditionConflicting = "Conflicting"
+
+	reasonOutputConflict = "OutputConflict"
+	reasonNoConflict     = "NoConflict"
+
+	// NodeNameLabel is set by nfd-worker on its NodeFeature object to the
+	// name of the node the features are of
+	NodeNameLabel = "nfd.node.kubernetes.io/node-name"
+
+	// NodeFeatureKind is the kind of the objects holding the features of the
+	// nodes, which have no Go types in this repository
+	NodeFeatureKind = "NodeFeature"
+)
+
+//go:generate mockgen -source=rulestatus.go -package=rulestatus -destination=mock_rulestatus.go RuleStatusAPI
+
+type RuleStatusAPI interface {
+	GetRuleStatus(ctx context.Context, nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) (*nfdopenshiftiov1alpha1.NodeFeatureRuleStatus, error)
+}
+
+type ruleStatus struct {
+	client client.Client
+
+	// mu guards last, the evaluation shared by the reconciles of all the
+	// NodeFeatureRules until the nodes, their features or the rules change
+	mu   sync.Mutex
+	last *snapshot
+}
+
+func NewRuleStatusAPI(client client.Client) RuleStatusAPI {
+	return &ruleStatus{
+		client: client,
+	}
+}
+
+// snapshot holds the evaluation of the rules of all the NodeFeatureRules on
+// the features of every node, and the conflicts between them
+type snapshot struct {
+	fingerprint  string
+	nodeFeatures map[string]*rules.Features
+	evaluation   *evaluation
+	conflicts    []Conflict
+}
+
+// ruleObject holds the rules of one NodeFeatureRule object
+type ruleObject struct {
+	key   types.NamespacedName
+	rules []nfdopenshiftiov1alpha1.Rule
+}
+
+// ConflictingRule is a rule setting a conflicting output
+type ConflictingRule struct {
+	Object types.NamespacedName
+	Rule   string
+	Value  string
+}
+
+// Conflict is an output, a label, an annotation, an extended resource or a
+// taint, set to different values by the rules of two NodeFeatureRules. Only
+// the first node the conflict is found on is named
+type Conflict struct {
+	Output    string
+	Key       string
+	First     ConflictingRule
+	Second    ConflictingRule
+	Node      string
+	NodeCount int
+}
+
+func (c Conflict) String() string {
+	message := fmt.Sprintf("%s %s is set to %q by rule %q of NodeFeatureRule %s and to %q by rule %q of NodeFeatureRule %s on node %s",
+		c.Output, c.Key, c.First.Value, c.First.Rule, c.First.Object, c.Second.Value, c.Second.Rule, c.Second.Object, c.Node)
+	if c.NodeCount > 1 {
+		message += fmt.Sprintf(" and %d more nodes", c.NodeCount-1)
+	}
+	return message
+}
+
+// GetRuleStatus returns the results of the rules of the NodeFeatureRule on
+// the features of every node, evaluated together with the rules of the other
+// objects processed by nfd-master since rules may refer to the output of
+// other rules. The conditions of the returned status have no transition time
+// yet
+func (r *ruleStatus) GetRuleStatus(ctx context.Context,
+	nfr *nfdopenshiftiov1alpha1.NodeFeatureRule) (*nfdopenshiftiov1alpha1.NodeFeatureRuleStatus, error) {
+	snap, err := r.getSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := types.NamespacedName{Namespace: nfr.Namespace, Name: nfr.Name}
+	ev, conflicts := snap.evaluation, snap.conflicts
+	if i := ev.index(target); i < 0 || !equality.Semantic.DeepEqual(ev.objects[i].rules, nfr.Spec.Rules) {
+		// the mirror of the NodeFeatureRule is not up to date yet, only its
+		// rules and those of the objects processed before it, which they may
+		// refer to, are evaluated until the update of the mirror is seen
+		objects := []ruleObject{{key: target, rules: nfr.Spec.Rules}}
+		for _, obj := range ev.objects {
+			if objectLess(obj.key, target) {
+				objects = append(objects, obj)
+			}
+		}
+		ev = evaluateAll(objects, snap.nodeFeatures)
+		conflicts = ev.conflicts()
+	}
+
+	return ev.status(target, conflicts), nil
+}
+
+// getSnapshot returns the evaluation of all the NodeFeatureRules, which is
+// only done again once the nodes, their NodeFeature objects or the rules
+// changed. Only the metadata of the NodeFeature objects is read until then
+func (r *ruleStatus) getSnapshot(ctx context.Context) (*snapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nodeList := corev1.NodeList{}
+	if err := r.client.List(ctx, &nodeList, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	nfMetaList := metav1.PartialObjectMetadataList{}
+	nfMetaList.SetGroupVersionKind(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(NodeFeatureKind + "List"))
+	if err := r.client.List(ctx, &nfMetaList, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatures: %w", err)
+	}
+
+	nfrList := nfdk8ssigsiov1alpha1.NodeFeatureRuleList{}
+	if err := r.client.List(ctx, &nfrList, client.UnsafeDisableDeepCopy); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatureRules: %w", err)
+	}
+
+	fingerprint := getFingerprint(&nodeList, &nfMetaList, &nfrList)
+	if r.last != nil && r.last.fingerprint == fingerprint {
+		return r.last, nil
+	}
+
+	nodeFeatures, err := r.getNodeFeatures(ctx, &nodeList)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ruleObject, 0, len(nfrList.Items))
+	for _, item := range nfrList.Items {
+		key := types.NamespacedName{Namespace: item.Namespace, Name: item.Name}
+		objects = append(objects, ruleObject{key: key, rules: item.Spec.Rules})
+	}
+	ev := evaluateAll(objects, nodeFeatures)
+
+	r.last = &snapshot{
+		fingerprint:  fingerprint,
+		nodeFeatures: nodeFeatures,
+		evaluation:   ev,
+		conflicts:    ev.conflicts(),
+	}
+	return r.last, nil
+}
+
+// getFingerprint identifies the inputs of the evaluation: the nodes, the
+// NodeFeature objects and the node they are of, and the rules, whose changes
+// all bump the generation of their object
+func getFingerprint(nodeList *corev1.NodeList, nfList *metav1.PartialObjectMetadataList,
+	nfrList *nfdk8ssigsiov1alpha1.NodeFeatureRuleList) string {
+	entries := make([]string, 0, len(nodeList.Items)+len(nfList.Items)+len(nfrList.Items))
+	for _, node := range nodeList.Items {
+		entries = append(entries, fmt.Sprintf("node %s %s", node.Name, node.UID))
+	}
+	for _, item := range nfList.Items {
+		entries = append(entries, fmt.Sprintf("nodefeature %s/%s %s %d %s",
+			item.Namespace, item.Name, item.UID, item.Generation, item.Labels[NodeNameLabel]))
+	}
+	for _, item := range nfrList.Items {
+		entries = append(entries, fmt.Sprintf("nodefeaturerule %s/%s %s %d", item.Namespace, item.Name, item.UID, item.Generation))
+	}
+	sort.Strings(entries)
+
+	hash := sha256.Sum256([]byte(strings.Join(entries, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// getNodeFeatures returns the features of each node, merged from all the
+// NodeFeature objects of the node. Objects of nodes that do not exist are
+// ignored, like nfd-master does
+func (r *ruleStatus) getNodeFeatures(ctx context.Context, nodeList *corev1.NodeList) (map[string]*rules.Features, error) {
+	nodes := make(map[string]bool, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodes[node.Name] = true
+	}
+
+	nfList := unstructured.UnstructuredList{}
+	nfList.SetGroupVersionKind(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(NodeFeatureKind + "List"))
+	if err := r.client.List(ctx, &nfList); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatures: %w", err)
+	}
+	sort.Slice(nfList.Items, func(i, j int) bool {
+		return nfList.Items[i].GetName() < nfList.Items[j].GetName()
+	})
+
+	logger := ctrl.LoggerFrom(ctx)
+	nodeFeatures := map[string]*rules.Features{}
+	for i := range nfList.Items {
+		item := &nfList.Items[i]
+		nodeName := item.GetLabels()[NodeNameLabel]
+		if nodeName == "" {
+			nodeName = item.GetName()
+		}
+		if !nodes[nodeName] {
+			continue
+		}
+
+		data, err := json.Marshal(item.Object)
+		if err != nil {
+			logger.Error(err, "failed to encode NodeFeature", "name", item.GetName(), "namespace", item.GetNamespace())
+			continue
+		}
+		features, err := rules.LoadFeatures(data)
+		if err != nil {
+			logger.Error(err, "invalid NodeFeature", "name", item.GetName(), "namespace", item.GetNamespace())
+			continue
+		}
+
+		if _, ok := nodeFeatures[nodeName]; !ok {
+			nodeFeatures[nodeName] = &rules.Features{}
+		}
+		nodeFeatures[nodeName].Merge(features)
+	}
+	return nodeFeatures, nil
+}
+
+// evaluation holds the results of the rules of all the objects, sorted by
+// name like nfd-master does, on each node
+type evaluation struct {
+	objects []ruleObject
+	// first is the index, in the results, of the first rule of each object
+	first     []int
+	nodeNames []string
+	results   map[string]*rules.Result
+}
+
+// objectLess tells whether nfd-master processes the rules of the first object
+// before those of the second one
+func objectLess(a, b types.NamespacedName) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	return a.Namespace < b.Namespace
+}
+
+func evaluateAll(objects []ruleObject, nodeFeatures map[string]*rules.Features) *evaluation {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objectLess(objects[i].key, objects[j].key)
+	})
+
+	ev := &evaluation{
+		objects:   objects,
+		first:     make([]int, len(objects)),
+		nodeNames: make([]string, 0, len(nodeFeatures)),
+		results:   make(map[string]*rules.Result, len(nodeFeatures)),
+	}
+	allRules := []nfdopenshiftiov1alpha1.Rule{}
+	for i, obj := range objects {
+		ev.first[i] = len(allRules)
+		allRules = append(allRules, obj.rules...)
+	}
+
+	for name := range nodeFeatures {
+		ev.nodeNames = append(ev.nodeNames, name)
+	}
+	sort.Strings(ev.nodeNames)
+	for _, nodeName := range ev.nodeNames {
+		ev.results[nodeName] = rules.Evaluate(allRules, nodeFeatures[nodeName])
+	}
+	return ev
+}
+
+// index returns the position of the object in the evaluation, or -1
+func (ev *evaluation) index(key types.NamespacedName) int {
+	for i, obj := range ev.objects {
+		if obj.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// status reports the results of the rules of the target and the conflicts
+// with the rules of the other objects of its namespace
+func (ev *evaluation) status(target types.NamespacedName, allConflicts []Conflict) *nfdopenshiftiov1alpha1.NodeFeatureRuleStatus {
+	first := 0
+	var targetRules []nfdopenshiftiov1alpha1.Rule
+	if i := ev.index(target); i >= 0 {
+		first, targetRules = ev.first[i], ev.objects[i].rules
+	}
+
+	status := &nfdopenshiftiov1alpha1.NodeFeatureRuleStatus{}
+	if len(targetRules) > 0 {
+		status.Rules = make([]nfdopenshiftiov1alpha1.RuleStatus, len(targetRules))
+	}
+	for i := range status.Rules {
+		status.Rules[i].Name = targetRules[i].Name
+	}
+
+	errs := []string{}
+	for _, nodeName := range ev.nodeNames {
+		result := ev.results[nodeName]
+		matched := false
+		for i := range status.Rules {
+			ruleResult := &result.Rules[first+i]
+			if ruleResult.Error != "" {
+				errs = append(errs, fmt.Sprintf("rule %q on node %s: %s", ruleResult.Name, nodeName, ruleResult.Error))
+				continue
+			}
+			if ruleResult.Matched {
+				matched = true
+				addMatchedNode(&status.Rules[i], nodeName, ruleResult)
+			}
+		}
+		if matched {
+			status.MatchedNodeCount++
+		}
+	}
+
+	conflicts := []string{}
+	for _, conflict := range allConflicts {
+		if conflict.First.Object.Namespace != conflict.Second.Object.Namespace {
+			continue
+		}
+		if conflict.First.Object == target || conflict.Second.Object == target {
+			conflicts = append(conflicts, conflict.String())
+		}
+	}
+
+	status.Conditions = []metav1.Condition{getDegradedCondition(errs), getConflictingCondition(conflicts)}
+	return status
+}
+
+// conflictKey identifies a conflict regardless of the nodes it is found on
+type conflictKey struct {
+	output string
+	key    string
+	first  ConflictingRule
+	second ConflictingRule
+}
+
+// conflicts returns the outputs set to different values on the same node by
+// rules of different objects. Each conflict is reported once, on the first
+// node it is found on, with the later rule, which wins in nfd-master, second
+func (ev *evaluation) conflicts() []Conflict {
+	conflicts := []Conflict{}
+	index := map[conflictKey]int{}
+	for _, nodeName := range ev.nodeNames {
+		result := ev.results[nodeName]
+		writers := map[[2]string]ConflictingRule{}
+		set := func(output, key, value string, writer ConflictingRule) {
+			writer.Value = value
+			previous, ok := writers[[2]string{output, key}]
+			writers[[2]string{output, key}] = writer
+			if !ok || previous.Object == writer.Object || previous.Value == value {
+				return
+			}
+
+			ck := conflictKey{output: output, key: key, first: previous, second: writer}
+			if i, ok := index[ck]; ok {
+				conflicts[i].NodeCount++
+				return
+			}
+			index[ck] = len(conflicts)
+			conflicts = append(conflicts, Conflict{
+				Output:    output,
+				Key:       key,
+				First:     previous,
+				Second:    writer,
+				Node:      nodeName,
+				NodeCount: 1,
+			})
+		}
+
+		for i, obj := range ev.objects {
+			for j := range obj.rules {
+				ruleResult := &result.Rules[ev.first[i]+j]
+				if !ruleResult.Matched {
+					continue
+				}
+				writer := ConflictingRule{Object: obj.key, Rule: ruleResult.Name}
+				for _, key := range sortedKeys(ruleResult.Labels) {
+					set("label", key, ruleResult.Labels[key], writer)
+				}
+				for _, key := range sortedKeys(ruleResult.Annotations) {
+					set("annotation", key, ruleResult.Annotations[key], writer)
+				}
+				for _, key := range sortedKeys(ruleResult.ExtendedResources) {
+					set("extended resource", key, ruleResult.ExtendedResources[key], writer)
+				}
+				for _, taint := range ruleResult.Taints {
+					set("taint", taint.Key, fmt.Sprintf("%s:%s", taint.Value, taint.Effect), writer)
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addMatchedNode records the node and the output of the rule on it
+func addMatchedNode(ruleStatus *nfdopenshiftiov1alpha1.RuleStatus, nodeName string, result *rules.RuleResult) {
+	ruleStatus.MatchedNodeCount++
+	if len(ruleStatus.MatchedNodes) < MaxReportedNodes {
+		ruleStatus.MatchedNodes = append(ruleStatus.MatchedNodes, nodeName)
+	}
+
+	for key, value := range result.Labels {
+		if ruleStatus.Labels == nil {
+			ruleStatus.Labels = map[string]string{}
+		}
+		if _, ok := ruleStatus.Labels[key]; !ok {
+			ruleStatus.Labels[key] = value
+		}
+	}
+
+	for _, taint := range result.Taints {
+		found := false
+		for i := range ruleStatus.Taints {
+			if ruleStatus.Taints[i].MatchTaint(&taint) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ruleStatus.Taints = append(ruleStatus.Taints, taint)
+		}
+	}
+}
+
+func getDegradedCondition(errs []string) metav1.Condition {
+	if len(errs) == 0 {
+		return metav1.Condition{
+			Type:   ConditionDegraded,
+			Status: metav1.ConditionFalse,
+			Reason: reasonEvaluationSucceeded,
+		}
+	}
+
+	message := strings.Join(errs[:min(len(errs), maxReportedErrors)], "; ")
+	if len(errs) > maxReportedErrors {
+		message += fmt.Sprintf("; and %d more errors", len(errs)-maxReportedErrors)
+	}
+	return metav1.Condition{
+		Type:    ConditionDegraded,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonEvaluationFailed,
+		Message: message,
+	}
+}
+
+func getConflictingCondition(conflicts []string) metav1.Condition {
+	if len(conflicts) == 0 {
+		return metav1.Condition{
+			Type:   ConditionConflicting,
+			Status: metav1.ConditionFalse,
+			Reason: reasonNoConflict,
+		}
+	}
+
+	message := strings.Join(conflicts[:min(len(conflicts), maxReportedErrors)], "; ")
+	if len(conflicts) > maxReportedErrors {
+		message += fmt.Sprintf("; and %d more conflicts", len(conflicts)-maxReportedErrors)
+	}
+	return metav1.Condition{
+		Type:    ConditionConflicting,
+		Status:  metav1.ConditionTrue,
+		Reason:  reasonOutputConflict,
+		Message: message,
+	}
+}
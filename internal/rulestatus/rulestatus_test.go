@@ -0,0 +1,539 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rulestatus
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"github.com/openshift/cluster-nfd-operator/internal/rules"
+	"github.com/openshift/cluster-nfd-operator/internal/test"
+)
+
+func nodeFeature(name, nodeName string, pciVendors ...string) unstructured.Unstructured {
+	instances := []interface{}{}
+	for _, vendor := range pciVendors {
+		instances = append(instances, map[string]interface{}{
+			"attributes": map[string]interface{}{"vendor": vendor},
+		})
+	}
+	obj := unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"features": map[string]interface{}{
+				"instances": map[string]interface{}{
+					"pci.device": map[string]interface{}{"elements": instances},
+				},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(NodeFeatureKind))
+	obj.SetName(name)
+	obj.SetNamespace("openshift-nfd")
+	if nodeName != "" {
+		obj.SetLabels(map[string]string{NodeNameLabel: nodeName})
+	}
+	return obj
+}
+
+func pciRule(name, vendor string) nfdopenshiftiov1alpha1.Rule {
+	return nfdopenshiftiov1alpha1.Rule{
+		Name:   name,
+		Labels: map[string]string{name: "true"},
+		MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+			{
+				Feature: "pci.device",
+				MatchExpressions: &nfdopenshiftiov1alpha1.MatchExpressionSet{
+					"vendor": &nfdopenshiftiov1alpha1.MatchExpression{Op: nfdopenshiftiov1alpha1.MatchIn, Value: []string{vendor}},
+				},
+			},
+		},
+	}
+}
+
+// expectMetadataLists expects the lists of the nodes, of the metadata of the
+// NodeFeature objects and of the NodeFeatureRules done on each evaluation
+func expectMetadataLists(clnt *client.MockClient, nodeNames []string, nodeFeatures []unstructured.Unstructured,
+	nfrs []nfdk8ssigsiov1alpha1.NodeFeatureRule) {
+	gomock.InOrder(
+		clnt.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&corev1.NodeList{}), gomock.Any()).DoAndReturn(
+			func(_ context.Context, list *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+				for _, name := range nodeNames {
+					list.Items = append(list.Items, corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+				}
+				return nil
+			}),
+		clnt.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&metav1.PartialObjectMetadataList{}), gomock.Any()).DoAndReturn(
+			func(_ context.Context, list *metav1.PartialObjectMetadataList, _ ...ctrlclient.ListOption) error {
+				for _, item := range nodeFeatures {
+					list.Items = append(list.Items, metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+						Name:       item.GetName(),
+						Namespace:  item.GetNamespace(),
+						Generation: item.GetGeneration(),
+						Labels:     item.GetLabels(),
+					}})
+				}
+				return nil
+			}),
+		clnt.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRuleList{}), gomock.Any()).DoAndReturn(
+			func(_ context.Context, list *nfdk8ssigsiov1alpha1.NodeFeatureRuleList, _ ...ctrlclient.ListOption) error {
+				list.Items = nfrs
+				return nil
+			}),
+	)
+}
+
+// expectLists expects the lists of a new evaluation, which also reads the
+// NodeFeature objects
+func expectLists(clnt *client.MockClient, nodeNames []string, nodeFeatures []unstructured.Unstructured,
+	nfrs []nfdk8ssigsiov1alpha1.NodeFeatureRule) {
+	expectMetadataLists(clnt, nodeNames, nodeFeatures, nfrs)
+	clnt.EXPECT().List(gomock.Any(), gomock.AssignableToTypeOf(&unstructured.UnstructuredList{})).DoAndReturn(
+		func(_ context.Context, list *unstructured.UnstructuredList, _ ...ctrlclient.ListOption) error {
+			list.Items = nodeFeatures
+			return nil
+		})
+}
+
+// newFakeClient returns a client serving the nodes, their NodeFeature
+// objects and the NodeFeatureRule mirrors processed by nfd-master
+func newFakeClient(nodeNames []string, nodeFeatures []unstructured.Unstructured,
+	nfrs ...nfdk8ssigsiov1alpha1.NodeFeatureRule) ctrlclient.Client {
+	s, err := test.TestScheme()
+	Expect(err).NotTo(HaveOccurred())
+	Expect(nfdk8ssigsiov1alpha1.AddToScheme(s)).To(Succeed())
+	// the NodeFeature objects are only read as unstructured objects
+	s.AddKnownTypeWithName(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(NodeFeatureKind), &unstructured.Unstructured{})
+	s.AddKnownTypeWithName(nfdk8ssigsiov1alpha1.GroupVersion.WithKind(NodeFeatureKind+"List"), &unstructured.UnstructuredList{})
+
+	builder := fake.NewClientBuilder().WithScheme(s)
+	for _, name := range nodeNames {
+		builder.WithObjects(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	for i := range nodeFeatures {
+		builder.WithObjects(&nodeFeatures[i])
+	}
+	for i := range nfrs {
+		builder.WithObjects(&nfrs[i])
+	}
+	return builder.Build()
+}
+
+var _ = Describe("GetRuleStatus", func() {
+	var (
+		ctrl  *gomock.Controller
+		clnt  *client.MockClient
+		rsAPI RuleStatusAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		rsAPI = NewRuleStatusAPI(clnt)
+	})
+
+	ctx := context.Background()
+
+	It("rules are evaluated on the features of the existing nodes", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-accelerators", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{
+					pciRule("nvidia", "10de"),
+					{
+						Name:   "accelerated nic",
+						Labels: map[string]string{"accelerated-nic": "true"},
+						Taints: []corev1.Taint{{Key: "example.com/nic", Effect: corev1.TaintEffectNoSchedule}},
+						MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+							{
+								Feature: "rule.matched",
+								MatchExpressions: &nfdopenshiftiov1alpha1.MatchExpressionSet{
+									"intel": &nfdopenshiftiov1alpha1.MatchExpression{Op: nfdopenshiftiov1alpha1.MatchIsTrue},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		// the mirror is outdated, the rules of the source are evaluated
+		mirror := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-accelerators", Namespace: "openshift-nfd"},
+		}
+		other := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-nics", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{pciRule("intel", "8086")},
+			},
+		}
+
+		expectLists(clnt, []string{"worker-0", "worker-1"}, []unstructured.Unstructured{
+			nodeFeature("worker-0", "worker-0", "10de"),
+			nodeFeature("worker-0-extra", "worker-0", "8086"),
+			nodeFeature("worker-1", "", "8086"),
+			nodeFeature("removed-worker", "removed-worker", "10de"),
+		}, []nfdk8ssigsiov1alpha1.NodeFeatureRule{mirror, other})
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(2)))
+		Expect(status.Rules).To(Equal([]nfdopenshiftiov1alpha1.RuleStatus{
+			{
+				Name:             "nvidia",
+				MatchedNodeCount: 1,
+				MatchedNodes:     []string{"worker-0"},
+				Labels:           map[string]string{"feature.node.kubernetes.io/nvidia": "true"},
+			},
+			{
+				Name:             "accelerated nic",
+				MatchedNodeCount: 2,
+				MatchedNodes:     []string{"worker-0", "worker-1"},
+				Labels:           map[string]string{"feature.node.kubernetes.io/accelerated-nic": "true"},
+				Taints:           []corev1.Taint{{Key: "example.com/nic", Effect: corev1.TaintEffectNoSchedule}},
+			},
+		}))
+		Expect(status.Conditions).To(HaveLen(2))
+		Expect(status.Conditions[0].Type).To(Equal(ConditionDegraded))
+		Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+		Expect(status.Conditions[1].Type).To(Equal(ConditionConflicting))
+		Expect(status.Conditions[1].Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("the evaluation is reused until the nodes, their features or the rules change", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{pciRule("nvidia", "10de")},
+			},
+		}
+		mirror := nfdk8ssigsiov1alpha1.NodeFeatureRule{ObjectMeta: nfr.ObjectMeta, Spec: nfr.Spec}
+		worker0 := nodeFeature("worker-0", "worker-0", "10de")
+		worker1 := nodeFeature("worker-1", "worker-1", "8086")
+
+		expectLists(clnt, []string{"worker-0", "worker-1"}, []unstructured.Unstructured{worker0, worker1},
+			[]nfdk8ssigsiov1alpha1.NodeFeatureRule{mirror})
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.Rules[0].MatchedNodes).To(Equal([]string{"worker-0"}))
+
+		// the NodeFeature objects are only read again once they changed
+		expectMetadataLists(clnt, []string{"worker-0", "worker-1"}, []unstructured.Unstructured{worker0, worker1},
+			[]nfdk8ssigsiov1alpha1.NodeFeatureRule{mirror})
+
+		status, err = rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.Rules[0].MatchedNodes).To(Equal([]string{"worker-0"}))
+
+		worker1 = nodeFeature("worker-1", "worker-1", "10de")
+		worker1.SetGeneration(2)
+		expectLists(clnt, []string{"worker-0", "worker-1"}, []unstructured.Unstructured{worker0, worker1},
+			[]nfdk8ssigsiov1alpha1.NodeFeatureRule{mirror})
+
+		status, err = rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.Rules[0].MatchedNodes).To(Equal([]string{"worker-0", "worker-1"}))
+	})
+
+	It("failure to list the NodeFeatures", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+		}
+		expectMetadataLists(clnt, nil, nil, nil)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&unstructured.UnstructuredList{})).Return(fmt.Errorf("some error"))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(HaveOccurred())
+		Expect(status).To(BeNil())
+	})
+
+	It("failure to list the NodeFeatureRules", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+		}
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{}), gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&metav1.PartialObjectMetadataList{}), gomock.Any()).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdk8ssigsiov1alpha1.NodeFeatureRuleList{}), gomock.Any()).
+				Return(fmt.Errorf("some error")),
+		)
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(HaveOccurred())
+		Expect(status).To(BeNil())
+	})
+})
+
+var _ = Describe("GetRuleStatus with the fake client", func() {
+	ctx := context.Background()
+
+	mirrorOf := func(nfr nfdopenshiftiov1alpha1.NodeFeatureRule) nfdk8ssigsiov1alpha1.NodeFeatureRule {
+		return nfdk8ssigsiov1alpha1.NodeFeatureRule{ObjectMeta: nfr.ObjectMeta, Spec: nfr.Spec}
+	}
+
+	It("the matching nodes are truncated", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "static", Labels: map[string]string{"static": "true"}}},
+			},
+		}
+		nodeNames := []string{}
+		nodeFeatures := []unstructured.Unstructured{}
+		for i := 0; i < MaxReportedNodes+2; i++ {
+			name := fmt.Sprintf("worker-%02d", i)
+			nodeNames = append(nodeNames, name)
+			nodeFeatures = append(nodeFeatures, nodeFeature(name, name))
+		}
+		rsAPI := NewRuleStatusAPI(newFakeClient(nodeNames, nodeFeatures, mirrorOf(nfr)))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(MaxReportedNodes + 2)))
+		Expect(status.Rules[0].MatchedNodeCount).To(Equal(int32(MaxReportedNodes + 2)))
+		Expect(status.Rules[0].MatchedNodes).To(HaveLen(MaxReportedNodes))
+		Expect(status.Rules[0].MatchedNodes[0]).To(Equal("worker-00"))
+	})
+
+	It("the evaluation errors are reported in the Degraded condition", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "dynamic", Labels: map[string]string{"kernel": "@kernel.version.major"}}},
+			},
+		}
+		nodeNames := []string{}
+		nodeFeatures := []unstructured.Unstructured{}
+		for i := 0; i < maxReportedErrors+1; i++ {
+			name := fmt.Sprintf("worker-%d", i)
+			nodeNames = append(nodeNames, name)
+			nodeFeatures = append(nodeFeatures, nodeFeature(name, name))
+		}
+		rsAPI := NewRuleStatusAPI(newFakeClient(nodeNames, nodeFeatures, mirrorOf(nfr)))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(0)))
+		Expect(status.Conditions).To(HaveLen(2))
+		Expect(status.Conditions[0].Status).To(Equal(metav1.ConditionTrue))
+		Expect(status.Conditions[0].Reason).To(Equal(reasonEvaluationFailed))
+		Expect(status.Conditions[0].Message).To(HavePrefix(`rule "dynamic" on node worker-0: `))
+		Expect(status.Conditions[0].Message).To(HaveSuffix("; and 1 more errors"))
+	})
+
+	It("the outputs set to different values by other objects are reported in the Conflicting condition", func() {
+		target := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{
+					{Name: "zone", Labels: map[string]string{"zone": "a"}},
+					{Name: "same", Labels: map[string]string{"same": "true"}},
+				},
+			},
+		}
+		other := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{
+					{Name: "zone", Labels: map[string]string{"zone": "b"}},
+					{Name: "same", Labels: map[string]string{"same": "true"}},
+				},
+			},
+		}
+		unrelated := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{
+					{Name: "gpu", Taints: []corev1.Taint{{Key: "example.com/gpu", Effect: corev1.TaintEffectNoSchedule}}},
+				},
+			},
+		}
+		rsAPI := NewRuleStatusAPI(newFakeClient([]string{"worker-0", "worker-1"},
+			[]unstructured.Unstructured{nodeFeature("worker-0", "worker-0"), nodeFeature("worker-1", "worker-1")},
+			mirrorOf(target), mirrorOf(other), mirrorOf(unrelated)))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &target)
+
+		Expect(err).To(BeNil())
+		Expect(status.Conditions).To(HaveLen(2))
+		Expect(status.Conditions[1].Type).To(Equal(ConditionConflicting))
+		Expect(status.Conditions[1].Status).To(Equal(metav1.ConditionTrue))
+		Expect(status.Conditions[1].Reason).To(Equal(reasonOutputConflict))
+		Expect(status.Conditions[1].Message).To(Equal(`label feature.node.kubernetes.io/zone is set to "b" by rule "zone" of ` +
+			`NodeFeatureRule openshift-nfd/other and to "a" by rule "zone" of NodeFeatureRule openshift-nfd/test on node worker-0 ` +
+			`and 1 more nodes`))
+
+		status, err = rsAPI.GetRuleStatus(ctx, &unrelated)
+
+		Expect(err).To(BeNil())
+		Expect(status.Conditions[1].Status).To(Equal(metav1.ConditionFalse))
+		Expect(status.Conditions[1].Reason).To(Equal(reasonNoConflict))
+	})
+
+	It("the conflicts with the objects of other namespaces are not reported", func() {
+		target := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "zone", Labels: map[string]string{"zone": "a"}}},
+			},
+		}
+		other := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other-namespace"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "zone", Labels: map[string]string{"zone": "b"}}},
+			},
+		}
+		rsAPI := NewRuleStatusAPI(newFakeClient([]string{"worker-0"},
+			[]unstructured.Unstructured{nodeFeature("worker-0", "worker-0")}, mirrorOf(target), other))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &target)
+
+		Expect(err).To(BeNil())
+		Expect(status.Conditions[1].Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("the rules of the NodeFeatureRule replace those of its outdated mirror", func() {
+		target := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "zone", Labels: map[string]string{"zone": "b"}}},
+			},
+		}
+		mirror := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: target.ObjectMeta,
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "zone", Labels: map[string]string{"zone": "a"}}},
+			},
+		}
+		other := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "zone", Labels: map[string]string{"zone": "b"}}},
+			},
+		}
+		rsAPI := NewRuleStatusAPI(newFakeClient([]string{"worker-0"},
+			[]unstructured.Unstructured{nodeFeature("worker-0", "worker-0")}, mirror, other))
+
+		status, err := rsAPI.GetRuleStatus(ctx, &target)
+
+		Expect(err).To(BeNil())
+		Expect(status.Rules[0].Labels).To(Equal(map[string]string{"feature.node.kubernetes.io/zone": "b"}))
+		Expect(status.Conditions[1].Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("the evaluation is only done again once the generation of a NodeFeature changed", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nvidia", Namespace: "openshift-nfd"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{pciRule("nvidia", "10de")},
+			},
+		}
+		clnt := newFakeClient([]string{"worker-0"},
+			[]unstructured.Unstructured{nodeFeature("worker-0", "worker-0", "8086")}, mirrorOf(nfr))
+		rsAPI := NewRuleStatusAPI(clnt)
+
+		status, err := rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(0)))
+
+		// a NodeFeature updated without a new generation is not read again,
+		// the fake client does not bump it like the API server does
+		stored := nodeFeature("", "")
+		Expect(clnt.Get(ctx, types.NamespacedName{Namespace: "openshift-nfd", Name: "worker-0"}, &stored)).To(Succeed())
+		updated := nodeFeature("worker-0", "worker-0", "10de")
+		updated.SetGeneration(stored.GetGeneration())
+		updated.SetResourceVersion(stored.GetResourceVersion())
+		Expect(clnt.Update(ctx, &updated)).To(Succeed())
+
+		status, err = rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(0)))
+
+		updated.SetGeneration(2)
+		Expect(clnt.Update(ctx, &updated)).To(Succeed())
+
+		status, err = rsAPI.GetRuleStatus(ctx, &nfr)
+
+		Expect(err).To(BeNil())
+		Expect(status.MatchedNodeCount).To(Equal(int32(1)))
+	})
+})
+
+var _ = Describe("conflicts", func() {
+	It("contradictory taints and extended resources of different objects are reported", func() {
+		nodeFeatures := map[string]*rules.Features{"worker-0": {}}
+		objects := []ruleObject{
+			{key: types.NamespacedName{Namespace: "openshift-nfd", Name: "b-gpu"}, rules: []nfdopenshiftiov1alpha1.Rule{
+				{
+					Name:              "gpu",
+					Taints:            []corev1.Taint{{Key: "example.com/gpu", Effect: corev1.TaintEffectNoExecute}},
+					ExtendedResources: map[string]string{"gpus": "1"},
+				},
+			}},
+			{key: types.NamespacedName{Namespace: "openshift-nfd", Name: "a-gpu"}, rules: []nfdopenshiftiov1alpha1.Rule{
+				{
+					Name:              "gpu",
+					Taints:            []corev1.Taint{{Key: "example.com/gpu", Effect: corev1.TaintEffectNoSchedule}},
+					ExtendedResources: map[string]string{"gpus": "1"},
+				},
+			}},
+		}
+
+		conflicts := evaluateAll(objects, nodeFeatures).conflicts()
+
+		Expect(conflicts).To(Equal([]Conflict{
+			{
+				Output: "taint",
+				Key:    "example.com/gpu",
+				First: ConflictingRule{
+					Object: types.NamespacedName{Namespace: "openshift-nfd", Name: "a-gpu"},
+					Rule:   "gpu",
+					Value:  ":NoSchedule",
+				},
+				Second: ConflictingRule{
+					Object: types.NamespacedName{Namespace: "openshift-nfd", Name: "b-gpu"},
+					Rule:   "gpu",
+					Value:  ":NoExecute",
+				},
+				Node:      "worker-0",
+				NodeCount: 1,
+			},
+		}))
+	})
+})
@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ruletemplate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+// TemplateLabel is set on the rendered NodeFeatureRules to the name of their
+// NodeFeatureRuleTemplate
+const TemplateLabel = "nfd.openshift.io/rule-template"
+
+// referenceRegexp matches the references to a parameter, $(name)
+var referenceRegexp = regexp.MustCompile(`\$\(([a-zA-Z][a-zA-Z0-9_]*)\)`)
+
+// ValidateTemplate checks that the defaults of the parameters are valid and
+// that the rules only reference declared parameters
+func ValidateTemplate(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	declared := sets.New[string]()
+	for i := range spec.Parameters {
+		param := &spec.Parameters[i]
+		idxPath := fldPath.Child("parameters").Index(i)
+		declared.Insert(param.Name)
+		if param.Pattern != "" {
+			if _, err := compilePattern(param.Pattern); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("pattern"), param.Pattern,
+					fmt.Sprintf("must be a valid regular expression: %v", err)))
+				continue
+			}
+		}
+		if param.Default == nil {
+			continue
+		}
+		if param.Required {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("default"), *param.Default, "a required parameter may not have a default"))
+		} else if err := validateValue(param, *param.Default); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("default"), *param.Default, err.Error()))
+		}
+	}
+
+	references, err := references(spec.Rules)
+	if err != nil {
+		return append(allErrs, field.InternalError(fldPath.Child("rules"), err))
+	}
+	for _, name := range sets.List(references.Difference(declared)) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rules"), "$("+name+")",
+			fmt.Sprintf("references the undeclared parameter %s", name)))
+	}
+
+	return allErrs
+}
+
+// ValidateParameters checks the parameter values of an instance against the
+// parameters declared by its template
+func ValidateParameters(params []nfdopenshiftiov1alpha1.TemplateParameter, values map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := make([]string, 0, len(params))
+	for _, param := range params {
+		names = append(names, param.Name)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !slices.Contains(names, key) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(key), key, names))
+		}
+	}
+
+	for i := range params {
+		param := &params[i]
+		value, ok := values[param.Name]
+		if !ok {
+			if param.Required {
+				allErrs = append(allErrs, field.Required(fldPath.Key(param.Name), "parameter is required by the template"))
+			}
+			continue
+		}
+		if err := validateValue(param, value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(param.Name), value, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// Render returns the rules of the template with the references to the
+// parameters replaced by the values of the instance, or by the defaults of
+// the parameters the instance does not set
+func Render(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec, values map[string]string) (*nfdopenshiftiov1alpha1.NodeFeatureRuleSpec, error) {
+	if errs := ValidateParameters(spec.Parameters, values, field.NewPath("spec", "parameters")); len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	resolved := map[string]string{}
+	for _, param := range spec.Parameters {
+		if param.Default != nil {
+			resolved[param.Name] = *param.Default
+		}
+	}
+	for name, value := range values {
+		resolved[name] = value
+	}
+
+	// the rules are substituted in their JSON form, so that every string of
+	// the rules is covered, whatever field it belongs to
+	var rules any
+	if err := convert(spec.Rules, &rules); err != nil {
+		return nil, err
+	}
+	rules, err := substitute(rules, resolved)
+	if err != nil {
+		return nil, err
+	}
+	rendered := &nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{}
+	if err := convert(rules, &rendered.Rules); err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+func validateValue(param *nfdopenshiftiov1alpha1.TemplateParameter, value string) error {
+	switch param.Type {
+	case nfdopenshiftiov1alpha1.ParameterTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+	case nfdopenshiftiov1alpha1.ParameterTypeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("must be true or false")
+		}
+	}
+	if len(param.Enum) != 0 && !slices.Contains(param.Enum, value) {
+		return fmt.Errorf("must be one of %q", param.Enum)
+	}
+	if param.Pattern != "" {
+		re, err := compilePattern(param.Pattern)
+		if err != nil {
+			return fmt.Errorf("the pattern of the parameter is not a valid regular expression: %w", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match the pattern %s", param.Pattern)
+		}
+	}
+	return nil
+}
+
+// compilePattern anchors the pattern so that it matches the whole value
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// references returns the names of the parameters referenced by the rules
+func references(rules []nfdopenshiftiov1alpha1.EmbeddedRule) (sets.Set[string], error) {
+	var generic any
+	if err := convert(rules, &generic); err != nil {
+		return nil, err
+	}
+	names := sets.New[string]()
+	walkStrings(generic, func(s string) {
+		for _, match := range referenceRegexp.FindAllStringSubmatch(s, -1) {
+			names.Insert(match[1])
+		}
+	})
+	return names, nil
+}
+
+func walkStrings(value any, visit func(string)) {
+	switch v := value.(type) {
+	case string:
+		visit(v)
+	case []any:
+		for _, elem := range v {
+			walkStrings(elem, visit)
+		}
+	case map[string]any:
+		for key, elem := range v {
+			visit(key)
+			walkStrings(elem, visit)
+		}
+	}
+}
+
+// substitute replaces the references in the strings and the map keys of the
+// JSON value
+func substitute(value any, values map[string]string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return substituteString(v, values)
+	case []any:
+		for i, elem := range v {
+			substituted, err := substitute(elem, values)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = substituted
+		}
+		return v, nil
+	case map[string]any:
+		substitutedMap := make(map[string]any, len(v))
+		for key, elem := range v {
+			substitutedKey, err := substituteString(key, values)
+			if err != nil {
+				return nil, err
+			}
+			substituted, err := substitute(elem, values)
+			if err != nil {
+				return nil, err
+			}
+			substitutedMap[substitutedKey] = substituted
+		}
+		return substitutedMap, nil
+	default:
+		return v, nil
+	}
+}
+
+func substituteString(s string, values map[string]string) (string, error) {
+	var err error
+	substituted := referenceRegexp.ReplaceAllStringFunc(s, func(reference string) string {
+		name := referenceRegexp.FindStringSubmatch(reference)[1]
+		value, ok := values[name]
+		if !ok {
+			err = fmt.Errorf("parameter %s is referenced by the rules but has no value", name)
+		}
+		return value
+	})
+	return substituted, err
+}
+
+// convert copies in into out through their JSON representation
+func convert(in, out any) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode the rules: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode the rules: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ruletemplate
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+// newKernelModuleTemplate returns a template labelling the nodes where a
+// kernel module is loaded
+func newKernelModuleTemplate() nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec {
+	return nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec{
+		Parameters: []nfdopenshiftiov1alpha1.TemplateParameter{
+			{Name: "module", Type: nfdopenshiftiov1alpha1.ParameterTypeString, Required: true, Pattern: "[a-z0-9_]+"},
+			{Name: "label", Type: nfdopenshiftiov1alpha1.ParameterTypeString, Default: ptr.To("kmod")},
+			{Name: "minVersion", Type: nfdopenshiftiov1alpha1.ParameterTypeInteger, Default: ptr.To("5")},
+			{Name: "mode", Type: nfdopenshiftiov1alpha1.ParameterTypeString, Enum: []string{"loaded", "builtin"}, Default: ptr.To("loaded")},
+		},
+		Rules: []nfdopenshiftiov1alpha1.EmbeddedRule{
+			{
+				Name:   "$(module) $(mode)",
+				Labels: map[string]string{"$(label)-$(module)": "true"},
+				MatchFeatures: nfdopenshiftiov1alpha1.EmbeddedFeatureMatcher{
+					{
+						Feature: "kernel.$(mode)module",
+						MatchExpressions: &nfdopenshiftiov1alpha1.EmbeddedMatchExpressionSet{
+							"$(module)": {Op: nfdopenshiftiov1alpha1.MatchExists},
+						},
+					},
+					{
+						Feature: "kernel.version",
+						MatchExpressions: &nfdopenshiftiov1alpha1.EmbeddedMatchExpressionSet{
+							"major": {Op: nfdopenshiftiov1alpha1.MatchGe, Value: nfdopenshiftiov1alpha1.MatchValue{"$(minVersion)"}},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Render", func() {
+	It("references are replaced in the values and the keys of the rules", func() {
+		spec := newKernelModuleTemplate()
+
+		rendered, err := Render(&spec, map[string]string{"module": "vfio_pci", "minVersion": "6"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rendered.Rules).To(HaveLen(1))
+		rule := rendered.Rules[0]
+		Expect(rule.Name).To(Equal("vfio_pci loaded"))
+		Expect(rule.Labels).To(Equal(map[string]string{"kmod-vfio_pci": "true"}))
+		Expect(rule.MatchFeatures[0].Feature).To(Equal("kernel.loadedmodule"))
+		Expect(*rule.MatchFeatures[0].MatchExpressions).To(HaveKey("vfio_pci"))
+		Expect((*rule.MatchFeatures[1].MatchExpressions)["major"].Value).To(Equal(nfdopenshiftiov1alpha1.MatchValue{"6"}))
+	})
+
+	It("the template is left unmodified", func() {
+		spec := newKernelModuleTemplate()
+		unmodified := spec.DeepCopy()
+
+		_, err := Render(&spec, map[string]string{"module": "vfio_pci"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(&spec).To(Equal(unmodified))
+	})
+
+	It("invalid parameters are not rendered", func() {
+		spec := newKernelModuleTemplate()
+
+		_, err := Render(&spec, map[string]string{"minVersion": "six"})
+		Expect(err).To(MatchError(And(ContainSubstring("spec.parameters[module]"), ContainSubstring("spec.parameters[minVersion]"))))
+	})
+
+	It("a referenced parameter without a value is an error", func() {
+		spec := newKernelModuleTemplate()
+		spec.Parameters = append(spec.Parameters, nfdopenshiftiov1alpha1.TemplateParameter{Name: "vendor"})
+		spec.Rules[0].Labels["vendor"] = "$(vendor)"
+
+		_, err := Render(&spec, map[string]string{"module": "vfio_pci"})
+		Expect(err).To(MatchError(ContainSubstring("parameter vendor is referenced by the rules but has no value")))
+	})
+})
+
+var _ = Describe("ValidateParameters", func() {
+	fldPath := field.NewPath("spec", "parameters")
+	params := newKernelModuleTemplate().Parameters
+
+	It("values matching the parameters are accepted", func() {
+		errs := ValidateParameters(params, map[string]string{"module": "vfio_pci", "mode": "builtin", "minVersion": "-1"}, fldPath)
+		Expect(errs).To(BeEmpty())
+	})
+
+	DescribeTable("invalid values are rejected with the offending parameter", func(values map[string]string, field string) {
+		errs := ValidateParameters(params, values, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", field)))
+	},
+		Entry("missing required parameter", map[string]string{}, "spec.parameters[module]"),
+		Entry("undeclared parameter", map[string]string{"module": "vfio_pci", "vendor": "intel"}, "spec.parameters[vendor]"),
+		Entry("value not matching the pattern", map[string]string{"module": "vfio-pci"}, "spec.parameters[module]"),
+		Entry("value not an integer", map[string]string{"module": "vfio_pci", "minVersion": "5.1"}, "spec.parameters[minVersion]"),
+		Entry("value not in the enum", map[string]string{"module": "vfio_pci", "mode": "unloaded"}, "spec.parameters[mode]"),
+	)
+
+	It("booleans only accept true and false", func() {
+		boolParams := []nfdopenshiftiov1alpha1.TemplateParameter{{Name: "enabled", Type: nfdopenshiftiov1alpha1.ParameterTypeBoolean}}
+		Expect(ValidateParameters(boolParams, map[string]string{"enabled": "true"}, fldPath)).To(BeEmpty())
+		Expect(ValidateParameters(boolParams, map[string]string{"enabled": "yes"}, fldPath)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("ValidateTemplate", func() {
+	fldPath := field.NewPath("spec")
+
+	It("a valid template is accepted", func() {
+		spec := newKernelModuleTemplate()
+		Expect(ValidateTemplate(&spec, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("invalid templates are rejected with the offending field", func(mutate func(*nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec), field string) {
+		spec := newKernelModuleTemplate()
+		mutate(&spec)
+		errs := ValidateTemplate(&spec, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", field)))
+	},
+		Entry("pattern not compiling", func(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec) {
+			spec.Parameters[0].Pattern = "[a-z"
+		}, "spec.parameters[0].pattern"),
+		Entry("default of a required parameter", func(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec) {
+			spec.Parameters[0].Default = ptr.To("vfio_pci")
+		}, "spec.parameters[0].default"),
+		Entry("default not matching the type", func(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec) {
+			spec.Parameters[2].Default = ptr.To("five")
+		}, "spec.parameters[2].default"),
+		Entry("default not in the enum", func(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec) {
+			spec.Parameters[3].Default = ptr.To("unloaded")
+		}, "spec.parameters[3].default"),
+		Entry("reference to an undeclared parameter", func(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec) {
+			spec.Rules[0].Labels["$(vendor)"] = "true"
+		}, "spec.rules"),
+	)
+})
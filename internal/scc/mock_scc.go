@@ -16,6 +16,7 @@ import (
 	v1 "github.com/openshift/api/security/v1"
 	v10 "github.com/openshift/cluster-nfd-operator/api/v1"
 	gomock "go.uber.org/mock/gomock"
+	v11 "k8s.io/api/core/v1"
 )
 
 // MockSccAPI is a mock of SccAPI interface.
@@ -42,18 +43,32 @@ func (m *MockSccAPI) EXPECT() *MockSccAPIMockRecorder {
 	return m.recorder
 }
 
-// DeleteSCC mocks base method.
-func (m *MockSccAPI) DeleteSCC(ctx context.Context, sccName string) error {
+// RemoveSCCUser mocks base method.
+func (m *MockSccAPI) RemoveSCCUser(ctx context.Context, nfdInstance *v10.NodeFeatureDiscovery, sccName, serviceAccount string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSCC", ctx, sccName)
+	ret := m.ctrl.Call(m, "RemoveSCCUser", ctx, nfdInstance, sccName, serviceAccount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSCC indicates an expected call of DeleteSCC.
-func (mr *MockSccAPIMockRecorder) DeleteSCC(ctx, sccName any) *gomock.Call {
+// RemoveSCCUser indicates an expected call of RemoveSCCUser.
+func (mr *MockSccAPIMockRecorder) RemoveSCCUser(ctx, nfdInstance, sccName, serviceAccount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSCC", reflect.TypeOf((*MockSccAPI)(nil).DeleteSCC), ctx, sccName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSCCUser", reflect.TypeOf((*MockSccAPI)(nil).RemoveSCCUser), ctx, nfdInstance, sccName, serviceAccount)
+}
+
+// SetPodSecurityLabelsAsDesired mocks base method.
+func (m *MockSccAPI) SetPodSecurityLabelsAsDesired(ctx context.Context, nfdInstance *v10.NodeFeatureDiscovery, namespace *v11.Namespace) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPodSecurityLabelsAsDesired", ctx, nfdInstance, namespace)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPodSecurityLabelsAsDesired indicates an expected call of SetPodSecurityLabelsAsDesired.
+func (mr *MockSccAPIMockRecorder) SetPodSecurityLabelsAsDesired(ctx, nfdInstance, namespace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPodSecurityLabelsAsDesired", reflect.TypeOf((*MockSccAPI)(nil).SetPodSecurityLabelsAsDesired), ctx, nfdInstance, namespace)
 }
 
 // SetTopologySCCAsDesired mocks base method.
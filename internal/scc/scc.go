@@ -19,10 +19,13 @@ package scc
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -35,7 +38,37 @@ import (
 type SccAPI interface {
 	SetWorkerSCCAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, workerSCC *securityv1.SecurityContextConstraints) error
 	SetTopologySCCAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, topologySCC *securityv1.SecurityContextConstraints) error
-	DeleteSCC(ctx context.Context, sccName string) error
+	RemoveSCCUser(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, sccName, serviceAccount string) error
+	SetPodSecurityLabelsAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, namespace *corev1.Namespace) error
+}
+
+const (
+	WorkerServiceAccount   = "nfd-worker"
+	TopologyServiceAccount = "nfd-topology-updater"
+
+	// podSecurityLevel is the Pod Security Standard required by the operands:
+	// nfd-worker uses the host network and hostPath volumes
+	podSecurityLevel = "privileged"
+)
+
+var podSecurityLabels = []string{
+	"pod-security.kubernetes.io/enforce",
+	"pod-security.kubernetes.io/audit",
+	"pod-security.kubernetes.io/warn",
+}
+
+// IsSCCAPIAvailable reports whether the cluster serves the
+// SecurityContextConstraints API, which is only the case on OpenShift
+func IsSCCAPIAvailable(mapper meta.RESTMapper) (bool, error) {
+	gk := schema.GroupKind{Group: securityv1.GroupName, Kind: "SecurityContextConstraints"}
+	_, err := mapper.RESTMapping(gk, securityv1.GroupVersion.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to discover the %s API: %w", gk, err)
+	}
+	return true, nil
 }
 
 type scc struct {
@@ -76,9 +109,9 @@ func (s *scc) SetWorkerSCCAsDesired(ctx context.Context, nfdInstance *nfdv1.Node
 	workerSCC.SeccompProfiles = []string{
 		"*",
 	}
-	workerSCC.Users = []string{
-		"system:serviceaccount:openshift-nfd:nfd-worker",
-	}
+	// the SCC may be shared with the instances of other namespaces, their
+	// users are kept
+	workerSCC.Users = addUser(workerSCC.Users, serviceAccountUser(nfdInstance.Namespace, WorkerServiceAccount))
 	workerSCC.Volumes = []securityv1.FSType{
 		securityv1.FSTypeConfigMap,
 		securityv1.FSTypeDownwardAPI,
@@ -118,9 +151,7 @@ func (s *scc) SetTopologySCCAsDesired(ctx context.Context, nfdInstance *nfdv1.No
 	topologySCC.SeccompProfiles = []string{
 		"*",
 	}
-	topologySCC.Users = []string{
-		"system:serviceaccount:openshift-nfd:nfd-topology-updater",
-	}
+	topologySCC.Users = addUser(topologySCC.Users, serviceAccountUser(nfdInstance.Namespace, TopologyServiceAccount))
 	topologySCC.Volumes = []securityv1.FSType{
 		securityv1.FSTypeConfigMap,
 		securityv1.FSTypeDownwardAPI,
@@ -133,15 +164,61 @@ func (s *scc) SetTopologySCCAsDesired(ctx context.Context, nfdInstance *nfdv1.No
 	return nil
 }
 
-func (s *scc) DeleteSCC(ctx context.Context, sccName string) error {
-	sc := securityv1.SecurityContextConstraints{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: sccName,
-		},
+// RemoveSCCUser removes the service account of the instance namespace from
+// the users of the SCC. The SCC is deleted along with its last user, so that
+// it is not pulled out from under the instances of other namespaces
+func (s *scc) RemoveSCCUser(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, sccName, serviceAccount string) error {
+	sc := securityv1.SecurityContextConstraints{}
+	err := s.client.Get(ctx, client.ObjectKey{Name: sccName}, &sc)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get SCC %s: %w", sccName, err)
+	}
+
+	user := serviceAccountUser(nfdInstance.Namespace, serviceAccount)
+	users := slices.DeleteFunc(slices.Clone(sc.Users), func(u string) bool { return u == user })
+	if len(users) == 0 {
+		err = s.client.Delete(ctx, &sc)
+		if err != nil && client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete SCC %s: %w", sccName, err)
+		}
+		return nil
+	}
+	if len(users) == len(sc.Users) {
+		return nil
+	}
+
+	sc.Users = users
+	err = s.client.Update(ctx, &sc)
+	if err != nil {
+		return fmt.Errorf("failed to remove user %s from SCC %s: %w", user, sccName, err)
+	}
+	return nil
+}
+
+// SetPodSecurityLabelsAsDesired labels the namespace of the instance so that
+// Pod Security Admission admits the operands. It is used instead of the SCCs
+// on clusters without the SCC API. The labels are kept on finalization, the
+// namespace may be shared with other instances
+func (s *scc) SetPodSecurityLabelsAsDesired(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, namespace *corev1.Namespace) error {
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
 	}
-	err := s.client.Delete(ctx, &sc)
-	if err != nil && client.IgnoreNotFound(err) != nil {
-		return fmt.Errorf("failed to delete SCC %s: %w", sccName, err)
+	for _, label := range podSecurityLabels {
+		namespace.Labels[label] = podSecurityLevel
 	}
 	return nil
 }
+
+func serviceAccountUser(namespace, serviceAccount string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+}
+
+func addUser(users []string, user string) []string {
+	if slices.Contains(users, user) {
+		return users
+	}
+	return append(users, user)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scc
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	securityv1 "github.com/openshift/api/security/v1"
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("IsSCCAPIAvailable", func() {
+	It("SCC API is served", func() {
+		mapper := meta.NewDefaultRESTMapper(nil)
+		mapper.Add(securityv1.GroupVersion.WithKind("SecurityContextConstraints"), meta.RESTScopeRoot)
+
+		available, err := IsSCCAPIAvailable(mapper)
+		Expect(err).To(BeNil())
+		Expect(available).To(BeTrue())
+	})
+
+	It("SCC API is not served", func() {
+		available, err := IsSCCAPIAvailable(meta.NewDefaultRESTMapper(nil))
+		Expect(err).To(BeNil())
+		Expect(available).To(BeFalse())
+	})
+})
+
+var _ = Describe("SetPodSecurityLabelsAsDesired", func() {
+	It("the namespace is labelled with the privileged level, other labels are kept", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "nfd-namespace"},
+		}
+		namespace := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "nfd-namespace",
+				Labels: map[string]string{
+					"team":                               "a",
+					"pod-security.kubernetes.io/enforce": "restricted",
+				},
+			},
+		}
+
+		err := NewSccAPI(nil, scheme).SetPodSecurityLabelsAsDesired(context.Background(), &nfdCR, &namespace)
+		Expect(err).To(BeNil())
+		Expect(namespace.Labels).To(Equal(map[string]string{
+			"team":                               "a",
+			"pod-security.kubernetes.io/enforce": "privileged",
+			"pod-security.kubernetes.io/audit":   "privileged",
+			"pod-security.kubernetes.io/warn":    "privileged",
+		}))
+	})
+})
+
+var _ = Describe("SetWorkerSCCAsDesired", func() {
+	var sccAPI SccAPI
+
+	BeforeEach(func() {
+		sccAPI = NewSccAPI(nil, scheme)
+	})
+
+	ctx := context.Background()
+	nfdCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "nfd-namespace"},
+	}
+
+	It("the service account of the instance namespace is allowed", func() {
+		workerSCC := securityv1.SecurityContextConstraints{}
+
+		err := sccAPI.SetWorkerSCCAsDesired(ctx, &nfdCR, &workerSCC)
+		Expect(err).To(BeNil())
+		Expect(workerSCC.Users).To(Equal([]string{"system:serviceaccount:nfd-namespace:nfd-worker"}))
+	})
+
+	It("the users of the other instances sharing the SCC are kept", func() {
+		workerSCC := securityv1.SecurityContextConstraints{
+			Users: []string{"system:serviceaccount:other-namespace:nfd-worker", "system:serviceaccount:nfd-namespace:nfd-worker"},
+		}
+
+		err := sccAPI.SetWorkerSCCAsDesired(ctx, &nfdCR, &workerSCC)
+		Expect(err).To(BeNil())
+		Expect(workerSCC.Users).To(Equal([]string{
+			"system:serviceaccount:other-namespace:nfd-worker",
+			"system:serviceaccount:nfd-namespace:nfd-worker",
+		}))
+	})
+})
+
+var _ = Describe("SetTopologySCCAsDesired", func() {
+	It("the service account of the instance namespace is added to the users", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "nfd-namespace"},
+		}
+		topologySCC := securityv1.SecurityContextConstraints{
+			Users: []string{"system:serviceaccount:other-namespace:nfd-topology-updater"},
+		}
+
+		err := NewSccAPI(nil, scheme).SetTopologySCCAsDesired(context.Background(), &nfdCR, &topologySCC)
+		Expect(err).To(BeNil())
+		Expect(topologySCC.Users).To(Equal([]string{
+			"system:serviceaccount:other-namespace:nfd-topology-updater",
+			"system:serviceaccount:nfd-namespace:nfd-topology-updater",
+		}))
+	})
+})
+
+var _ = Describe("RemoveSCCUser", func() {
+	var (
+		ctrl   *gomock.Controller
+		clnt   *client.MockClient
+		sccAPI SccAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		sccAPI = NewSccAPI(clnt, scheme)
+	})
+
+	ctx := context.Background()
+	nfdCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-cr", Namespace: "nfd-namespace"},
+	}
+	getSCC := func(users ...string) func(_ interface{}, _ interface{}, sc *securityv1.SecurityContextConstraints, _ ...ctrlclient.GetOption) error {
+		return func(_ interface{}, _ interface{}, sc *securityv1.SecurityContextConstraints, _ ...ctrlclient.GetOption) error {
+			sc.Name = "nfd-worker"
+			sc.Users = users
+			return nil
+		}
+	}
+
+	It("SCC is deleted along with its last user", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getSCC("system:serviceaccount:nfd-namespace:nfd-worker")),
+			clnt.EXPECT().Delete(ctx, gomock.Any()).Return(nil),
+		)
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(BeNil())
+	})
+
+	It("SCC shared with another namespace only loses the user of the instance", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getSCC(
+				"system:serviceaccount:other-namespace:nfd-worker",
+				"system:serviceaccount:nfd-namespace:nfd-worker",
+			)),
+			clnt.EXPECT().Update(ctx, gomock.Cond(func(x any) bool {
+				sc, ok := x.(*securityv1.SecurityContextConstraints)
+				return ok && len(sc.Users) == 1 && sc.Users[0] == "system:serviceaccount:other-namespace:nfd-worker"
+			})).Return(nil),
+		)
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(BeNil())
+	})
+
+	It("SCC not used by the instance is left untouched", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getSCC("system:serviceaccount:other-namespace:nfd-worker"))
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(BeNil())
+	})
+
+	It("SCC does not exist", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(apierrors.NewNotFound(schema.GroupResource{}, "nfd-worker"))
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(BeNil())
+	})
+
+	It("failure to get the SCC", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("failure to update the SCC", func() {
+		gomock.InOrder(
+			clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).DoAndReturn(getSCC(
+				"system:serviceaccount:other-namespace:nfd-worker",
+				"system:serviceaccount:nfd-namespace:nfd-worker",
+			)),
+			clnt.EXPECT().Update(ctx, gomock.Any()).Return(fmt.Errorf("some error")),
+		)
+
+		err := sccAPI.RemoveSCCUser(ctx, &nfdCR, "nfd-worker", WorkerServiceAccount)
+		Expect(err).To(HaveOccurred())
+	})
+})
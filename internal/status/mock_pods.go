@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pods.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pods.go -package=status -destination=mock_pods.go podDiagnosticsAPI
+//
+
+// Package status is a generated GoMock package.
+package status
+
+import (
+	context "context"
+	reflect "reflect"
+
+	v1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	gomock "go.uber.org/mock/gomock"
+	v10 "k8s.io/api/apps/v1"
+)
+
+// MockpodDiagnosticsAPI is a mock of podDiagnosticsAPI interface.
+type MockpodDiagnosticsAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockpodDiagnosticsAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockpodDiagnosticsAPIMockRecorder is the mock recorder for MockpodDiagnosticsAPI.
+type MockpodDiagnosticsAPIMockRecorder struct {
+	mock *MockpodDiagnosticsAPI
+}
+
+// NewMockpodDiagnosticsAPI creates a new mock instance.
+func NewMockpodDiagnosticsAPI(ctrl *gomock.Controller) *MockpodDiagnosticsAPI {
+	mock := &MockpodDiagnosticsAPI{ctrl: ctrl}
+	mock.recorder = &MockpodDiagnosticsAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockpodDiagnosticsAPI) EXPECT() *MockpodDiagnosticsAPIMockRecorder {
+	return m.recorder
+}
+
+// getDaemonSetPodFailures mocks base method.
+func (m *MockpodDiagnosticsAPI) getDaemonSetPodFailures(ctx context.Context, ds *v10.DaemonSet) []v1.PodFailure {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getDaemonSetPodFailures", ctx, ds)
+	ret0, _ := ret[0].([]v1.PodFailure)
+	return ret0
+}
+
+// getDaemonSetPodFailures indicates an expected call of getDaemonSetPodFailures.
+func (mr *MockpodDiagnosticsAPIMockRecorder) getDaemonSetPodFailures(ctx, ds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getDaemonSetPodFailures", reflect.TypeOf((*MockpodDiagnosticsAPI)(nil).getDaemonSetPodFailures), ctx, ds)
+}
+
+// getDeploymentPodFailures mocks base method.
+func (m *MockpodDiagnosticsAPI) getDeploymentPodFailures(ctx context.Context, dep *v10.Deployment) []v1.PodFailure {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getDeploymentPodFailures", ctx, dep)
+	ret0, _ := ret[0].([]v1.PodFailure)
+	return ret0
+}
+
+// getDeploymentPodFailures indicates an expected call of getDeploymentPodFailures.
+func (mr *MockpodDiagnosticsAPIMockRecorder) getDeploymentPodFailures(ctx, dep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getDeploymentPodFailures", reflect.TypeOf((*MockpodDiagnosticsAPI)(nil).getDeploymentPodFailures), ctx, dep)
+}
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source=status.go -package=status -destination=mock_status.go statusHelperAPI
+//	mockgen -source=status.go -package=status -destination=mock_status.go StatusAPI
 //
 
 // Package status is a generated GoMock package.
@@ -56,18 +56,46 @@ func (mr *MockStatusAPIMockRecorder) AreConditionsEqual(prevConditions, newCondi
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AreConditionsEqual", reflect.TypeOf((*MockStatusAPI)(nil).AreConditionsEqual), prevConditions, newConditions)
 }
 
+// GetComponents mocks base method.
+func (m *MockStatusAPI) GetComponents(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v1.ComponentStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComponents", ctx, nfdInstance)
+	ret0, _ := ret[0].([]v1.ComponentStatus)
+	return ret0
+}
+
+// GetComponents indicates an expected call of GetComponents.
+func (mr *MockStatusAPIMockRecorder) GetComponents(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComponents", reflect.TypeOf((*MockStatusAPI)(nil).GetComponents), ctx, nfdInstance)
+}
+
 // GetConditions mocks base method.
-func (m *MockStatusAPI) GetConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v10.Condition {
+func (m *MockStatusAPI) GetConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery, components []v1.ComponentStatus) []v10.Condition {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetConditions", ctx, nfdInstance)
+	ret := m.ctrl.Call(m, "GetConditions", ctx, nfdInstance, components)
 	ret0, _ := ret[0].([]v10.Condition)
 	return ret0
 }
 
 // GetConditions indicates an expected call of GetConditions.
-func (mr *MockStatusAPIMockRecorder) GetConditions(ctx, nfdInstance any) *gomock.Call {
+func (mr *MockStatusAPIMockRecorder) GetConditions(ctx, nfdInstance, components any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConditions", reflect.TypeOf((*MockStatusAPI)(nil).GetConditions), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConditions", reflect.TypeOf((*MockStatusAPI)(nil).GetConditions), ctx, nfdInstance, components)
+}
+
+// GetMasterLeader mocks base method.
+func (m *MockStatusAPI) GetMasterLeader(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMasterLeader", ctx, nfdInstance)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetMasterLeader indicates an expected call of GetMasterLeader.
+func (mr *MockStatusAPIMockRecorder) GetMasterLeader(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMasterLeader", reflect.TypeOf((*MockStatusAPI)(nil).GetMasterLeader), ctx, nfdInstance)
 }
 
 // MockstatusHelperAPI is a mock of statusHelperAPI interface.
@@ -94,58 +122,72 @@ func (m *MockstatusHelperAPI) EXPECT() *MockstatusHelperAPIMockRecorder {
 	return m.recorder
 }
 
-// getGCNotAvailableConditions mocks base method.
-func (m *MockstatusHelperAPI) getGCNotAvailableConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v10.Condition {
+// getGCStatus mocks base method.
+func (m *MockstatusHelperAPI) getGCStatus(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) v1.ComponentStatus {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "getGCNotAvailableConditions", ctx, nfdInstance)
-	ret0, _ := ret[0].([]v10.Condition)
+	ret := m.ctrl.Call(m, "getGCStatus", ctx, nfdInstance)
+	ret0, _ := ret[0].(v1.ComponentStatus)
 	return ret0
 }
 
-// getGCNotAvailableConditions indicates an expected call of getGCNotAvailableConditions.
-func (mr *MockstatusHelperAPIMockRecorder) getGCNotAvailableConditions(ctx, nfdInstance any) *gomock.Call {
+// getGCStatus indicates an expected call of getGCStatus.
+func (mr *MockstatusHelperAPIMockRecorder) getGCStatus(ctx, nfdInstance any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getGCNotAvailableConditions", reflect.TypeOf((*MockstatusHelperAPI)(nil).getGCNotAvailableConditions), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getGCStatus", reflect.TypeOf((*MockstatusHelperAPI)(nil).getGCStatus), ctx, nfdInstance)
 }
 
-// getMasterNotAvailableConditions mocks base method.
-func (m *MockstatusHelperAPI) getMasterNotAvailableConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v10.Condition {
+// getMasterStatus mocks base method.
+func (m *MockstatusHelperAPI) getMasterStatus(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) v1.ComponentStatus {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "getMasterNotAvailableConditions", ctx, nfdInstance)
-	ret0, _ := ret[0].([]v10.Condition)
+	ret := m.ctrl.Call(m, "getMasterStatus", ctx, nfdInstance)
+	ret0, _ := ret[0].(v1.ComponentStatus)
 	return ret0
 }
 
-// getMasterNotAvailableConditions indicates an expected call of getMasterNotAvailableConditions.
-func (mr *MockstatusHelperAPIMockRecorder) getMasterNotAvailableConditions(ctx, nfdInstance any) *gomock.Call {
+// getMasterStatus indicates an expected call of getMasterStatus.
+func (mr *MockstatusHelperAPIMockRecorder) getMasterStatus(ctx, nfdInstance any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getMasterNotAvailableConditions", reflect.TypeOf((*MockstatusHelperAPI)(nil).getMasterNotAvailableConditions), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getMasterStatus", reflect.TypeOf((*MockstatusHelperAPI)(nil).getMasterStatus), ctx, nfdInstance)
 }
 
-// getTopologyNotAvailableConditions mocks base method.
-func (m *MockstatusHelperAPI) getTopologyNotAvailableConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v10.Condition {
+// getTopologyStatus mocks base method.
+func (m *MockstatusHelperAPI) getTopologyStatus(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) v1.ComponentStatus {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "getTopologyNotAvailableConditions", ctx, nfdInstance)
-	ret0, _ := ret[0].([]v10.Condition)
+	ret := m.ctrl.Call(m, "getTopologyStatus", ctx, nfdInstance)
+	ret0, _ := ret[0].(v1.ComponentStatus)
 	return ret0
 }
 
-// getTopologyNotAvailableConditions indicates an expected call of getTopologyNotAvailableConditions.
-func (mr *MockstatusHelperAPIMockRecorder) getTopologyNotAvailableConditions(ctx, nfdInstance any) *gomock.Call {
+// getTopologyStatus indicates an expected call of getTopologyStatus.
+func (mr *MockstatusHelperAPIMockRecorder) getTopologyStatus(ctx, nfdInstance any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getTopologyNotAvailableConditions", reflect.TypeOf((*MockstatusHelperAPI)(nil).getTopologyNotAvailableConditions), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getTopologyStatus", reflect.TypeOf((*MockstatusHelperAPI)(nil).getTopologyStatus), ctx, nfdInstance)
 }
 
-// getWorkerNotAvailableConditions mocks base method.
-func (m *MockstatusHelperAPI) getWorkerNotAvailableConditions(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v10.Condition {
+// getWorkerConfigCondition mocks base method.
+func (m *MockstatusHelperAPI) getWorkerConfigCondition(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) v10.Condition {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "getWorkerNotAvailableConditions", ctx, nfdInstance)
-	ret0, _ := ret[0].([]v10.Condition)
+	ret := m.ctrl.Call(m, "getWorkerConfigCondition", ctx, nfdInstance)
+	ret0, _ := ret[0].(v10.Condition)
+	return ret0
+}
+
+// getWorkerConfigCondition indicates an expected call of getWorkerConfigCondition.
+func (mr *MockstatusHelperAPIMockRecorder) getWorkerConfigCondition(ctx, nfdInstance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getWorkerConfigCondition", reflect.TypeOf((*MockstatusHelperAPI)(nil).getWorkerConfigCondition), ctx, nfdInstance)
+}
+
+// getWorkerStatuses mocks base method.
+func (m *MockstatusHelperAPI) getWorkerStatuses(ctx context.Context, nfdInstance *v1.NodeFeatureDiscovery) []v1.ComponentStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "getWorkerStatuses", ctx, nfdInstance)
+	ret0, _ := ret[0].([]v1.ComponentStatus)
 	return ret0
 }
 
-// getWorkerNotAvailableConditions indicates an expected call of getWorkerNotAvailableConditions.
-func (mr *MockstatusHelperAPIMockRecorder) getWorkerNotAvailableConditions(ctx, nfdInstance any) *gomock.Call {
+// getWorkerStatuses indicates an expected call of getWorkerStatuses.
+func (mr *MockstatusHelperAPIMockRecorder) getWorkerStatuses(ctx, nfdInstance any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getWorkerNotAvailableConditions", reflect.TypeOf((*MockstatusHelperAPI)(nil).getWorkerNotAvailableConditions), ctx, nfdInstance)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "getWorkerStatuses", reflect.TypeOf((*MockstatusHelperAPI)(nil).getWorkerStatuses), ctx, nfdInstance)
 }
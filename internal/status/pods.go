@@ -0,0 +1,270 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+const (
+	// maxPodFailureNodes is the maximum number of nodes listed per reason
+	// of pod failure
+	maxPodFailureNodes = 5
+
+	// maxPodFailureMessageLength bounds the example message of each reason
+	// of pod failure
+	maxPodFailureMessageLength = 256
+
+	// failedCreateReason is the reason of the events of the controllers
+	// failing to create pods, e.g. because admission rejects them
+	failedCreateReason = "FailedCreate"
+)
+
+var (
+	// podNamePattern matches the name of the pod the kubelet adds to some
+	// messages, e.g. "pod=nfd-worker-x2v4k_openshift-nfd(uid)"
+	podNamePattern = regexp.MustCompile(`\s*pod=\S+`)
+
+	// backOffDelayPattern matches the back-off delay of the kubelet
+	// messages, which grows on every restart
+	backOffDelayPattern = regexp.MustCompile(`(?i)(back-off) [0-9][0-9a-z.]*`)
+)
+
+//go:generate mockgen -source=pods.go -package=status -destination=mock_pods.go podDiagnosticsAPI
+
+type podDiagnosticsAPI interface {
+	getDaemonSetPodFailures(ctx context.Context, ds *appsv1.DaemonSet) []nfdv1.PodFailure
+	getDeploymentPodFailures(ctx context.Context, dep *appsv1.Deployment) []nfdv1.PodFailure
+}
+
+type podDiagnostics struct {
+	client client.Client
+}
+
+func newPodDiagnosticsAPI(client client.Client) podDiagnosticsAPI {
+	return &podDiagnostics{
+		client: client,
+	}
+}
+
+// getDaemonSetPodFailures returns why the pods of the DaemonSet fail. Pods
+// rejected at admission are only reported by the events of the DaemonSet,
+// which are kept for a while after the failure is fixed, so they are only
+// looked at while some pods are not created
+func (pd *podDiagnostics) getDaemonSetPodFailures(ctx context.Context, ds *appsv1.DaemonSet) []nfdv1.PodFailure {
+	failures := podFailures{}
+	if ds.Status.CurrentNumberScheduled < ds.Status.DesiredNumberScheduled {
+		if message := pd.getFailedCreateMessage(ctx, ds.Namespace, "DaemonSet", ds.Name); message != "" {
+			failures.add(nfdv1.PodAdmissionFailed, "", message)
+		}
+	}
+	pd.addPodFailures(ctx, ds.Namespace, ds.Spec.Selector, failures)
+	return failures.list()
+}
+
+// getDeploymentPodFailures returns why the pods of the Deployment fail. Pods
+// rejected at admission are reported by the ReplicaFailure condition
+func (pd *podDiagnostics) getDeploymentPodFailures(ctx context.Context, dep *appsv1.Deployment) []nfdv1.PodFailure {
+	failures := podFailures{}
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue {
+			failures.add(nfdv1.PodAdmissionFailed, "", condition.Message)
+		}
+	}
+	pd.addPodFailures(ctx, dep.Namespace, dep.Spec.Selector, failures)
+	return failures.list()
+}
+
+// addPodFailures adds the failures of the pods matching the selector. Errors
+// are only logged, as the diagnostics must not prevent the status to be
+// reported
+func (pd *podDiagnostics) addPodFailures(ctx context.Context, namespace string, labelSelector *metav1.LabelSelector, failures podFailures) {
+	logger := ctrl.LoggerFrom(ctx)
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		logger.Error(err, "invalid pod selector", "namespace", namespace)
+		return
+	}
+	podList := corev1.PodList{}
+	if err := pd.client.List(ctx, &podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		logger.Error(err, "failed to list pods", "namespace", namespace, "selector", selector.String())
+		return
+	}
+	// the message of a reason is the one of its first pod, which must not
+	// depend on the order of the list to keep the status stable
+	sort.Slice(podList.Items, func(i, j int) bool {
+		return podList.Items[i].Name < podList.Items[j].Name
+	})
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if reason, message := getPodFailure(pod); reason != "" {
+			failures.add(reason, pod.Spec.NodeName, message)
+		}
+	}
+}
+
+// getFailedCreateMessage returns the message of the latest event reporting
+// that the controller of the pods could not create them. Events are not
+// cached, the field selector is applied by the API server
+func (pd *podDiagnostics) getFailedCreateMessage(ctx context.Context, namespace, kind, name string) string {
+	eventList := corev1.EventList{}
+	err := pd.client.List(ctx, &eventList, client.InNamespace(namespace), client.MatchingFields{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+		"reason":              failedCreateReason,
+	})
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list events", "namespace", namespace, "kind", kind, "name", name)
+		return ""
+	}
+	var latest *corev1.Event
+	for i := range eventList.Items {
+		event := &eventList.Items[i]
+		if event.Type != corev1.EventTypeWarning || event.Reason != failedCreateReason ||
+			event.InvolvedObject.Kind != kind || event.InvolvedObject.Name != name {
+			continue
+		}
+		if latest == nil || getEventTime(latest).Before(getEventTime(event)) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.Message
+}
+
+// getEventTime returns when the event was last seen. Events of the
+// events.k8s.io API set EventTime and the last occurrence of a series instead
+// of LastTimestamp
+func getEventTime(event *corev1.Event) time.Time {
+	last := event.LastTimestamp.Time
+	if event.EventTime.Time.After(last) {
+		last = event.EventTime.Time
+	}
+	if event.Series != nil && event.Series.LastObservedTime.Time.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	return last
+}
+
+// getPodFailure returns why the pod fails, if it does. A container killed for
+// exceeding its memory limit is reported as OOMKilled even when it is now
+// backing off
+func getPodFailure(pod *corev1.Pod) (nfdv1.PodFailureReason, string) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == corev1.PodReasonUnschedulable {
+			return nfdv1.PodUnschedulable, condition.Message
+		}
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, containerStatus := range statuses {
+		lastStates := []*corev1.ContainerStateTerminated{containerStatus.State.Terminated, containerStatus.LastTerminationState.Terminated}
+		for _, terminated := range lastStates {
+			if terminated != nil && terminated.Reason == "OOMKilled" {
+				return nfdv1.PodOOMKilled, fmt.Sprintf("container %s was killed for exceeding its memory limit", containerStatus.Name)
+			}
+		}
+	}
+	for _, containerStatus := range statuses {
+		waiting := containerStatus.State.Waiting
+		if waiting == nil {
+			continue
+		}
+		switch waiting.Reason {
+		case "CrashLoopBackOff":
+			return nfdv1.PodCrashLoopBackOff, waiting.Message
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			return nfdv1.PodImagePullBackOff, waiting.Message
+		}
+	}
+	return "", ""
+}
+
+// podFailures accumulates the failures of the pods of a component per reason
+type podFailures map[nfdv1.PodFailureReason]*nfdv1.PodFailure
+
+func (f podFailures) add(reason nfdv1.PodFailureReason, nodeName, message string) {
+	failure, ok := f[reason]
+	if !ok {
+		message = TruncateMessage(normalizePodFailureMessage(message), maxPodFailureMessageLength)
+		failure = &nfdv1.PodFailure{Reason: reason, Message: message}
+		f[reason] = failure
+	}
+	failure.Count++
+	if nodeName != "" {
+		failure.Nodes = append(failure.Nodes, nodeName)
+	}
+}
+
+// normalizePodFailureMessage strips the parts of the message that are specific
+// to a pod or change on every restart, so that the status is not patched when
+// only those change
+func normalizePodFailureMessage(message string) string {
+	message = podNamePattern.ReplaceAllString(message, "")
+	return backOffDelayPattern.ReplaceAllString(message, "$1")
+}
+
+// list returns the failures sorted by reason, each with the first nodes in
+// alphabetical order, so that the status does not change with the order of
+// the pods
+func (f podFailures) list() []nfdv1.PodFailure {
+	if len(f) == 0 {
+		return nil
+	}
+	failures := make([]nfdv1.PodFailure, 0, len(f))
+	for _, failure := range f {
+		sort.Strings(failure.Nodes)
+		if len(failure.Nodes) > maxPodFailureNodes {
+			failure.Nodes = failure.Nodes[:maxPodFailureNodes]
+		}
+		failures = append(failures, *failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Reason < failures[j].Reason
+	})
+	return failures
+}
+
+// formatPodFailures summarizes the failures for the condition messages, e.g.
+// "CrashLoopBackOff: 3 pods (worker-0, worker-1, worker-2)"
+func formatPodFailures(failures []nfdv1.PodFailure) string {
+	summaries := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		summary := fmt.Sprintf("%s: %d pods", failure.Reason, failure.Count)
+		if len(failure.Nodes) > 0 {
+			summary += fmt.Sprintf(" (%s)", strings.Join(failure.Nodes, ", "))
+		}
+		summaries = append(summaries, summary)
+	}
+	return strings.Join(summaries, ", ")
+}
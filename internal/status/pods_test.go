@@ -0,0 +1,333 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+var _ = Describe("getDaemonSetPodFailures", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		pd   podDiagnosticsAPI
+		ds   *appsv1.DaemonSet
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		pd = newPodDiagnosticsAPI(clnt)
+		ds = &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-worker", Namespace: "test-namespace"},
+			Spec: appsv1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nfd-worker"}},
+			},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, CurrentNumberScheduled: 3},
+		}
+	})
+
+	ctx := context.Background()
+
+	listPods := func(pods ...corev1.Pod) func(_ interface{}, podList *corev1.PodList, _ ...ctrlclient.ListOption) error {
+		return func(_ interface{}, podList *corev1.PodList, _ ...ctrlclient.ListOption) error {
+			podList.Items = pods
+			return nil
+		}
+	}
+
+	It("pods failing for several reasons", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(listPods(
+			crashingPod("worker-2"),
+			crashingPod("worker-0"),
+			imagePullPod("worker-1"),
+			corev1.Pod{Spec: corev1.PodSpec{NodeName: "worker-3"}},
+		))
+
+		res := pd.getDaemonSetPodFailures(ctx, ds)
+		Expect(res).To(Equal([]nfdv1.PodFailure{
+			{Reason: nfdv1.PodCrashLoopBackOff, Count: 2, Nodes: []string{"worker-0", "worker-2"}, Message: "back-off restarting failed container=worker"},
+			{Reason: nfdv1.PodImagePullBackOff, Count: 1, Nodes: []string{"worker-1"}, Message: "Back-off pulling image"},
+		}))
+	})
+
+	It("pods rejected at admission are reported by the events of the DaemonSet", func() {
+		ds.Status.CurrentNumberScheduled = 0
+		now := time.Now()
+		fields := ctrlclient.MatchingFields{
+			"involvedObject.kind": "DaemonSet",
+			"involvedObject.name": "nfd-worker",
+			"reason":              failedCreateReason,
+		}
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), ctrlclient.InNamespace("test-namespace"), fields).DoAndReturn(
+				func(_ interface{}, eventList *corev1.EventList, _ ...ctrlclient.ListOption) error {
+					eventList.Items = []corev1.Event{
+						failedCreateEvent("nfd-worker", "old error", now.Add(-time.Hour)),
+						failedCreateEvent("nfd-worker", "forbidden: unable to validate against any security context constraint", now),
+					}
+					return nil
+				}),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(listPods()),
+		)
+
+		res := pd.getDaemonSetPodFailures(ctx, ds)
+		Expect(res).To(Equal([]nfdv1.PodFailure{
+			{Reason: nfdv1.PodAdmissionFailed, Count: 1, Message: "forbidden: unable to validate against any security context constraint"},
+		}))
+	})
+
+	It("the latest event is found from the time of the events of the events.k8s.io API", func() {
+		ds.Status.CurrentNumberScheduled = 0
+		now := time.Now()
+		series := failedCreateEvent("nfd-worker", "latest occurrence of a series", time.Time{})
+		series.EventTime = metav1.NewMicroTime(now.Add(-time.Hour))
+		series.Series = &corev1.EventSeries{Count: 3, LastObservedTime: metav1.NewMicroTime(now)}
+		single := failedCreateEvent("nfd-worker", "single event", time.Time{})
+		single.EventTime = metav1.NewMicroTime(now.Add(-time.Minute))
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ interface{}, eventList *corev1.EventList, _ ...ctrlclient.ListOption) error {
+					eventList.Items = []corev1.Event{
+						single,
+						series,
+						failedCreateEvent("nfd-worker", "old error", now.Add(-2*time.Hour)),
+					}
+					return nil
+				}),
+			clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(listPods()),
+		)
+
+		res := pd.getDaemonSetPodFailures(ctx, ds)
+		Expect(res).To(Equal([]nfdv1.PodFailure{
+			{Reason: nfdv1.PodAdmissionFailed, Count: 1, Message: "latest occurrence of a series"},
+		}))
+	})
+
+	It("the message does not depend on the order of the pods", func() {
+		imagePull := imagePullPod("worker-1")
+		imagePull.Name = "nfd-worker-worker-1"
+		otherImagePull := imagePullPod("worker-3")
+		otherImagePull.Name = "nfd-worker-worker-3"
+		otherImagePull.Status.ContainerStatuses[0].State.Waiting.Message = "Back-off pulling image \"other\""
+		pods := []corev1.Pod{crashingPodWithDelay("worker-0", "10s"), imagePull, crashingPodWithDelay("worker-2", "2m40s"), otherImagePull}
+		expected := []nfdv1.PodFailure{
+			{Reason: nfdv1.PodCrashLoopBackOff, Count: 2, Nodes: []string{"worker-0", "worker-2"}, Message: "back-off restarting failed container=worker"},
+			{Reason: nfdv1.PodImagePullBackOff, Count: 2, Nodes: []string{"worker-1", "worker-3"}, Message: "Back-off pulling image"},
+		}
+
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(listPods(pods...))
+		Expect(pd.getDaemonSetPodFailures(ctx, ds)).To(Equal(expected))
+
+		slices.Reverse(pods)
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(listPods(pods...))
+		Expect(pd.getDaemonSetPodFailures(ctx, ds)).To(Equal(expected))
+	})
+
+	It("failure to list the pods", func() {
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		res := pd.getDaemonSetPodFailures(ctx, ds)
+		Expect(res).To(BeNil())
+	})
+})
+
+var _ = Describe("getDeploymentPodFailures", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		pd   podDiagnosticsAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		pd = newPodDiagnosticsAPI(clnt)
+	})
+
+	ctx := context.Background()
+
+	It("pods rejected at admission are reported by the ReplicaFailure condition", func() {
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-master", Namespace: "test-namespace"},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nfd-master"}},
+			},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentReplicaFailure,
+						Status:  corev1.ConditionTrue,
+						Reason:  "FailedCreate",
+						Message: "pods \"nfd-master-1\" is forbidden",
+					},
+				},
+			},
+		}
+		clnt.EXPECT().List(ctx, gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, podList *corev1.PodList, _ ...ctrlclient.ListOption) error {
+				podList.Items = []corev1.Pod{unschedulablePod()}
+				return nil
+			})
+
+		res := pd.getDeploymentPodFailures(ctx, dep)
+		Expect(res).To(Equal([]nfdv1.PodFailure{
+			{Reason: nfdv1.PodAdmissionFailed, Count: 1, Message: "pods \"nfd-master-1\" is forbidden"},
+			{Reason: nfdv1.PodUnschedulable, Count: 1, Message: "0/3 nodes are available"},
+		}))
+	})
+})
+
+var _ = Describe("getPodFailure", func() {
+	DescribeTable("reasons of the pod failures", func(pod corev1.Pod, expectedReason nfdv1.PodFailureReason, expectedMessage string) {
+		reason, message := getPodFailure(&pod)
+		Expect(reason).To(Equal(expectedReason))
+		Expect(message).To(Equal(expectedMessage))
+	},
+		Entry("running pod", corev1.Pod{}, nfdv1.PodFailureReason(""), ""),
+		Entry("crash loop", crashingPod("worker-0"), nfdv1.PodCrashLoopBackOff,
+			"back-off 5m0s restarting failed container=worker pod=nfd-worker-worker-0_test-namespace(0a1b2c)"),
+		Entry("image pull", imagePullPod("worker-0"), nfdv1.PodImagePullBackOff, "Back-off pulling image"),
+		Entry("unschedulable", unschedulablePod(), nfdv1.PodUnschedulable, "0/3 nodes are available"),
+		Entry("OOM killed container backing off", corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:                 "worker",
+						State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+					},
+				},
+			},
+		}, nfdv1.PodOOMKilled, "container worker was killed for exceeding its memory limit"),
+		Entry("failing init container", corev1.Pod{
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "init", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "InvalidImageName"}}},
+				},
+			},
+		}, nfdv1.PodImagePullBackOff, ""),
+	)
+})
+
+var _ = Describe("podFailures", func() {
+	It("nodes and messages are bounded", func() {
+		failures := podFailures{}
+		for i := 6; i >= 0; i-- {
+			failures.add(nfdv1.PodCrashLoopBackOff, fmt.Sprintf("worker-%d", i), strings.Repeat("x", 300))
+		}
+
+		res := failures.list()
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].Count).To(Equal(int32(7)))
+		Expect(res[0].Nodes).To(Equal([]string{"worker-0", "worker-1", "worker-2", "worker-3", "worker-4"}))
+		Expect(res[0].Message).To(Equal(strings.Repeat("x", maxPodFailureMessageLength) + "..."))
+		Expect(formatPodFailures(res)).To(Equal("CrashLoopBackOff: 7 pods (worker-0, worker-1, worker-2, worker-3, worker-4)"))
+	})
+
+	It("messages are truncated on a rune boundary", func() {
+		failures := podFailures{}
+		failures.add(nfdv1.PodAdmissionFailed, "", strings.Repeat("x", maxPodFailureMessageLength-1)+"é")
+
+		Expect(failures.list()[0].Message).To(Equal(strings.Repeat("x", maxPodFailureMessageLength-1) + "..."))
+	})
+
+	It("no failures", func() {
+		Expect(podFailures{}.list()).To(BeNil())
+	})
+})
+
+func crashingPod(nodeName string) corev1.Pod {
+	return crashingPodWithDelay(nodeName, "5m0s")
+}
+
+func crashingPodWithDelay(nodeName string, delay string) corev1.Pod {
+	name := "nfd-worker-" + nodeName
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-namespace"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "worker",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "CrashLoopBackOff",
+							Message: fmt.Sprintf("back-off %s restarting failed container=worker pod=%s_test-namespace(0a1b2c)", delay, name),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func imagePullPod(nodeName string) corev1.Pod {
+	return corev1.Pod{
+		Spec: corev1.PodSpec{NodeName: nodeName},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "worker",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func unschedulablePod() corev1.Pod {
+	return corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{
+					Type:    corev1.PodScheduled,
+					Status:  corev1.ConditionFalse,
+					Reason:  corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available",
+				},
+			},
+		},
+	}
+}
+
+func failedCreateEvent(name, message string, lastTimestamp time.Time) corev1.Event {
+	return corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "DaemonSet", Name: name},
+		Type:           corev1.EventTypeWarning,
+		Reason:         failedCreateReason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(lastTimestamp),
+	}
+}
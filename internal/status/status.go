@@ -19,22 +19,25 @@ package status
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/configmap"
 	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
 	"github.com/openshift/cluster-nfd-operator/internal/deployment"
 )
 
 const (
-	conditionStatusProgressing = "progressing"
-	conditionStatusDegraded    = "degrading"
-	conditionStatusAvailable   = "available"
-
 	conditionFailedGettingNFDWorkerDaemonSet = "FailedGettingNFDWorkerDaemonSet"
 	conditionNFDWorkerDaemonSetDegraded      = "NFDWorkerDaemonSetDegraded"
 	conditionNFDWorkerDaemonSetProgressing   = "NFDWorkerDaemonSetProgressing"
@@ -49,10 +52,18 @@ const (
 
 	conditionFailedGettingNFDGCDeployment = "FailedGettingNFDGCDeployment"
 	conditionNFDGCDeploymentDegraded      = "NFDGCDegraded"
-	conditionNFDGCDeploymentProgressing   = "NFDGCDeploymentProgressing"
+	conditionNFDGCDeploymentProgressing   = "NFDGCProgressing"
 
 	conditionIsFalseReason = "ConditionNotBeingMetCurrently"
 
+	// progressDeadlineExceededReason is the reason of the Progressing
+	// condition of a deployment whose rollout is stuck
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+	reasonComponentsDegraded    = "ComponentsDegraded"
+	reasonComponentsProgressing = "ComponentsProgressing"
+	reasonComponentsUnavailable = "ComponentsUnavailable"
+
 	// ConditionAvailable indicates that the resources maintained by the operator,
 	// is functional and available in the cluster.
 	conditionAvailable string = "Available"
@@ -81,59 +92,156 @@ const (
 
 	reasonOperandImagePinned    = "OperandImageSetExplicitly"
 	reasonOperandImageNotPinned = "OperandImageManagedByOperator"
+
+	// ConditionWorkerConfigAvailable indicates whether the nfd-worker configuration is available.
+	// It is only False when a workerConfig.configMapRef references a ConfigMap, or a key of it,
+	// that cannot be found, in which case the worker pods cannot start.
+	ConditionWorkerConfigAvailable string = "WorkerConfigAvailable"
+
+	reasonWorkerConfigManagedByOperator = "WorkerConfigManagedByOperator"
+	reasonWorkerConfigMapFound          = "WorkerConfigMapFound"
+	reasonWorkerConfigMapNotFound       = "WorkerConfigMapNotFound"
+	reasonWorkerConfigMapKeyNotFound    = "WorkerConfigMapKeyNotFound"
+	reasonFailedGettingWorkerConfigMap  = "FailedGettingWorkerConfigMap"
+
+	// ConditionManaged indicates whether the operator reconciles the operands,
+	// i.e. whether spec.managementState is Managed
+	ConditionManaged string = "Managed"
+
+	reasonManaged   = "Managed"
+	reasonUnmanaged = "Unmanaged"
+	reasonRemoved   = "Removed"
 )
 
 //go:generate mockgen -source=status.go -package=status -destination=mock_status.go StatusAPI
 
 type StatusAPI interface {
-	GetConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition
+	GetComponents(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []nfdv1.ComponentStatus
+	GetConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery, components []nfdv1.ComponentStatus) []metav1.Condition
 	AreConditionsEqual(prevConditions, newConditions []metav1.Condition) bool
+	GetMasterLeader(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) string
 }
 
 type status struct {
-	helper statusHelperAPI
+	helper        statusHelperAPI
+	deploymentAPI deployment.DeploymentAPI
 }
 
-func NewStatusAPI(deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI) StatusAPI {
-	helper := newStatusHelperAPI(deploymentAPI, daemonsetAPI)
+func NewStatusAPI(client client.Client, deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI,
+	configmapAPI configmap.ConfigMapAPI) StatusAPI {
+	helper := newStatusHelperAPI(deploymentAPI, daemonsetAPI, configmapAPI, newPodDiagnosticsAPI(client))
 	return &status{
-		helper: helper,
+		helper:        helper,
+		deploymentAPI: deploymentAPI,
 	}
 }
 
-func (s *status) GetConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	conditions := s.getComponentConditions(ctx, nfdInstance)
+// GetComponents returns the status of the workload of every component. The
+// workloads of Removed instances are deleted on purpose and are not reported
+func (s *status) GetComponents(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []nfdv1.ComponentStatus {
+	if nfdInstance.GetManagementState() == nfdv1.Removed {
+		return nil
+	}
+	components := s.helper.getWorkerStatuses(ctx, nfdInstance)
+	components = append(components, s.helper.getMasterStatus(ctx, nfdInstance), s.helper.getGCStatus(ctx, nfdInstance))
+	if nfdInstance.Spec.TopologyUpdater {
+		components = append(components, s.helper.getTopologyStatus(ctx, nfdInstance))
+	}
+	return components
+}
+
+// GetConditions returns the conditions of the instance, the Available,
+// Progressing and Degraded ones being aggregated from the status of all the
+// components returned by GetComponents
+func (s *status) GetConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	components []nfdv1.ComponentStatus) []metav1.Condition {
+	var conditions []metav1.Condition
+	if nfdInstance.GetManagementState() == nfdv1.Removed {
+		// the operands are deleted on purpose, they are not missing
+		conditions = getRemovedConditions()
+	} else {
+		conditions = getComponentConditions(components)
+	}
 	// OperandImagePinned is computed independently of the other conditions above (rather than folded
 	// into e.g. Upgradeable) so that it stays visible even when the CR is already Degraded/Progressing
 	// for an unrelated reason - which is exactly the case we most want to catch.
-	return append(conditions, getOperandImagePinnedCondition(nfdInstance))
+	conditions = append(conditions, getOperandImagePinnedCondition(nfdInstance))
+	// WorkerConfigAvailable is independent as well, so that a missing user-owned ConfigMap is
+	// pointed at directly instead of only surfacing as a progressing worker DaemonSet.
+	conditions = append(conditions, s.helper.getWorkerConfigCondition(ctx, nfdInstance))
+	conditions = append(conditions, getManagedCondition(nfdInstance))
+	for i := range conditions {
+		conditions[i].ObservedGeneration = nfdInstance.Generation
+	}
+	return conditions
 }
 
-func (s *status) getComponentConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	// get worker daemonset conditions
-	nonAvailableConditions := s.helper.getWorkerNotAvailableConditions(ctx, nfdInstance)
-	if nonAvailableConditions != nil {
-		return nonAvailableConditions
+// getComponentConditions aggregates the status of the components: every
+// component that is not available is listed in the messages, and a single
+// failing component keeps its own reason
+func getComponentConditions(components []nfdv1.ComponentStatus) []metav1.Condition {
+	var degraded, progressing []nfdv1.ComponentStatus
+	for _, component := range components {
+		switch component.State {
+		case nfdv1.ComponentDegraded:
+			degraded = append(degraded, component)
+		case nfdv1.ComponentProgressing:
+			progressing = append(progressing, component)
+		}
 	}
-	// get master deployment conditions
-	nonAvailableConditions = s.helper.getMasterNotAvailableConditions(ctx, nfdInstance)
-	if nonAvailableConditions != nil {
-		return nonAvailableConditions
+	if len(degraded) == 0 && len(progressing) == 0 {
+		return getAvailableConditions()
 	}
-	// get GC deployment conditions
-	nonAvailableConditions = s.helper.getGCNotAvailableConditions(ctx, nfdInstance)
-	if nonAvailableConditions != nil {
-		return nonAvailableConditions
+
+	unavailable := make([]string, 0, len(degraded)+len(progressing))
+	for _, component := range append(append([]nfdv1.ComponentStatus{}, degraded...), progressing...) {
+		unavailable = append(unavailable, component.Name)
 	}
-	// get topology, if needed
-	if nfdInstance.Spec.TopologyUpdater {
-		nonAvailableConditions := s.helper.getTopologyNotAvailableConditions(ctx, nfdInstance)
-		if nonAvailableConditions != nil {
-			return nonAvailableConditions
-		}
+	now := metav1.Time{Time: time.Now()}
+	return []metav1.Condition{
+		{
+			Type:               conditionAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonComponentsUnavailable,
+			Message:            "components not available: " + strings.Join(unavailable, ", "),
+			LastTransitionTime: now,
+		},
+		{
+			Type:               conditionUpgradeable,
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionIsFalseReason,
+			LastTransitionTime: now,
+		},
+		getAggregatedCondition(conditionProgressing, progressing, reasonComponentsProgressing, now),
+		getAggregatedCondition(conditionDegraded, degraded, reasonComponentsDegraded, now),
 	}
+}
 
-	return getAvailableConditions()
+// getAggregatedCondition returns a condition that is True when components
+// are in the matching state, with a message listing all of them
+func getAggregatedCondition(conditionType string, components []nfdv1.ComponentStatus, reason string, now metav1.Time) metav1.Condition {
+	if len(components) == 0 {
+		return metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionIsFalseReason,
+			LastTransitionTime: now,
+		}
+	}
+	if len(components) == 1 {
+		reason = components[0].Reason
+	}
+	messages := make([]string, 0, len(components))
+	for _, component := range components {
+		messages = append(messages, fmt.Sprintf("%s %s: %s", component.Kind, component.Name, component.Message))
+	}
+	return metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            strings.Join(messages, "; "),
+		LastTransitionTime: now,
+	}
 }
 
 // getOperandImagePinnedCondition reports whether spec.operand.image is explicitly set. Setting it
@@ -165,6 +273,49 @@ func getOperandImagePinnedCondition(nfdInstance *nfdv1.NodeFeatureDiscovery) met
 	}
 }
 
+// getManagedCondition reports the management state of the instance. When it
+// is Unmanaged, the component conditions still reflect the operands, which
+// may have been edited by hand
+func getManagedCondition(nfdInstance *nfdv1.NodeFeatureDiscovery) metav1.Condition {
+	now := metav1.Time{Time: time.Now()}
+	switch nfdInstance.GetManagementState() {
+	case nfdv1.Unmanaged:
+		return metav1.Condition{
+			Type:               ConditionManaged,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonUnmanaged,
+			Message:            "spec.managementState is Unmanaged, the operands are not reconciled",
+			LastTransitionTime: now,
+		}
+	case nfdv1.Removed:
+		return metav1.Condition{
+			Type:               ConditionManaged,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonRemoved,
+			Message:            "spec.managementState is Removed, the operands are deleted",
+			LastTransitionTime: now,
+		}
+	}
+	return metav1.Condition{
+		Type:               ConditionManaged,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonManaged,
+		LastTransitionTime: now,
+	}
+}
+
+// GetMasterLeader returns the nfd-master replica currently holding the leader
+// election lease. An error getting the lease is reported as no leader, as the
+// leader is informative and must not prevent the conditions to be updated
+func (s *status) GetMasterLeader(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) string {
+	leader, err := s.deploymentAPI.GetMasterLeader(ctx, nfdInstance)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to get the nfd-master leader")
+		return ""
+	}
+	return leader
+}
+
 func (s *status) AreConditionsEqual(prevConditions, newConditions []metav1.Condition) bool {
 	for _, newCondition := range newConditions {
 		oldCondition := meta.FindStatusCondition(prevConditions, newCondition.Type)
@@ -174,7 +325,8 @@ func (s *status) AreConditionsEqual(prevConditions, newConditions []metav1.Condi
 		// Ignore timestamps
 		if oldCondition.Status != newCondition.Status ||
 			oldCondition.Reason != newCondition.Reason ||
-			oldCondition.Message != newCondition.Message {
+			oldCondition.Message != newCondition.Message ||
+			oldCondition.ObservedGeneration != newCondition.ObservedGeneration {
 			return false
 		}
 	}
@@ -184,119 +336,248 @@ func (s *status) AreConditionsEqual(prevConditions, newConditions []metav1.Condi
 //go:generate mockgen -source=status.go -package=status -destination=mock_status.go statusHelperAPI
 
 type statusHelperAPI interface {
-	getWorkerNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition
-	getTopologyNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition
-	getMasterNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition
-	getGCNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition
+	getWorkerStatuses(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []nfdv1.ComponentStatus
+	getTopologyStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus
+	getMasterStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus
+	getGCStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus
+	getWorkerConfigCondition(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) metav1.Condition
 }
 
 type statusHelper struct {
-	deploymentAPI deployment.DeploymentAPI
-	daemonsetAPI  daemonset.DaemonsetAPI
+	deploymentAPI  deployment.DeploymentAPI
+	daemonsetAPI   daemonset.DaemonsetAPI
+	configmapAPI   configmap.ConfigMapAPI
+	podDiagnostics podDiagnosticsAPI
 }
 
-func newStatusHelperAPI(deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI) statusHelperAPI {
+func newStatusHelperAPI(deploymentAPI deployment.DeploymentAPI, daemonsetAPI daemonset.DaemonsetAPI, configmapAPI configmap.ConfigMapAPI,
+	podDiagnostics podDiagnosticsAPI) statusHelperAPI {
 	return &statusHelper{
-		deploymentAPI: deploymentAPI,
-		daemonsetAPI:  daemonsetAPI,
+		deploymentAPI:  deploymentAPI,
+		daemonsetAPI:   daemonsetAPI,
+		configmapAPI:   configmapAPI,
+		podDiagnostics: podDiagnostics,
 	}
 }
 
-func (sh *statusHelper) getWorkerNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	return sh.getDaemonSetNotAvailableConditions(ctx,
-		nfdInstance.Namespace,
-		"nfd-worker",
-		conditionFailedGettingNFDWorkerDaemonSet,
-		conditionNFDWorkerDaemonSetDegraded,
-		conditionNFDWorkerDaemonSetProgressing)
+func (sh *statusHelper) getWorkerStatuses(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []nfdv1.ComponentStatus {
+	// every worker profile has its own DaemonSet
+	profiles := nfdInstance.GetWorkerProfiles()
+	statuses := make([]nfdv1.ComponentStatus, 0, len(profiles))
+	for _, profile := range profiles {
+		statuses = append(statuses, sh.getDaemonSetStatus(ctx,
+			nfdInstance.Namespace,
+			nfdInstance.WorkerProfileName(profile.Name),
+			conditionFailedGettingNFDWorkerDaemonSet,
+			conditionNFDWorkerDaemonSetDegraded,
+			conditionNFDWorkerDaemonSetProgressing))
+	}
+	return statuses
 }
 
-func (sh *statusHelper) getTopologyNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	return sh.getDaemonSetNotAvailableConditions(ctx,
+func (sh *statusHelper) getTopologyStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus {
+	return sh.getDaemonSetStatus(ctx,
 		nfdInstance.Namespace,
-		"nfd-topology-updater",
+		nfdInstance.ComponentName("nfd-topology-updater"),
 		conditionFailedGettingNFDTopologyDaemonSet,
 		conditionNFDTopologyDaemonSetDegraded,
 		conditionNFDTopologyDaemonSetProgressing)
 }
 
-func (sh *statusHelper) getDaemonSetNotAvailableConditions(ctx context.Context,
+func (sh *statusHelper) getDaemonSetStatus(ctx context.Context,
 	dsNamespace,
 	dsName,
 	failedToGetDSReason,
 	dsDegradedReason,
-	dsProgressingReason string) []metav1.Condition {
+	dsProgressingReason string) nfdv1.ComponentStatus {
 
+	componentStatus := nfdv1.ComponentStatus{Name: dsName, Kind: "DaemonSet"}
 	ds, err := sh.daemonsetAPI.GetDaemonSet(ctx, dsNamespace, dsName)
 	if err != nil {
-		return getDegradedConditions(failedToGetDSReason, err.Error())
+		componentStatus.State = nfdv1.ComponentDegraded
+		componentStatus.Reason = failedToGetDSReason
+		componentStatus.Message = err.Error()
+		return componentStatus
 	}
-	conditionsStatus, message := getDaemonSetConditions(ds)
-	if conditionsStatus == conditionStatusDegraded {
-		return getDegradedConditions(dsDegradedReason, message)
-	} else if conditionsStatus == conditionStatusProgressing {
-		return getProgressingConditions(dsProgressingReason, message)
+	componentStatus.Desired = ds.Status.DesiredNumberScheduled
+	componentStatus.Ready = ds.Status.NumberReady
+	componentStatus.Updated = ds.Status.UpdatedNumberScheduled
+	componentStatus.Image = getOperandImage(&ds.Spec.Template)
+	componentStatus.ObservedGeneration = ds.Status.ObservedGeneration
+
+	componentStatus.State, componentStatus.Message = getDaemonSetState(ds)
+	switch componentStatus.State {
+	case nfdv1.ComponentDegraded:
+		componentStatus.Reason = dsDegradedReason
+	case nfdv1.ComponentProgressing:
+		componentStatus.Reason = dsProgressingReason
+	default:
+		return componentStatus
 	}
-	return nil
+	setPodFailures(&componentStatus, sh.podDiagnostics.getDaemonSetPodFailures(ctx, ds))
+	return componentStatus
 }
 
-func (sh *statusHelper) getMasterNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	return sh.getDeploymentNotAvailableConditions(ctx,
+func (sh *statusHelper) getMasterStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus {
+	return sh.getDeploymentStatus(ctx,
 		nfdInstance.Namespace,
-		"nfd-master",
+		nfdInstance.ComponentName("nfd-master"),
 		conditionFailedGettingNFDMasterDeployment,
 		conditionNFDMasterDeploymentDegraded,
 		conditionNFDMasterDeploymentProgressing)
 }
 
-func (sh *statusHelper) getGCNotAvailableConditions(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) []metav1.Condition {
-	return sh.getDeploymentNotAvailableConditions(ctx,
+func (sh *statusHelper) getGCStatus(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) nfdv1.ComponentStatus {
+	return sh.getDeploymentStatus(ctx,
 		nfdInstance.Namespace,
-		"nfd-gc",
+		nfdInstance.ComponentName("nfd-gc"),
 		conditionFailedGettingNFDGCDeployment,
 		conditionNFDGCDeploymentDegraded,
 		conditionNFDGCDeploymentProgressing)
+}
 
+func (sh *statusHelper) getWorkerConfigCondition(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               ConditionWorkerConfigAvailable,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonWorkerConfigManagedByOperator,
+		LastTransitionTime: metav1.Time{Time: time.Now()},
+	}
+
+	// the first referenced ConfigMap that cannot be used is reported
+	for _, ref := range nfdInstance.WorkerConfigMapRefs() {
+		cm, err := sh.configmapAPI.GetConfigMap(ctx, nfdInstance.Namespace, ref.Name)
+		switch {
+		case k8serrors.IsNotFound(err):
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = reasonWorkerConfigMapNotFound
+			condition.Message = fmt.Sprintf("referenced worker ConfigMap %s/%s does not exist",
+				nfdInstance.Namespace, ref.Name)
+			return condition
+		case err != nil:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = reasonFailedGettingWorkerConfigMap
+			condition.Message = err.Error()
+			return condition
+		}
+		_, inData := cm.Data[ref.Key]
+		_, inBinaryData := cm.BinaryData[ref.Key]
+		if !inData && !inBinaryData {
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = reasonWorkerConfigMapKeyNotFound
+			condition.Message = fmt.Sprintf("key %q not found in referenced worker ConfigMap %s/%s",
+				ref.Key, nfdInstance.Namespace, ref.Name)
+			return condition
+		}
+		condition.Reason = reasonWorkerConfigMapFound
+	}
+	return condition
 }
 
-func (sh *statusHelper) getDeploymentNotAvailableConditions(ctx context.Context,
+func (sh *statusHelper) getDeploymentStatus(ctx context.Context,
 	deploymentNamespace,
 	deploymentName,
 	failedToGetDeploymentReason,
 	deploymentDegradedReason,
-	deploymentProgressingReason string) []metav1.Condition {
+	deploymentProgressingReason string) nfdv1.ComponentStatus {
 
+	componentStatus := nfdv1.ComponentStatus{Name: deploymentName, Kind: "Deployment"}
 	dep, err := sh.deploymentAPI.GetDeployment(ctx, deploymentNamespace, deploymentName)
 	if err != nil {
-		return getDegradedConditions(failedToGetDeploymentReason, err.Error())
+		componentStatus.State = nfdv1.ComponentDegraded
+		componentStatus.Reason = failedToGetDeploymentReason
+		componentStatus.Message = err.Error()
+		return componentStatus
+	}
+	componentStatus.Desired = 1
+	if dep.Spec.Replicas != nil {
+		componentStatus.Desired = *dep.Spec.Replicas
+	}
+	componentStatus.Ready = dep.Status.ReadyReplicas
+	componentStatus.Updated = dep.Status.UpdatedReplicas
+	componentStatus.Image = getOperandImage(&dep.Spec.Template)
+	componentStatus.ObservedGeneration = dep.Status.ObservedGeneration
+
+	componentStatus.State, componentStatus.Message = getDeploymentState(dep, componentStatus.Desired)
+	switch componentStatus.State {
+	case nfdv1.ComponentDegraded:
+		componentStatus.Reason = deploymentDegradedReason
+	case nfdv1.ComponentProgressing:
+		componentStatus.Reason = deploymentProgressingReason
+	default:
+		return componentStatus
+	}
+	setPodFailures(&componentStatus, sh.podDiagnostics.getDeploymentPodFailures(ctx, dep))
+	return componentStatus
+}
+
+// setPodFailures records why the pods of the component fail, and adds a
+// summary to its message so that the conditions tell it too
+func setPodFailures(componentStatus *nfdv1.ComponentStatus, failures []nfdv1.PodFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	componentStatus.PodFailures = failures
+	componentStatus.Message += ", pods failing: " + formatPodFailures(failures)
+}
+
+// TruncateMessage bounds the message to maxLength bytes followed by "...",
+// cutting it on a rune boundary so that it stays valid UTF-8
+func TruncateMessage(message string, maxLength int) string {
+	if len(message) <= maxLength {
+		return message
 	}
-	conditionsStatus, message := getDeploymentConditions(dep)
-	if conditionsStatus == conditionStatusDegraded {
-		return getDegradedConditions(deploymentDegradedReason, message)
-	} else if conditionsStatus == conditionStatusProgressing {
-		return getProgressingConditions(deploymentProgressingReason, message)
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
 	}
-	return nil
+	return message[:end] + "..."
 }
 
-func getDaemonSetConditions(ds *appsv1.DaemonSet) (string, string) {
+// getOperandImage returns the image of the operand container, which comes
+// before the sidecar containers
+func getOperandImage(template *corev1.PodTemplateSpec) string {
+	if len(template.Spec.Containers) == 0 {
+		return ""
+	}
+	return template.Spec.Containers[0].Image
+}
+
+// getDaemonSetState reports the daemonset as available when no node is
+// selected, e.g. for an empty pool of a worker profile, as degraded when none
+// of the selected nodes runs a pod, and as progressing while some of the pods
+// are not ready or not updated
+func getDaemonSetState(ds *appsv1.DaemonSet) (nfdv1.ComponentState, string) {
 	if ds.Status.DesiredNumberScheduled == 0 {
-		return conditionStatusDegraded, "number of desired nodes for scheduling is 0"
+		return nfdv1.ComponentAvailable, ""
 	}
 	if ds.Status.CurrentNumberScheduled == 0 {
-		return conditionStatusDegraded, "0 nodes have pods scheduled"
+		return nfdv1.ComponentDegraded, "0 nodes have pods scheduled"
 	}
-	if ds.Status.NumberReady == ds.Status.DesiredNumberScheduled {
-		return conditionStatusAvailable, ""
+	if ds.Status.NumberReady < ds.Status.DesiredNumberScheduled ||
+		ds.Status.UpdatedNumberScheduled < ds.Status.DesiredNumberScheduled {
+		return nfdv1.ComponentProgressing, "ds is progressing"
 	}
-	return conditionStatusProgressing, "ds is progressing"
+	return nfdv1.ComponentAvailable, ""
 }
 
-func getDeploymentConditions(dep *appsv1.Deployment) (string, string) {
+// getDeploymentState reports the deployment as degraded when none of its pods
+// is available or its rollout is stuck, and as progressing while some of the
+// desired pods are not ready or not updated
+func getDeploymentState(dep *appsv1.Deployment, desired int32) (nfdv1.ComponentState, string) {
 	if dep.Status.AvailableReplicas == 0 {
-		return conditionStatusDegraded, "number of available pods is 0"
+		return nfdv1.ComponentDegraded, "number of available pods is 0"
+	}
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == progressDeadlineExceededReason {
+			return nfdv1.ComponentDegraded, condition.Message
+		}
 	}
-	return conditionStatusAvailable, ""
+	if dep.Status.ReadyReplicas < desired || dep.Status.UpdatedReplicas < desired {
+		return nfdv1.ComponentProgressing, "deployment is progressing"
+	}
+	return nfdv1.ComponentAvailable, ""
 }
 
 // getAvailableConditions returns a list of Condition objects and marks
@@ -332,6 +613,39 @@ func getAvailableConditions() []metav1.Condition {
 	}
 }
 
+// getRemovedConditions returns the conditions of an instance whose operands
+// are removed: it is neither available nor degraded, and can be upgraded
+func getRemovedConditions() []metav1.Condition {
+	now := time.Now()
+	return []metav1.Condition{
+		{
+			Type:               conditionAvailable,
+			Status:             metav1.ConditionFalse,
+			Reason:             reasonRemoved,
+			Message:            "the operands are removed",
+			LastTransitionTime: metav1.Time{Time: now},
+		},
+		{
+			Type:               conditionUpgradeable,
+			Status:             metav1.ConditionTrue,
+			Reason:             "CanBeUpgraded",
+			LastTransitionTime: metav1.Time{Time: now},
+		},
+		{
+			Type:               conditionProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionIsFalseReason,
+			LastTransitionTime: metav1.Time{Time: now},
+		},
+		{
+			Type:               conditionDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             conditionIsFalseReason,
+			LastTransitionTime: metav1.Time{Time: now},
+		},
+	}
+}
+
 // getDegradedConditions returns a list of conditions.Condition objects and marks
 // every condition as FALSE except for conditions.ConditionDegraded so that the
 // reconciler can determine that the resource is degraded.
@@ -25,14 +25,73 @@ import (
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	meta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/configmap"
 	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
 	"github.com/openshift/cluster-nfd-operator/internal/deployment"
 )
 
+var _ = Describe("GetComponents", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockHelper *MockstatusHelperAPI
+		st         *status
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockHelper = NewMockstatusHelperAPI(ctrl)
+		st = &status{
+			helper: mockHelper,
+		}
+	})
+
+	ctx := context.Background()
+	worker := nfdv1.ComponentStatus{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded}
+	master := nfdv1.ComponentStatus{Name: "nfd-master", Kind: "Deployment", State: nfdv1.ComponentDegraded}
+	gc := nfdv1.ComponentStatus{Name: "nfd-gc", Kind: "Deployment", State: nfdv1.ComponentAvailable}
+	topology := nfdv1.ComponentStatus{Name: "nfd-topology-updater", Kind: "DaemonSet", State: nfdv1.ComponentProgressing}
+
+	It("every component is reported, even after a failing one", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				TopologyUpdater: true,
+			},
+		}
+		mockHelper.EXPECT().getWorkerStatuses(ctx, &nfdCR).Return([]nfdv1.ComponentStatus{worker})
+		mockHelper.EXPECT().getMasterStatus(ctx, &nfdCR).Return(master)
+		mockHelper.EXPECT().getGCStatus(ctx, &nfdCR).Return(gc)
+		mockHelper.EXPECT().getTopologyStatus(ctx, &nfdCR).Return(topology)
+
+		components := st.GetComponents(ctx, &nfdCR)
+		Expect(components).To(Equal([]nfdv1.ComponentStatus{worker, master, gc, topology}))
+	})
+
+	It("the topology updater is only reported when it is deployed", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		mockHelper.EXPECT().getWorkerStatuses(ctx, &nfdCR).Return([]nfdv1.ComponentStatus{worker})
+		mockHelper.EXPECT().getMasterStatus(ctx, &nfdCR).Return(master)
+		mockHelper.EXPECT().getGCStatus(ctx, &nfdCR).Return(gc)
+
+		components := st.GetComponents(ctx, &nfdCR)
+		Expect(components).To(Equal([]nfdv1.ComponentStatus{worker, master, gc}))
+	})
+
+	It("no component is reported when Removed", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Removed},
+		}
+
+		Expect(st.GetComponents(ctx, &nfdCR)).To(BeNil())
+	})
+})
+
 var _ = Describe("GetConditions", func() {
 	var (
 		ctrl       *gomock.Controller
@@ -49,52 +108,140 @@ var _ = Describe("GetConditions", func() {
 	})
 
 	ctx := context.Background()
-	nfdCR := nfdv1.NodeFeatureDiscovery{
-		Spec: nfdv1.NodeFeatureDiscoverySpec{
-			TopologyUpdater: true,
-		},
+	nfdCR := nfdv1.NodeFeatureDiscovery{}
+	workerConfigCond := metav1.Condition{
+		Type:   ConditionWorkerConfigAvailable,
+		Status: metav1.ConditionTrue,
+		Reason: reasonWorkerConfigManagedByOperator,
 	}
-	progConds := getProgressingConditions("progressing reason", "progressing message")
-	degConds := getDegradedConditions("degraded reason", "degraded message")
-	availConds := getAvailableConditions()
-
-	DescribeTable("checking all the flows", func(workerAvailable, masterAvailable, gcAvailable, topologyAvailable bool) {
-		expectConds := availConds
-		if !workerAvailable {
-			mockHelper.EXPECT().getWorkerNotAvailableConditions(ctx, &nfdCR).Return(degConds)
-			expectConds = degConds
-			goto executeTestFunction
+	available := nfdv1.ComponentStatus{Name: "nfd-gc", Kind: "Deployment", State: nfdv1.ComponentAvailable}
+	degradedWorker := nfdv1.ComponentStatus{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded,
+		Reason: conditionNFDWorkerDaemonSetDegraded, Message: "0 nodes have pods scheduled"}
+	degradedMaster := nfdv1.ComponentStatus{Name: "nfd-master", Kind: "Deployment", State: nfdv1.ComponentDegraded,
+		Reason: conditionNFDMasterDeploymentDegraded, Message: "number of available pods is 0"}
+	progressingTopology := nfdv1.ComponentStatus{Name: "nfd-topology-updater", Kind: "DaemonSet", State: nfdv1.ComponentProgressing,
+		Reason: conditionNFDTopologyDaemonSetProgressing, Message: "ds is progressing"}
+
+	It("all components are available", func() {
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, &nfdCR).Return(workerConfigCond)
+
+		conds := st.GetConditions(ctx, &nfdCR, []nfdv1.ComponentStatus{available})
+		compareConditions(conds, append(getAvailableConditions(), getOperandImagePinnedCondition(&nfdCR), workerConfigCond,
+			getManagedCondition(&nfdCR)))
+	})
+
+	It("conditions are set for the generation of the spec", func() {
+		generationCR := nfdv1.NodeFeatureDiscovery{ObjectMeta: metav1.ObjectMeta{Generation: 3}}
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, &generationCR).Return(workerConfigCond)
+
+		conds := st.GetConditions(ctx, &generationCR, []nfdv1.ComponentStatus{available})
+		Expect(conds).ToNot(BeEmpty())
+		for _, cond := range conds {
+			Expect(cond.ObservedGeneration).To(Equal(int64(3)), cond.Type)
 		}
-		mockHelper.EXPECT().getWorkerNotAvailableConditions(ctx, &nfdCR).Return(nil)
-		if !masterAvailable {
-			mockHelper.EXPECT().getMasterNotAvailableConditions(ctx, &nfdCR).Return(progConds)
-			expectConds = progConds
-			goto executeTestFunction
+	})
+
+	It("a single failing component keeps its own reason", func() {
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, &nfdCR).Return(workerConfigCond)
+
+		conds := st.GetConditions(ctx, &nfdCR, []nfdv1.ComponentStatus{degradedWorker, available})
+
+		availableCond := meta.FindStatusCondition(conds, conditionAvailable)
+		Expect(availableCond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(availableCond.Reason).To(Equal(reasonComponentsUnavailable))
+		Expect(availableCond.Message).To(Equal("components not available: nfd-worker"))
+		degradedCond := meta.FindStatusCondition(conds, conditionDegraded)
+		Expect(degradedCond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degradedCond.Reason).To(Equal(conditionNFDWorkerDaemonSetDegraded))
+		Expect(degradedCond.Message).To(Equal("DaemonSet nfd-worker: 0 nodes have pods scheduled"))
+		Expect(meta.IsStatusConditionFalse(conds, conditionProgressing)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(conds, conditionUpgradeable)).To(BeTrue())
+	})
+
+	It("every failing component is listed at once", func() {
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, &nfdCR).Return(workerConfigCond)
+
+		conds := st.GetConditions(ctx, &nfdCR, []nfdv1.ComponentStatus{degradedWorker, degradedMaster, available, progressingTopology})
+
+		availableCond := meta.FindStatusCondition(conds, conditionAvailable)
+		Expect(availableCond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(availableCond.Message).To(Equal("components not available: nfd-worker, nfd-master, nfd-topology-updater"))
+		degradedCond := meta.FindStatusCondition(conds, conditionDegraded)
+		Expect(degradedCond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(degradedCond.Reason).To(Equal(reasonComponentsDegraded))
+		Expect(degradedCond.Message).To(Equal("DaemonSet nfd-worker: 0 nodes have pods scheduled; " +
+			"Deployment nfd-master: number of available pods is 0"))
+		progressingCond := meta.FindStatusCondition(conds, conditionProgressing)
+		Expect(progressingCond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(progressingCond.Reason).To(Equal(conditionNFDTopologyDaemonSetProgressing))
+		Expect(progressingCond.Message).To(Equal("DaemonSet nfd-topology-updater: ds is progressing"))
+	})
+})
+
+var _ = Describe("GetConditions - ManagementState", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockHelper *MockstatusHelperAPI
+		st         *status
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockHelper = NewMockstatusHelperAPI(ctrl)
+		st = &status{
+			helper: mockHelper,
 		}
-		mockHelper.EXPECT().getMasterNotAvailableConditions(ctx, &nfdCR).Return(nil)
-		if !gcAvailable {
-			mockHelper.EXPECT().getGCNotAvailableConditions(ctx, &nfdCR).Return(degConds)
-			expectConds = degConds
-			goto executeTestFunction
+	})
+
+	ctx := context.Background()
+
+	It("reports Managed=True when spec.managementState is not set", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
+
+		conds := st.GetConditions(ctx, &nfdCR, nil)
+
+		cond := meta.FindStatusCondition(conds, ConditionManaged)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(reasonManaged))
+	})
+
+	It("still reports the component conditions when Unmanaged", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Unmanaged},
 		}
-		mockHelper.EXPECT().getGCNotAvailableConditions(ctx, &nfdCR).Return(nil)
-		if !topologyAvailable {
-			mockHelper.EXPECT().getTopologyNotAvailableConditions(ctx, &nfdCR).Return(progConds)
-			expectConds = progConds
-		} else {
-			mockHelper.EXPECT().getTopologyNotAvailableConditions(ctx, &nfdCR).Return(nil)
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
+
+		conds := st.GetConditions(ctx, &nfdCR, []nfdv1.ComponentStatus{
+			{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded, Reason: conditionNFDWorkerDaemonSetDegraded},
+		})
+
+		Expect(meta.IsStatusConditionTrue(conds, conditionDegraded)).To(BeTrue())
+		cond := meta.FindStatusCondition(conds, ConditionManaged)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonUnmanaged))
+	})
+
+	It("does not look for the removed operands when Removed", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{ManagementState: nfdv1.Removed},
 		}
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
 
-	executeTestFunction:
-		conds := st.GetConditions(ctx, &nfdCR)
-		compareConditions(conds, append(append([]metav1.Condition{}, expectConds...), getOperandImagePinnedCondition(&nfdCR)))
-	},
-		Entry("worker is not available yet", false, false, false, false),
-		Entry("worker available, master is not yet", true, false, false, false),
-		Entry("worker and master available, gc is not yet", true, true, false, false),
-		Entry("worker,master and gc available, topology is not yet", true, true, true, false),
-		Entry("all components are available", true, true, true, true),
-	)
+		conds := st.GetConditions(ctx, &nfdCR, nil)
+
+		available := meta.FindStatusCondition(conds, conditionAvailable)
+		Expect(available).ToNot(BeNil())
+		Expect(available.Status).To(Equal(metav1.ConditionFalse))
+		Expect(available.Reason).To(Equal(reasonRemoved))
+		Expect(meta.IsStatusConditionFalse(conds, conditionDegraded)).To(BeTrue())
+		cond := meta.FindStatusCondition(conds, ConditionManaged)
+		Expect(cond).ToNot(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonRemoved))
+	})
 })
 
 var _ = Describe("GetConditions - OperandImagePinned", func() {
@@ -114,17 +261,17 @@ var _ = Describe("GetConditions - OperandImagePinned", func() {
 
 	ctx := context.Background()
 
-	expectAllComponentsAvailable := func() {
-		mockHelper.EXPECT().getWorkerNotAvailableConditions(ctx, gomock.Any()).Return(nil)
-		mockHelper.EXPECT().getMasterNotAvailableConditions(ctx, gomock.Any()).Return(nil)
-		mockHelper.EXPECT().getGCNotAvailableConditions(ctx, gomock.Any()).Return(nil)
+	availableComponents := []nfdv1.ComponentStatus{
+		{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentAvailable},
+		{Name: "nfd-master", Kind: "Deployment", State: nfdv1.ComponentAvailable},
+		{Name: "nfd-gc", Kind: "Deployment", State: nfdv1.ComponentAvailable},
 	}
 
 	It("reports OperandImagePinned=False when spec.operand.image is empty and everything is healthy", func() {
 		nfdCR := nfdv1.NodeFeatureDiscovery{}
-		expectAllComponentsAvailable()
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
 
-		conds := st.GetConditions(ctx, &nfdCR)
+		conds := st.GetConditions(ctx, &nfdCR, availableComponents)
 
 		cond := meta.FindStatusCondition(conds, ConditionOperandImagePinned)
 		Expect(cond).ToNot(BeNil())
@@ -138,9 +285,9 @@ var _ = Describe("GetConditions - OperandImagePinned", func() {
 				Operand: nfdv1.OperandSpec{Image: "registry.k8s.io/nfd/node-feature-discovery:v0.15.5"},
 			},
 		}
-		expectAllComponentsAvailable()
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
 
-		conds := st.GetConditions(ctx, &nfdCR)
+		conds := st.GetConditions(ctx, &nfdCR, availableComponents)
 
 		cond := meta.FindStatusCondition(conds, ConditionOperandImagePinned)
 		Expect(cond).ToNot(BeNil())
@@ -155,10 +302,12 @@ var _ = Describe("GetConditions - OperandImagePinned", func() {
 				Operand: nfdv1.OperandSpec{Image: "registry.k8s.io/nfd/node-feature-discovery:v0.15.5"},
 			},
 		}
-		mockHelper.EXPECT().getWorkerNotAvailableConditions(ctx, gomock.Any()).
-			Return(getDegradedConditions("NFDWorkerDaemonSetDegraded", "0 nodes have pods scheduled"))
+		mockHelper.EXPECT().getWorkerConfigCondition(ctx, gomock.Any()).Return(metav1.Condition{})
 
-		conds := st.GetConditions(ctx, &nfdCR)
+		conds := st.GetConditions(ctx, &nfdCR, []nfdv1.ComponentStatus{
+			{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded,
+				Reason: conditionNFDWorkerDaemonSetDegraded, Message: "0 nodes have pods scheduled"},
+		})
 
 		degraded := meta.FindStatusCondition(conds, conditionDegraded)
 		Expect(degraded).ToNot(BeNil())
@@ -171,6 +320,37 @@ var _ = Describe("GetConditions - OperandImagePinned", func() {
 	})
 })
 
+var _ = Describe("GetMasterLeader", func() {
+	var (
+		ctrl           *gomock.Controller
+		mockDeployment *deployment.MockDeploymentAPI
+		st             *status
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockDeployment = deployment.NewMockDeploymentAPI(ctrl)
+		st = &status{
+			deploymentAPI: mockDeployment,
+		}
+	})
+
+	ctx := context.Background()
+	nfdCR := nfdv1.NodeFeatureDiscovery{}
+
+	It("the leader is returned", func() {
+		mockDeployment.EXPECT().GetMasterLeader(ctx, &nfdCR).Return("nfd-master-7d9f-abcde", nil)
+
+		Expect(st.GetMasterLeader(ctx, &nfdCR)).To(Equal("nfd-master-7d9f-abcde"))
+	})
+
+	It("an error getting the leader is reported as no leader", func() {
+		mockDeployment.EXPECT().GetMasterLeader(ctx, &nfdCR).Return("", fmt.Errorf("some error"))
+
+		Expect(st.GetMasterLeader(ctx, &nfdCR)).To(BeEmpty())
+	})
+})
+
 var _ = Describe("AreConditionsEqual", func() {
 	It("testing various use-cases", func() {
 		st := &status{}
@@ -217,6 +397,15 @@ var _ = Describe("AreConditionsEqual", func() {
 		res = st.AreConditionsEqual(firstCond, secondCond)
 		Expect(res).To(BeTrue())
 
+		By("conditions of different generations are not equal")
+		firstCond = getAvailableConditions()
+		secondCond = getAvailableConditions()
+		for i := range secondCond {
+			secondCond[i].ObservedGeneration = 2
+		}
+		res = st.AreConditionsEqual(firstCond, secondCond)
+		Expect(res).To(BeFalse())
+
 		By("degraded and progressing conditions are not equal")
 		firstCond = getDegradedConditions("reason1", "message1")
 		secondCond = getProgressingConditions("reason1", "message1")
@@ -225,17 +414,19 @@ var _ = Describe("AreConditionsEqual", func() {
 	})
 })
 
-var _ = Describe("getWorkerOrTopologyNotAvailableConditions", func() {
+var _ = Describe("getWorkerOrTopologyStatus", func() {
 	var (
-		ctrl   *gomock.Controller
-		mockDS *daemonset.MockDaemonsetAPI
-		h      statusHelperAPI
+		ctrl     *gomock.Controller
+		mockDS   *daemonset.MockDaemonsetAPI
+		mockPods *MockpodDiagnosticsAPI
+		h        statusHelperAPI
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockDS = daemonset.NewMockDaemonsetAPI(ctrl)
-		h = newStatusHelperAPI(nil, mockDS)
+		mockPods = NewMockpodDiagnosticsAPI(ctrl)
+		h = newStatusHelperAPI(nil, mockDS, nil, mockPods)
 	})
 
 	nfdCR := nfdv1.NodeFeatureDiscovery{
@@ -249,119 +440,153 @@ var _ = Describe("getWorkerOrTopologyNotAvailableConditions", func() {
 	It("worker of topology ds not available", func() {
 		err := fmt.Errorf("some error")
 		By("checking worker")
-		expectedConds := getDegradedConditions(conditionFailedGettingNFDWorkerDaemonSet, err.Error())
 		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-worker").Return(nil, err)
 
-		resCond := h.getWorkerNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		res := h.getWorkerStatuses(ctx, &nfdCR)
+		Expect(res).To(Equal([]nfdv1.ComponentStatus{
+			{Name: "nfd-worker", Kind: "DaemonSet", State: nfdv1.ComponentDegraded,
+				Reason: conditionFailedGettingNFDWorkerDaemonSet, Message: err.Error()},
+		}))
 
 		By("checking topology")
-		expectedConds = getDegradedConditions(conditionFailedGettingNFDTopologyDaemonSet, err.Error())
 		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-topology-updater").Return(nil, err)
 
-		resCond = h.getTopologyNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		Expect(h.getTopologyStatus(ctx, &nfdCR)).To(Equal(nfdv1.ComponentStatus{
+			Name: "nfd-topology-updater", Kind: "DaemonSet", State: nfdv1.ComponentDegraded,
+			Reason: conditionFailedGettingNFDTopologyDaemonSet, Message: err.Error(),
+		}))
 	})
 
-	It("worker or topology ds number of scheduled is 0", func() {
-		ds := &appsv1.DaemonSet{
-			Status: appsv1.DaemonSetStatus{
-				DesiredNumberScheduled: 0,
-			},
+	DescribeTable("worker or topology ds state", func(dsStatus appsv1.DaemonSetStatus, state nfdv1.ComponentState,
+		workerReason, topologyReason, message string) {
+		ds := &appsv1.DaemonSet{Status: dsStatus}
+		if state != nfdv1.ComponentAvailable {
+			mockPods.EXPECT().getDaemonSetPodFailures(ctx, ds).Return(nil).Times(2)
 		}
+
 		By("checking worker")
-		expectedConds := getDegradedConditions(conditionNFDWorkerDaemonSetDegraded, "number of desired nodes for scheduling is 0")
 		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-worker").Return(ds, nil)
 
-		resCond := h.getWorkerNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		res := h.getWorkerStatuses(ctx, &nfdCR)
+		Expect(res).To(HaveLen(1))
+		Expect(res[0].State).To(Equal(state))
+		Expect(res[0].Reason).To(Equal(workerReason))
+		Expect(res[0].Message).To(Equal(message))
 
 		By("checking topology")
-		expectedConds = getDegradedConditions(conditionNFDTopologyDaemonSetDegraded, "number of desired nodes for scheduling is 0")
 		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-topology-updater").Return(ds, nil)
 
-		resCond = h.getTopologyNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
-	})
+		topology := h.getTopologyStatus(ctx, &nfdCR)
+		Expect(topology.State).To(Equal(state))
+		Expect(topology.Reason).To(Equal(topologyReason))
+		Expect(topology.Message).To(Equal(message))
+	},
+		Entry("no node is selected", appsv1.DaemonSetStatus{DesiredNumberScheduled: 0},
+			nfdv1.ComponentAvailable, "", "", ""),
+		Entry("current number of scheduled pods is 0", appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 0},
+			nfdv1.ComponentDegraded, conditionNFDWorkerDaemonSetDegraded, conditionNFDTopologyDaemonSetDegraded,
+			"0 nodes have pods scheduled"),
+		Entry("number of pods has not yet reached desired number",
+			appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, NumberReady: 1},
+			nfdv1.ComponentProgressing, conditionNFDWorkerDaemonSetProgressing, conditionNFDTopologyDaemonSetProgressing,
+			"ds is progressing"),
+		Entry("some pods are not updated yet",
+			appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, NumberReady: 2, UpdatedNumberScheduled: 1},
+			nfdv1.ComponentProgressing, conditionNFDWorkerDaemonSetProgressing, conditionNFDTopologyDaemonSetProgressing,
+			"ds is progressing"),
+		Entry("all pods are available",
+			appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, CurrentNumberScheduled: 2, NumberReady: 2, UpdatedNumberScheduled: 2},
+			nfdv1.ComponentAvailable, "", "", ""),
+	)
 
-	It("worker or topology ds current number of scheduled pods is 0", func() {
+	It("the counts, image and observed generation of the ds are reported", func() {
 		ds := &appsv1.DaemonSet{
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "nfd-worker", Image: "registry.k8s.io/nfd/node-feature-discovery:v0.16.0"},
+							{Name: "sidecar", Image: "sidecar:latest"},
+						},
+					},
+				},
+			},
 			Status: appsv1.DaemonSetStatus{
-				DesiredNumberScheduled: 2,
-				CurrentNumberScheduled: 0,
+				DesiredNumberScheduled: 3,
+				CurrentNumberScheduled: 3,
+				NumberReady:            2,
+				UpdatedNumberScheduled: 1,
+				ObservedGeneration:     4,
 			},
 		}
-		By("checking worker")
-		expectedConds := getDegradedConditions(conditionNFDWorkerDaemonSetDegraded, "0 nodes have pods scheduled")
+		failures := []nfdv1.PodFailure{
+			{Reason: nfdv1.PodCrashLoopBackOff, Count: 1, Nodes: []string{"worker-2"}, Message: "back-off 5m0s restarting failed container"},
+		}
 		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-worker").Return(ds, nil)
-
-		resCond := h.getWorkerNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
-
-		By("checking topology")
-		expectedConds = getDegradedConditions(conditionNFDTopologyDaemonSetDegraded, "0 nodes have pods scheduled")
-		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-topology-updater").Return(ds, nil)
-
-		resCond = h.getTopologyNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		mockPods.EXPECT().getDaemonSetPodFailures(ctx, ds).Return(failures)
+
+		res := h.getWorkerStatuses(ctx, &nfdCR)
+		Expect(res).To(Equal([]nfdv1.ComponentStatus{
+			{
+				Name:               "nfd-worker",
+				Kind:               "DaemonSet",
+				State:              nfdv1.ComponentProgressing,
+				Reason:             conditionNFDWorkerDaemonSetProgressing,
+				Message:            "ds is progressing, pods failing: CrashLoopBackOff: 1 pods (worker-2)",
+				Desired:            3,
+				Ready:              2,
+				Updated:            1,
+				Image:              "registry.k8s.io/nfd/node-feature-discovery:v0.16.0",
+				ObservedGeneration: 4,
+				PodFailures:        failures,
+			},
+		}))
 	})
 
-	It("worker or topology ds number of pods has not yet reached desired number", func() {
-		ds := &appsv1.DaemonSet{
-			Status: appsv1.DaemonSetStatus{
-				DesiredNumberScheduled: 2,
-				CurrentNumberScheduled: 2,
-				NumberReady:            1,
+	It("worker profiles ds, all of them are reported", func() {
+		profilesCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+			},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerProfiles: []nfdv1.WorkerProfile{{Name: "gpu"}, {Name: "sriov"}},
 			},
 		}
-		By("worker")
-		expectedConds := getProgressingConditions(conditionNFDWorkerDaemonSetProgressing, "ds is progressing")
-		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-worker").Return(ds, nil)
-
-		resCond := h.getWorkerNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
-
-		By("topology")
-		expectedConds = getProgressingConditions(conditionNFDTopologyDaemonSetProgressing, "ds is progressing")
-		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-topology-updater").Return(ds, nil)
-
-		resCond = h.getTopologyNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
-	})
-
-	It("worker or topology ds all pods are available", func() {
-		ds := &appsv1.DaemonSet{
+		progressingDS := &appsv1.DaemonSet{
 			Status: appsv1.DaemonSetStatus{
 				DesiredNumberScheduled: 2,
 				CurrentNumberScheduled: 2,
-				NumberReady:            2,
+				NumberReady:            1,
 			},
 		}
-		By("worker")
-		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-worker").Return(ds, nil)
-
-		resCond := h.getWorkerNotAvailableConditions(ctx, &nfdCR)
-		Expect(resCond).To(BeNil())
-
-		By("topology")
-		mockDS.EXPECT().GetDaemonSet(ctx, nfdCR.Namespace, "nfd-topology-updater").Return(ds, nil)
-
-		resCond = h.getTopologyNotAvailableConditions(ctx, &nfdCR)
-		Expect(resCond).To(BeNil())
+		gomock.InOrder(
+			mockDS.EXPECT().GetDaemonSet(ctx, profilesCR.Namespace, "nfd-worker-gpu").Return(nil, fmt.Errorf("some error")),
+			mockDS.EXPECT().GetDaemonSet(ctx, profilesCR.Namespace, "nfd-worker-sriov").Return(progressingDS, nil),
+			mockPods.EXPECT().getDaemonSetPodFailures(ctx, progressingDS).Return(nil),
+		)
+
+		res := h.getWorkerStatuses(ctx, &profilesCR)
+		Expect(res).To(HaveLen(2))
+		Expect(res[0].Name).To(Equal("nfd-worker-gpu"))
+		Expect(res[0].State).To(Equal(nfdv1.ComponentDegraded))
+		Expect(res[1].Name).To(Equal("nfd-worker-sriov"))
+		Expect(res[1].State).To(Equal(nfdv1.ComponentProgressing))
 	})
 })
 
-var _ = Describe("getMasterOrGCNotAvailableCondition", func() {
+var _ = Describe("getMasterOrGCStatus", func() {
 	var (
 		ctrl           *gomock.Controller
 		mockDeployment *deployment.MockDeploymentAPI
+		mockPods       *MockpodDiagnosticsAPI
 		h              statusHelperAPI
 	)
 
 	BeforeEach(func() {
 		ctrl = gomock.NewController(GinkgoT())
 		mockDeployment = deployment.NewMockDeploymentAPI(ctrl)
-		h = newStatusHelperAPI(mockDeployment, nil)
+		mockPods = NewMockpodDiagnosticsAPI(ctrl)
+		h = newStatusHelperAPI(mockDeployment, nil, nil, mockPods)
 	})
 
 	nfdCR := nfdv1.NodeFeatureDiscovery{
@@ -375,18 +600,20 @@ var _ = Describe("getMasterOrGCNotAvailableCondition", func() {
 		err := fmt.Errorf("some error")
 
 		By("master")
-		expectedConds := getDegradedConditions(conditionFailedGettingNFDMasterDeployment, err.Error())
 		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-master").Return(nil, err)
 
-		resCond := h.getMasterNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		Expect(h.getMasterStatus(ctx, &nfdCR)).To(Equal(nfdv1.ComponentStatus{
+			Name: "nfd-master", Kind: "Deployment", State: nfdv1.ComponentDegraded,
+			Reason: conditionFailedGettingNFDMasterDeployment, Message: err.Error(),
+		}))
 
 		By("GC")
-		expectedConds = getDegradedConditions(conditionFailedGettingNFDGCDeployment, err.Error())
 		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-gc").Return(nil, err)
 
-		resCond = h.getGCNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		Expect(h.getGCStatus(ctx, &nfdCR)).To(Equal(nfdv1.ComponentStatus{
+			Name: "nfd-gc", Kind: "Deployment", State: nfdv1.ComponentDegraded,
+			Reason: conditionFailedGettingNFDGCDeployment, Message: err.Error(),
+		}))
 	})
 
 	It("master or GC deployment available replicas 0", func() {
@@ -395,38 +622,221 @@ var _ = Describe("getMasterOrGCNotAvailableCondition", func() {
 				AvailableReplicas: 0,
 			},
 		}
+		failures := []nfdv1.PodFailure{
+			{Reason: nfdv1.PodAdmissionFailed, Count: 1, Message: "unable to validate against any security context constraint"},
+		}
 		By("master")
-		expectedConds := getDegradedConditions(conditionNFDMasterDeploymentDegraded, "number of available pods is 0")
 		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-master").Return(dep, nil)
+		mockPods.EXPECT().getDeploymentPodFailures(ctx, dep).Return(failures)
 
-		resCond := h.getMasterNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		master := h.getMasterStatus(ctx, &nfdCR)
+		Expect(master.State).To(Equal(nfdv1.ComponentDegraded))
+		Expect(master.Reason).To(Equal(conditionNFDMasterDeploymentDegraded))
+		Expect(master.Message).To(Equal("number of available pods is 0, pods failing: AdmissionFailed: 1 pods"))
+		Expect(master.PodFailures).To(Equal(failures))
 
 		By("GC")
-		expectedConds = getDegradedConditions(conditionNFDGCDeploymentDegraded, "number of available pods is 0")
 		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-gc").Return(dep, nil)
+		mockPods.EXPECT().getDeploymentPodFailures(ctx, dep).Return(nil)
 
-		resCond = h.getGCNotAvailableConditions(ctx, &nfdCR)
-		compareConditions(resCond, expectedConds)
+		gc := h.getGCStatus(ctx, &nfdCR)
+		Expect(gc.State).To(Equal(nfdv1.ComponentDegraded))
+		Expect(gc.Reason).To(Equal(conditionNFDGCDeploymentDegraded))
+		Expect(gc.Message).To(Equal("number of available pods is 0"))
 	})
 
 	It("master or GC deployment all pods are available", func() {
+		replicas := int32(2)
 		dep := &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "nfd-master", Image: "registry.k8s.io/nfd/node-feature-discovery:v0.16.0"}},
+					},
+				},
+			},
 			Status: appsv1.DeploymentStatus{
-				AvailableReplicas: 1,
+				AvailableReplicas:  2,
+				ReadyReplicas:      2,
+				UpdatedReplicas:    2,
+				ObservedGeneration: 3,
 			},
 		}
 		By("master")
 		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-master").Return(dep, nil)
 
-		resCond := h.getMasterNotAvailableConditions(ctx, &nfdCR)
-		Expect(resCond).To(BeNil())
+		Expect(h.getMasterStatus(ctx, &nfdCR)).To(Equal(nfdv1.ComponentStatus{
+			Name:               "nfd-master",
+			Kind:               "Deployment",
+			State:              nfdv1.ComponentAvailable,
+			Desired:            2,
+			Ready:              2,
+			Updated:            2,
+			Image:              "registry.k8s.io/nfd/node-feature-discovery:v0.16.0",
+			ObservedGeneration: 3,
+		}))
 
 		By("GC")
-		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-gc").Return(dep, nil)
+		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-gc").Return(&appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1, ReadyReplicas: 1, UpdatedReplicas: 1},
+		}, nil)
+
+		gc := h.getGCStatus(ctx, &nfdCR)
+		Expect(gc.State).To(Equal(nfdv1.ComponentAvailable))
+		Expect(gc.Desired).To(Equal(int32(1)))
+	})
+
+	It("master or GC deployment with pods not ready or not updated", func() {
+		replicas := int32(2)
+		notReady := &appsv1.Deployment{
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1, ReadyReplicas: 1, UpdatedReplicas: 2},
+		}
+		failures := []nfdv1.PodFailure{
+			{Reason: nfdv1.PodCrashLoopBackOff, Count: 1, Nodes: []string{"master-1"}, Message: "back-off 5m0s restarting failed container"},
+		}
+		By("master")
+		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-master").Return(notReady, nil)
+		mockPods.EXPECT().getDeploymentPodFailures(ctx, notReady).Return(failures)
+
+		master := h.getMasterStatus(ctx, &nfdCR)
+		Expect(master.State).To(Equal(nfdv1.ComponentProgressing))
+		Expect(master.Reason).To(Equal(conditionNFDMasterDeploymentProgressing))
+		Expect(master.Message).To(Equal("deployment is progressing, pods failing: CrashLoopBackOff: 1 pods (master-1)"))
+		Expect(master.PodFailures).To(Equal(failures))
+
+		By("GC")
+		notUpdated := &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{AvailableReplicas: 1, ReadyReplicas: 1, UpdatedReplicas: 0},
+		}
+		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-gc").Return(notUpdated, nil)
+		mockPods.EXPECT().getDeploymentPodFailures(ctx, notUpdated).Return(nil)
 
-		resCond = h.getGCNotAvailableConditions(ctx, &nfdCR)
-		Expect(resCond).To(BeNil())
+		gc := h.getGCStatus(ctx, &nfdCR)
+		Expect(gc.State).To(Equal(nfdv1.ComponentProgressing))
+		Expect(gc.Reason).To(Equal(conditionNFDGCDeploymentProgressing))
+		Expect(gc.Message).To(Equal("deployment is progressing"))
+	})
+
+	It("master deployment whose rollout exceeded its progress deadline", func() {
+		dep := &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{
+				AvailableReplicas: 1,
+				ReadyReplicas:     1,
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:    appsv1.DeploymentProgressing,
+						Status:  corev1.ConditionFalse,
+						Reason:  progressDeadlineExceededReason,
+						Message: `ReplicaSet "nfd-master-7d4b9" has timed out progressing.`,
+					},
+				},
+			},
+		}
+		mockDeployment.EXPECT().GetDeployment(ctx, nfdCR.Namespace, "nfd-master").Return(dep, nil)
+		mockPods.EXPECT().getDeploymentPodFailures(ctx, dep).Return(nil)
+
+		master := h.getMasterStatus(ctx, &nfdCR)
+		Expect(master.State).To(Equal(nfdv1.ComponentDegraded))
+		Expect(master.Reason).To(Equal(conditionNFDMasterDeploymentDegraded))
+		Expect(master.Message).To(Equal(`ReplicaSet "nfd-master-7d4b9" has timed out progressing.`))
+	})
+})
+
+var _ = Describe("getWorkerConfigCondition", func() {
+	var (
+		ctrl   *gomock.Controller
+		mockCM *configmap.MockConfigMapAPI
+		h      statusHelperAPI
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockCM = configmap.NewMockConfigMapAPI(ctrl)
+		h = newStatusHelperAPI(nil, nil, mockCM, nil)
+	})
+
+	ctx := context.Background()
+	refNFD := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+		},
+		Spec: nfdv1.NodeFeatureDiscoverySpec{
+			WorkerConfig: nfdv1.ConfigMap{
+				ConfigMapRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+					Key:                  "nfd-worker.conf",
+				},
+			},
+		},
+	}
+
+	It("worker config is generated by the operator", func() {
+		cond := h.getWorkerConfigCondition(ctx, &nfdv1.NodeFeatureDiscovery{})
+		Expect(cond.Type).To(Equal(ConditionWorkerConfigAvailable))
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(reasonWorkerConfigManagedByOperator))
+	})
+
+	It("referenced configmap and key exist", func() {
+		cm := &corev1.ConfigMap{Data: map[string]string{"nfd-worker.conf": ""}}
+		mockCM.EXPECT().GetConfigMap(ctx, refNFD.Namespace, "user-worker-config").Return(cm, nil)
+
+		cond := h.getWorkerConfigCondition(ctx, &refNFD)
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(reasonWorkerConfigMapFound))
+	})
+
+	It("referenced configmap does not exist", func() {
+		mockCM.EXPECT().GetConfigMap(ctx, refNFD.Namespace, "user-worker-config").
+			Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, "user-worker-config"))
+
+		cond := h.getWorkerConfigCondition(ctx, &refNFD)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonWorkerConfigMapNotFound))
+		Expect(cond.Message).To(ContainSubstring("test-namespace/user-worker-config"))
+	})
+
+	It("referenced key does not exist in the configmap", func() {
+		cm := &corev1.ConfigMap{Data: map[string]string{"other-key": ""}}
+		mockCM.EXPECT().GetConfigMap(ctx, refNFD.Namespace, "user-worker-config").Return(cm, nil)
+
+		cond := h.getWorkerConfigCondition(ctx, &refNFD)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonWorkerConfigMapKeyNotFound))
+		Expect(cond.Message).To(ContainSubstring(`"nfd-worker.conf"`))
+	})
+
+	It("configmap referenced by a worker profile does not exist", func() {
+		profilesNFD := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				WorkerProfiles: []nfdv1.WorkerProfile{
+					{Name: "compute"},
+					{Name: "gpu", WorkerConfig: &nfdv1.ConfigMap{ConfigMapRef: &corev1.ConfigMapKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "gpu-worker-config"},
+						Key:                  "nfd-worker.conf",
+					}}},
+				},
+			},
+		}
+		mockCM.EXPECT().GetConfigMap(ctx, profilesNFD.Namespace, "gpu-worker-config").
+			Return(nil, k8serrors.NewNotFound(schema.GroupResource{}, "gpu-worker-config"))
+
+		cond := h.getWorkerConfigCondition(ctx, &profilesNFD)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonWorkerConfigMapNotFound))
+		Expect(cond.Message).To(ContainSubstring("test-namespace/gpu-worker-config"))
+	})
+
+	It("failed to get the referenced configmap", func() {
+		mockCM.EXPECT().GetConfigMap(ctx, refNFD.Namespace, "user-worker-config").Return(nil, fmt.Errorf("some error"))
+
+		cond := h.getWorkerConfigCondition(ctx, &refNFD)
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(reasonFailedGettingWorkerConfigMap))
+		Expect(cond.Message).To(Equal("some error"))
 	})
 })
 
@@ -19,6 +19,7 @@ package test
 import (
 	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 	nfdv1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 )
@@ -30,6 +31,7 @@ func TestScheme() (*runtime.Scheme, error) {
 		scheme.AddToScheme,
 		nfdv1.AddToScheme,
 		nfdv1alpha1.AddToScheme,
+		apiextensionsv1.AddToScheme,
 	}
 
 	for _, f := range funcs {
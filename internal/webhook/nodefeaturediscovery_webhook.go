@@ -0,0 +1,435 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
+	"github.com/openshift/cluster-nfd-operator/internal/presets"
+)
+
+const (
+	defaultImagePullPolicy = corev1.PullAlways
+
+	// longestComponentName is the longest NFD component name that
+	// Spec.Instance is appended to when naming the generated objects
+	longestComponentName = "nfd-topology-updater"
+)
+
+// allowedReservedLabelNs lists the label namespaces under kubernetes.io that
+// nfd-master publishes to by default, and which are therefore accepted even
+// though they are sub-domains of a reserved namespace
+var allowedReservedLabelNs = []string{
+	"feature.node.kubernetes.io",
+	"profile.node.kubernetes.io",
+}
+
+// reservedLabelNs lists the label namespaces owned by Kubernetes itself
+var reservedLabelNs = []string{
+	"kubernetes.io",
+	"k8s.io",
+}
+
+// reservedHostPorts lists the well-known ports already bound on the nodes,
+// which the host-networked nfd-worker must not listen on
+var reservedHostPorts = map[int]string{
+	9100:  "node-exporter",
+	9537:  "CRI-O metrics",
+	10248: "kubelet healthz",
+	10249: "kube-proxy metrics",
+	10250: "kubelet",
+	10256: "kube-proxy healthz",
+}
+
+// +kubebuilder:webhook:path=/mutate-nfd-openshift-io-v1-nodefeaturediscovery,mutating=true,failurePolicy=fail,sideEffects=None,groups=nfd.openshift.io,resources=nodefeaturediscoveries,verbs=create;update,versions=v1,name=mnodefeaturediscovery.nfd.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nfd-openshift-io-v1-nodefeaturediscovery,mutating=false,failurePolicy=fail,sideEffects=None,groups=nfd.openshift.io,resources=nodefeaturediscoveries,verbs=create;update,versions=v1,name=vnodefeaturediscovery.nfd.openshift.io,admissionReviewVersions=v1
+
+// nodeFeatureDiscoveryWebhook defaults and validates NodeFeatureDiscovery objects
+type nodeFeatureDiscoveryWebhook struct {
+	// client reads from the API server rather than from the cache of the
+	// manager, which is restricted to the watched namespace, as the worker
+	// ports of the instances of all namespaces are checked
+	client client.Reader
+}
+
+func NewNodeFeatureDiscoveryWebhook(client client.Reader) *nodeFeatureDiscoveryWebhook {
+	return &nodeFeatureDiscoveryWebhook{
+		client: client,
+	}
+}
+
+// SetupWithManager registers the defaulting and validating webhooks for
+// NodeFeatureDiscovery with the manager's webhook server
+func (w *nodeFeatureDiscoveryWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nfdv1.NodeFeatureDiscovery{}).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// Default fills in the operand fields that the operator would otherwise
+// default implicitly, so that the stored object reflects what is deployed
+func (w *nodeFeatureDiscoveryWebhook) Default(ctx context.Context, obj runtime.Object) error {
+	nfdInstance, ok := obj.(*nfdv1.NodeFeatureDiscovery)
+	if !ok {
+		return fmt.Errorf("expected a NodeFeatureDiscovery but got a %T", obj)
+	}
+
+	if nfdInstance.Spec.Operand.ImagePullPolicy == "" {
+		nfdInstance.Spec.Operand.ImagePullPolicy = string(defaultImagePullPolicy)
+	}
+	if nfdInstance.Spec.Operand.ServicePort == 0 {
+		nfdInstance.Spec.Operand.ServicePort = nfdv1.DefaultServicePort
+	}
+	ports := &nfdInstance.Spec.Operand.Ports
+	if ports.Worker == 0 {
+		ports.Worker = nfdv1.DefaultOperandPort
+	}
+	if ports.GC == 0 {
+		ports.GC = nfdv1.DefaultOperandPort
+	}
+	if ports.TopologyUpdater == 0 {
+		ports.TopologyUpdater = nfdv1.DefaultOperandPort
+	}
+	return nil
+}
+
+func (w *nodeFeatureDiscoveryWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	nfdInstance, ok := obj.(*nfdv1.NodeFeatureDiscovery)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodeFeatureDiscovery but got a %T", obj)
+	}
+	return w.validate(ctx, nfdInstance, nil)
+}
+
+// ValidateUpdate only validates changes of the spec. The updates of an
+// instance being deleted, and the ones of its metadata such as the finalizer
+// set and removed by the operator, are always accepted, as the checks against
+// the other instances and the nodes could otherwise block them
+func (w *nodeFeatureDiscoveryWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldInstance, ok := oldObj.(*nfdv1.NodeFeatureDiscovery)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodeFeatureDiscovery but got a %T", oldObj)
+	}
+	nfdInstance, ok := newObj.(*nfdv1.NodeFeatureDiscovery)
+	if !ok {
+		return nil, fmt.Errorf("expected a NodeFeatureDiscovery but got a %T", newObj)
+	}
+	if nfdInstance.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldInstance.Spec, nfdInstance.Spec) {
+		return nil, nil
+	}
+	return w.validate(ctx, nfdInstance, oldInstance)
+}
+
+func (w *nodeFeatureDiscoveryWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the spec of the instance, oldInstance being nil on create.
+// The checks against the other instances and the nodes only run when the
+// fields they depend on change, so that an instance is not rejected for a
+// change of the cluster unrelated to the update
+func (w *nodeFeatureDiscoveryWebhook) validate(ctx context.Context, nfdInstance, oldInstance *nfdv1.NodeFeatureDiscovery) (admission.Warnings, error) {
+	allErrs := validateNodeFeatureDiscoverySpec(&nfdInstance.Spec, field.NewPath("spec"))
+
+	if oldInstance == nil || workerPlacementChanged(oldInstance, nfdInstance) {
+		portErrs, err := w.validateWorkerPortConflicts(ctx, nfdInstance, field.NewPath("spec", "operand", "ports", "worker"))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, portErrs...)
+	}
+
+	if oldInstance == nil || oldInstance.Spec.Operand.MasterReplicaCount() != nfdInstance.Spec.Operand.MasterReplicaCount() {
+		leaseErrs, err := w.validateMasterLeaseConflicts(ctx, nfdInstance, field.NewPath("spec", "operand", "masterReplicas"))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, leaseErrs...)
+	}
+
+	if oldInstance == nil || !equality.Semantic.DeepEqual(oldInstance.Spec.WorkerProfiles, nfdInstance.Spec.WorkerProfiles) {
+		overlapErrs, err := w.validateWorkerProfileOverlaps(ctx, nfdInstance, field.NewPath("spec", "workerProfiles"))
+		if err != nil {
+			return nil, err
+		}
+		allErrs = append(allErrs, overlapErrs...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, k8serrors.NewInvalid(nfdv1.GroupVersion.WithKind("NodeFeatureDiscovery").GroupKind(), nfdInstance.Name, allErrs)
+}
+
+// workerPlacementChanged returns true if the port of the workers or the nodes
+// they are deployed on changed
+func workerPlacementChanged(oldInstance, nfdInstance *nfdv1.NodeFeatureDiscovery) bool {
+	return oldInstance.Spec.Operand.WorkerPort() != nfdInstance.Spec.Operand.WorkerPort() ||
+		!equality.Semantic.DeepEqual(oldInstance.Spec.Operand.WorkerNodeSelector, nfdInstance.Spec.Operand.WorkerNodeSelector) ||
+		!equality.Semantic.DeepEqual(oldInstance.Spec.WorkerProfiles, nfdInstance.Spec.WorkerProfiles)
+}
+
+func validateNodeFeatureDiscoverySpec(spec *nfdv1.NodeFeatureDiscoverySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validateInstance(spec.Instance, fldPath.Child("instance"))...)
+	allErrs = append(allErrs, validateOperandSpec(&spec.Operand, fldPath.Child("operand"))...)
+	allErrs = append(allErrs, validateExtraLabelNs(spec.ExtraLabelNs, fldPath.Child("extraLabelNs"))...)
+
+	if strings.TrimSpace(spec.LabelWhiteList) != "" {
+		if _, err := regexp.Compile(spec.LabelWhiteList); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("labelWhiteList"), spec.LabelWhiteList,
+				fmt.Sprintf("must be a valid regular expression: %v", err)))
+		}
+	}
+
+	allErrs = append(allErrs, validateWorkerConfig(&spec.WorkerConfig, fldPath.Child("workerConfig"))...)
+	allErrs = append(allErrs, validateWorkerProfiles(spec, fldPath.Child("workerProfiles"))...)
+	allErrs = append(allErrs, validateTopologyUpdaterConfig(spec.TopologyUpdaterConfig, fldPath.Child("topologyUpdaterConfig"))...)
+	allErrs = append(allErrs, validateRulePresets(spec.RulePresets, fldPath.Child("rulePresets"))...)
+
+	return allErrs
+}
+
+// validateRulePresets checks that the presets are part of the catalog shipped
+// with the operator
+func validateRulePresets(rulePresets []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, name := range rulePresets {
+		if _, ok := presets.Get(name); !ok {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), name, presets.Names()))
+		}
+	}
+
+	return allErrs
+}
+
+func validateInstance(instance string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if instance == "" {
+		return allErrs
+	}
+
+	for _, msg := range validation.IsDNS1123Label(instance) {
+		allErrs = append(allErrs, field.Invalid(fldPath, instance, msg))
+	}
+	// the instance is used as a suffix of object names and of the "app" label
+	// value, both of which are limited to 63 characters
+	if maxLen := validation.DNS1123LabelMaxLength - len(longestComponentName+"-"); len(instance) > maxLen {
+		allErrs = append(allErrs, field.TooLong(fldPath, instance, maxLen))
+	}
+
+	return allErrs
+}
+
+func validateOperandSpec(operand *nfdv1.OperandSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	supportedPullPolicies := []string{string(corev1.PullAlways), string(corev1.PullIfNotPresent), string(corev1.PullNever)}
+	if operand.ImagePullPolicy != "" && !slices.Contains(supportedPullPolicies, operand.ImagePullPolicy) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("imagePullPolicy"), operand.ImagePullPolicy, supportedPullPolicies))
+	}
+
+	allErrs = append(allErrs, validatePort(operand.ServicePort, fldPath.Child("servicePort"))...)
+	allErrs = append(allErrs, validateOperandPorts(&operand.Ports, fldPath.Child("ports"))...)
+
+	allErrs = append(allErrs, validateResources(operand.MasterResources, fldPath.Child("masterResources"))...)
+	allErrs = append(allErrs, validateResources(operand.WorkerResources, fldPath.Child("workerResources"))...)
+	allErrs = append(allErrs, validateResources(operand.GCResources, fldPath.Child("gcResources"))...)
+	allErrs = append(allErrs, validateResources(operand.TopologyUpdaterResources, fldPath.Child("topologyUpdaterResources"))...)
+	allErrs = append(allErrs, validateResources(operand.PruneResources, fldPath.Child("pruneResources"))...)
+
+	return allErrs
+}
+
+// validateResources checks that no resource request exceeds its limit, which
+// the API server would otherwise only report when creating the operand pods
+func validateResources(resources *corev1.ResourceRequirements, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if resources == nil {
+		return allErrs
+	}
+	for name, request := range resources.Requests {
+		limit, ok := resources.Limits[name]
+		if ok && request.Cmp(limit) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requests").Key(string(name)), request.String(),
+				fmt.Sprintf("must be less than or equal to %s limit of %s", name, limit.String())))
+		}
+	}
+
+	return allErrs
+}
+
+func validateOperandPorts(ports *nfdv1.OperandPorts, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validatePort(ports.Worker, fldPath.Child("worker"))...)
+	allErrs = append(allErrs, validatePort(ports.GC, fldPath.Child("gc"))...)
+	allErrs = append(allErrs, validatePort(ports.TopologyUpdater, fldPath.Child("topologyUpdater"))...)
+
+	// nfd-worker runs in the host network namespace, so its port is opened on
+	// the node itself, next to the ones of the node services
+	if owner, ok := reservedHostPorts[ports.Worker]; ok {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("worker"), ports.Worker,
+			fmt.Sprintf("port is already used by %s on the nodes running nfd-worker with host networking", owner)))
+	}
+
+	return allErrs
+}
+
+// validatePort checks a port field, where 0 means that the default port is used
+func validatePort(port int, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if port == 0 {
+		return allErrs
+	}
+	for _, msg := range validation.IsValidPortNum(port) {
+		allErrs = append(allErrs, field.Invalid(fldPath, port, msg))
+	}
+
+	return allErrs
+}
+
+// validateWorkerPortConflicts checks that no other NodeFeatureDiscovery, in any
+// namespace, deploys its host-networked workers on the same port on some of
+// the same nodes, since both workers would then compete for it. Instances on
+// disjoint node pools, e.g. a canary next to the production instance, can
+// share the port
+func (w *nodeFeatureDiscoveryWebhook) validateWorkerPortConflicts(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	fldPath *field.Path) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	nfdList := nfdv1.NodeFeatureDiscoveryList{}
+	if err := w.client.List(ctx, &nfdList); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatureDiscovery objects: %w", err)
+	}
+
+	workerPort := nfdInstance.Spec.Operand.WorkerPort()
+	var nodes *corev1.NodeList
+	var workerNodes sets.Set[string]
+	for _, other := range nfdList.Items {
+		if other.Namespace == nfdInstance.Namespace && other.Name == nfdInstance.Name {
+			continue
+		}
+		if other.Spec.Operand.WorkerPort() != workerPort {
+			continue
+		}
+		if nodes == nil {
+			nodes = &corev1.NodeList{}
+			if err := w.client.List(ctx, nodes); err != nil {
+				return nil, fmt.Errorf("failed to list nodes: %w", err)
+			}
+			workerNodes = daemonset.SelectWorkerNodes(nfdInstance.GetWorkerProfiles(), nodes.Items)
+		}
+		shared := workerNodes.Intersection(daemonset.SelectWorkerNodes(other.GetWorkerProfiles(), nodes.Items))
+		if shared.Len() > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, workerPort,
+				fmt.Sprintf("port is already used by the workers of NodeFeatureDiscovery %s/%s on node %s",
+					other.Namespace, other.Name, sets.List(shared)[0])))
+			break
+		}
+	}
+
+	return allErrs, nil
+}
+
+// validateMasterLeaseConflicts checks that no other NodeFeatureDiscovery of the
+// namespace runs several nfd-master replicas. nfd-master does not allow to
+// name its leader election lease, so the replicas of all the instances of a
+// namespace would elect a single leader between them
+func (w *nodeFeatureDiscoveryWebhook) validateMasterLeaseConflicts(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	fldPath *field.Path) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	if !nfdInstance.Spec.Operand.MasterLeaderElection() {
+		return allErrs, nil
+	}
+
+	nfdList := nfdv1.NodeFeatureDiscoveryList{}
+	if err := w.client.List(ctx, &nfdList, client.InNamespace(nfdInstance.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list NodeFeatureDiscovery objects in namespace %s: %w", nfdInstance.Namespace, err)
+	}
+	for _, other := range nfdList.Items {
+		if other.Name != nfdInstance.Name && other.Spec.Operand.MasterLeaderElection() {
+			allErrs = append(allErrs, field.Invalid(fldPath, nfdInstance.Spec.Operand.MasterReplicaCount(),
+				fmt.Sprintf("the nfd-master replicas of NodeFeatureDiscovery %s/%s already elect a leader in the namespace, only one instance per namespace can run more than one replica",
+					other.Namespace, other.Name)))
+			break
+		}
+	}
+
+	return allErrs, nil
+}
+
+func validateExtraLabelNs(extraLabelNs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, ns := range extraLabelNs {
+		idxPath := fldPath.Index(i)
+		if msgs := validation.IsDNS1123Subdomain(ns); len(msgs) != 0 {
+			for _, msg := range msgs {
+				allErrs = append(allErrs, field.Invalid(idxPath, ns, msg))
+			}
+			continue
+		}
+		if isReservedLabelNs(ns) {
+			allErrs = append(allErrs, field.Forbidden(idxPath,
+				fmt.Sprintf("%q is in a namespace reserved for Kubernetes (%s)", ns, strings.Join(reservedLabelNs, ", "))))
+		}
+	}
+
+	return allErrs
+}
+
+// isReservedLabelNs returns true if ns is, or is a sub-domain of, one of the
+// Kubernetes reserved namespaces, unless it is one that NFD already uses by default
+func isReservedLabelNs(ns string) bool {
+	for _, allowed := range allowedReservedLabelNs {
+		if ns == allowed || strings.HasSuffix(ns, "."+allowed) {
+			return false
+		}
+	}
+	for _, reserved := range reservedLabelNs {
+		if ns == reserved || strings.HasSuffix(ns, "."+reserved) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,413 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+var _ = Describe("Default", func() {
+	ctx := context.Background()
+
+	It("empty operand fields are defaulted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+
+		err := NewNodeFeatureDiscoveryWebhook(nil).Default(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(nfdCR.Spec.Operand.ImagePullPolicy).To(Equal(string(corev1.PullAlways)))
+		Expect(nfdCR.Spec.Operand.ServicePort).To(Equal(12000))
+		Expect(nfdCR.Spec.Operand.Ports).To(Equal(nfdv1.OperandPorts{Worker: 8080, GC: 8080, TopologyUpdater: 8080}))
+	})
+
+	It("explicitly set operand fields are kept", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Operand: nfdv1.OperandSpec{
+					ImagePullPolicy: string(corev1.PullIfNotPresent),
+					ServicePort:     12345,
+					Ports:           nfdv1.OperandPorts{Worker: 8181},
+				},
+			},
+		}
+
+		err := NewNodeFeatureDiscoveryWebhook(nil).Default(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+		Expect(nfdCR.Spec.Operand.ImagePullPolicy).To(Equal(string(corev1.PullIfNotPresent)))
+		Expect(nfdCR.Spec.Operand.ServicePort).To(Equal(12345))
+		Expect(nfdCR.Spec.Operand.Ports.Worker).To(Equal(8181))
+	})
+})
+
+var _ = Describe("ValidateCreate", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+
+	ctx := context.Background()
+
+	workerNode := func(name string, nodeLabels map[string]string) corev1.Node {
+		nodeLabels["node-role.kubernetes.io/worker"] = ""
+		nodeLabels["kubernetes.io/os"] = "linux"
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels}}
+	}
+	listNodes := func(_ interface{}, list *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+		list.Items = []corev1.Node{
+			workerNode("canary-node", map[string]string{"pool": "canary"}),
+			workerNode("production-node", map[string]string{"pool": "production"}),
+		}
+		return nil
+	}
+
+	It("valid spec is accepted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "team-a",
+				Operand: nfdv1.OperandSpec{
+					ImagePullPolicy: string(corev1.PullNever),
+					ServicePort:     12000,
+				},
+				ExtraLabelNs:   []string{"vendor.example.com", "feature.node.kubernetes.io", "sub.profile.node.kubernetes.io"},
+				LabelWhiteList: "^cpu-.*",
+				WorkerConfig: nfdv1.ConfigMap{
+					ConfigData: "core:\n  sleepInterval: 60s\nsources:\n  pci:\n    deviceClassWhitelist: [\"03\"]\n",
+				},
+			},
+		}
+
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+
+		warnings, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(warnings).To(BeEmpty())
+		Expect(err).To(BeNil())
+	})
+
+	DescribeTable("invalid spec is rejected with the offending field path", func(spec nfdv1.NodeFeatureDiscoverySpec, expectedField string) {
+		nfdCR := nfdv1.NodeFeatureDiscovery{Spec: spec}
+
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+		statusErr, ok := err.(*k8serrors.StatusError)
+		Expect(ok).To(BeTrue())
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(HaveField("Field", expectedField)))
+	},
+		Entry("instance that is not a DNS label",
+			nfdv1.NodeFeatureDiscoverySpec{Instance: "Team_A"},
+			"spec.instance"),
+		Entry("instance too long to suffix the generated object names",
+			nfdv1.NodeFeatureDiscoverySpec{Instance: strings.Repeat("a", 43)},
+			"spec.instance"),
+		Entry("bad label whitelist regex",
+			nfdv1.NodeFeatureDiscoverySpec{LabelWhiteList: "cpu-(["},
+			"spec.labelWhiteList"),
+		Entry("unknown image pull policy",
+			nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{ImagePullPolicy: "Sometimes"}},
+			"spec.operand.imagePullPolicy"),
+		Entry("out of range service port",
+			nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{ServicePort: 70000}},
+			"spec.operand.servicePort"),
+		Entry("out of range worker port",
+			nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{Ports: nfdv1.OperandPorts{Worker: -1}}},
+			"spec.operand.ports.worker"),
+		Entry("worker port already bound on the nodes by the kubelet",
+			nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{Ports: nfdv1.OperandPorts{Worker: 10250}}},
+			"spec.operand.ports.worker"),
+		Entry("worker memory request above its limit",
+			nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{WorkerResources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}},
+			"spec.operand.workerResources.requests[memory]"),
+		Entry("extra label namespace reserved by kubernetes.io",
+			nfdv1.NodeFeatureDiscoverySpec{ExtraLabelNs: []string{"vendor.example.com", "node.kubernetes.io"}},
+			"spec.extraLabelNs[1]"),
+		Entry("extra label namespace reserved by k8s.io",
+			nfdv1.NodeFeatureDiscoverySpec{ExtraLabelNs: []string{"k8s.io"}},
+			"spec.extraLabelNs[0]"),
+		Entry("extra label namespace that is not a DNS subdomain",
+			nfdv1.NodeFeatureDiscoverySpec{ExtraLabelNs: []string{"Not_A_Domain"}},
+			"spec.extraLabelNs[0]"),
+		Entry("unparsable worker config",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerConfig: nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 60s\n bad-indent: true\n"}},
+			"spec.workerConfig.configData"),
+		Entry("rule preset missing from the catalog",
+			nfdv1.NodeFeatureDiscoverySpec{RulePresets: []string{"nvidia-gpu", "quantum-accelerator"}},
+			"spec.rulePresets[1]"),
+	)
+
+	It("worker port used by another instance in the namespace is rejected", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test-namespace"},
+			Spec:       nfdv1.NodeFeatureDiscoverySpec{Instance: "canary"},
+		}
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(
+			func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{
+					{ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test-namespace"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "test-namespace"}},
+				}
+				return nil
+			},
+		)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).DoAndReturn(listNodes)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		statusErr, ok := err.(*k8serrors.StatusError)
+		Expect(ok).To(BeTrue())
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(HaveField("Field", "spec.operand.ports.worker")))
+	})
+
+	It("worker port used by an instance in another namespace is rejected", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace"},
+		}
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(
+			func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{
+					{ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "other-namespace"}},
+				}
+				return nil
+			},
+		)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).DoAndReturn(listNodes)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		statusErr, ok := err.(*k8serrors.StatusError)
+		Expect(ok).To(BeTrue())
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(And(
+			HaveField("Field", "spec.operand.ports.worker"),
+			HaveField("Message", ContainSubstring("NodeFeatureDiscovery other-namespace/nfd-instance on node canary-node")),
+		)))
+	})
+
+	It("instances with the same worker port on disjoint node pools are accepted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "canary",
+				Operand:  nfdv1.OperandSpec{WorkerNodeSelector: map[string]string{"pool": "canary"}},
+			},
+		}
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(
+			func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "test-namespace"},
+						Spec: nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{
+							{Name: "production", NodeSelector: map[string]string{"pool": "production"}},
+						}},
+					},
+				}
+				return nil
+			},
+		)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).DoAndReturn(listNodes)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("instances with distinct worker ports are accepted", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "canary",
+				Operand:  nfdv1.OperandSpec{Ports: nfdv1.OperandPorts{Worker: 8181}},
+			},
+		}
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(
+			func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{
+					{ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "test-namespace"}},
+				}
+				return nil
+			},
+		)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("a second instance of the namespace electing a master leader is rejected", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "canary", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{
+				Instance: "canary",
+				Operand:  nfdv1.OperandSpec{MasterReplicas: ptr.To(int32(3)), Ports: nfdv1.OperandPorts{Worker: 8181}},
+			},
+		}
+		otherInstances := func(replicas int32) func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+			return func(_ interface{}, list *nfdv1.NodeFeatureDiscoveryList, _ ...ctrlclient.ListOption) error {
+				list.Items = []nfdv1.NodeFeatureDiscovery{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "production", Namespace: "test-namespace"},
+						Spec:       nfdv1.NodeFeatureDiscoverySpec{Operand: nfdv1.OperandSpec{MasterReplicas: ptr.To(replicas)}},
+					},
+				}
+				return nil
+			}
+		}
+
+		By("the other instance runs several replicas")
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(otherInstances(2)),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{}), ctrlclient.InNamespace("test-namespace")).
+				DoAndReturn(otherInstances(2)),
+		)
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		statusErr, ok := err.(*k8serrors.StatusError)
+		Expect(ok).To(BeTrue())
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(And(
+			HaveField("Field", "spec.operand.masterReplicas"),
+			HaveField("Message", ContainSubstring("NodeFeatureDiscovery test-namespace/production")),
+		)))
+
+		By("the other instance runs a single replica")
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).DoAndReturn(otherInstances(1)),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{}), ctrlclient.InNamespace("test-namespace")).
+				DoAndReturn(otherInstances(1)),
+		)
+
+		_, err = NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("failure to list the other instances", func() {
+		nfdCR := nfdv1.NodeFeatureDiscovery{}
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(fmt.Errorf("some error"))
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		Expect(k8serrors.IsInvalid(err)).To(BeFalse())
+	})
+})
+
+var _ = Describe("ValidateUpdate", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+
+	ctx := context.Background()
+
+	It("new object is validated", func() {
+		oldCR := nfdv1.NodeFeatureDiscovery{}
+		newCR := nfdv1.NodeFeatureDiscovery{
+			Spec: nfdv1.NodeFeatureDiscoverySpec{LabelWhiteList: "("},
+		}
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, &newCR)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("metadata-only updates are accepted without looking at the cluster", func() {
+		oldCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "cpu"},
+			}},
+		}
+		newCR := oldCR.DeepCopy()
+		newCR.Finalizers = []string{"nfd-finalizer"}
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, newCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("updates of an instance being deleted are accepted", func() {
+		now := metav1.Now()
+		oldCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace", DeletionTimestamp: &now},
+		}
+		newCR := oldCR.DeepCopy()
+		newCR.Spec.LabelWhiteList = "("
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, newCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("the other instances and the nodes are only checked when the placement of the workers changes", func() {
+		oldCR := nfdv1.NodeFeatureDiscovery{
+			ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace"},
+			Spec: nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "cpu", NodeSelector: map[string]string{"pool": "cpu"}},
+			}},
+		}
+
+		By("a change unrelated to the workers")
+		newCR := oldCR.DeepCopy()
+		newCR.Spec.LabelWhiteList = "^cpu-.*"
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, newCR)
+		Expect(err).To(BeNil())
+
+		By("a change of the worker port")
+		newCR = oldCR.DeepCopy()
+		newCR.Spec.Operand.Ports.Worker = 8181
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+
+		_, err = NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, newCR)
+		Expect(err).To(BeNil())
+
+		By("a change of the profiles")
+		newCR = oldCR.DeepCopy()
+		newCR.Spec.WorkerProfiles[1].NodeSelector = map[string]string{"pool": "general"}
+		gomock.InOrder(
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil),
+			clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).Return(nil),
+		)
+
+		_, err = NewNodeFeatureDiscoveryWebhook(clnt).ValidateUpdate(ctx, &oldCR, newCR)
+		Expect(err).To(BeNil())
+	})
+})
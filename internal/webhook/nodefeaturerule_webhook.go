@@ -0,0 +1,344 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+)
+
+// +kubebuilder:webhook:path=/validate-nfd-openshift-io-v1alpha1-nodefeaturerule,mutating=false,failurePolicy=fail,sideEffects=None,groups=nfd.openshift.io,resources=nodefeaturerules,verbs=create;update,versions=v1alpha1,name=vnodefeaturerule.nfd.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nfd-k8s-sigs-io-v1alpha1-nodefeaturerule,mutating=false,failurePolicy=fail,sideEffects=None,groups=nfd.k8s-sigs.io,resources=nodefeaturerules,verbs=create;update,versions=v1alpha1,name=vnodefeaturerule.nfd.k8s-sigs.io,admissionReviewVersions=v1
+
+var validTaintEffects = []string{
+	string(corev1.TaintEffectNoSchedule),
+	string(corev1.TaintEffectPreferNoSchedule),
+	string(corev1.TaintEffectNoExecute),
+}
+
+// nodeFeatureRuleWebhook validates the NodeFeatureRule objects of both the
+// nfd.openshift.io and the nfd.k8s-sigs.io API groups, so that invalid rules
+// are rejected before they reach nfd-master
+type nodeFeatureRuleWebhook struct{}
+
+func NewNodeFeatureRuleWebhook() *nodeFeatureRuleWebhook {
+	return &nodeFeatureRuleWebhook{}
+}
+
+// SetupWithManager registers the validating webhooks for NodeFeatureRule
+// with the manager's webhook server
+func (w *nodeFeatureRuleWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureRule{}).
+		WithValidator(w).
+		Complete()
+	if err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nfdk8ssigsiov1alpha1.NodeFeatureRule{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *nodeFeatureRuleWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return w.validate(obj)
+}
+
+func (w *nodeFeatureRuleWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return w.validate(newObj)
+}
+
+func (w *nodeFeatureRuleWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *nodeFeatureRuleWebhook) validate(obj runtime.Object) (admission.Warnings, error) {
+	var (
+		gk   schema.GroupKind
+		name string
+		spec *nfdopenshiftiov1alpha1.NodeFeatureRuleSpec
+	)
+	switch nfr := obj.(type) {
+	case *nfdopenshiftiov1alpha1.NodeFeatureRule:
+		gk = nfdopenshiftiov1alpha1.GroupVersion.WithKind("NodeFeatureRule").GroupKind()
+		name = nfr.Name
+		spec = &nfr.Spec
+	case *nfdk8ssigsiov1alpha1.NodeFeatureRule:
+		gk = nfdk8ssigsiov1alpha1.GroupVersion.WithKind("NodeFeatureRule").GroupKind()
+		name = nfr.Name
+		spec = &nfr.Spec
+	default:
+		return nil, fmt.Errorf("expected a NodeFeatureRule but got a %T", obj)
+	}
+
+	allErrs := validateNodeFeatureRuleSpec(spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, k8serrors.NewInvalid(gk, name, allErrs)
+}
+
+func validateNodeFeatureRuleSpec(spec *nfdopenshiftiov1alpha1.NodeFeatureRuleSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := map[string]bool{}
+	for i := range spec.Rules {
+		rule := &spec.Rules[i]
+		idxPath := fldPath.Child("rules").Index(i)
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), rule.Name))
+		}
+		names[rule.Name] = true
+		allErrs = append(allErrs, validateRule(rule, idxPath)...)
+	}
+
+	return allErrs
+}
+
+func validateRule(rule *nfdopenshiftiov1alpha1.Rule, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if rule.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "rule name is required"))
+	}
+
+	for _, key := range sortedKeys(rule.Labels) {
+		keyPath := fldPath.Child("labels").Key(key)
+		allErrs = append(allErrs, validateQualifiedName(key, keyPath)...)
+		allErrs = append(allErrs, validateLabelValue(rule.Labels[key], keyPath)...)
+	}
+	for _, key := range sortedKeys(rule.Annotations) {
+		allErrs = append(allErrs, validateQualifiedName(key, fldPath.Child("annotations").Key(key))...)
+	}
+	allErrs = append(allErrs, validateTemplate(rule.LabelsTemplate, fldPath.Child("labelsTemplate"))...)
+	allErrs = append(allErrs, validateTemplate(rule.VarsTemplate, fldPath.Child("varsTemplate"))...)
+
+	for i, taint := range rule.Taints {
+		allErrs = append(allErrs, validateTaint(&taint, fldPath.Child("taints").Index(i))...)
+	}
+
+	for _, key := range sortedKeys(rule.ExtendedResources) {
+		keyPath := fldPath.Child("extendedResources").Key(key)
+		allErrs = append(allErrs, validateQualifiedName(key, keyPath)...)
+		allErrs = append(allErrs, validateExtendedResourceValue(rule.ExtendedResources[key], keyPath)...)
+	}
+
+	allErrs = append(allErrs, validateFeatureMatcher(rule.MatchFeatures, fldPath.Child("matchFeatures"))...)
+	for i, elem := range rule.MatchAny {
+		allErrs = append(allErrs, validateFeatureMatcher(elem.MatchFeatures, fldPath.Child("matchAny").Index(i).Child("matchFeatures"))...)
+	}
+
+	return allErrs
+}
+
+func validateFeatureMatcher(matcher nfdopenshiftiov1alpha1.FeatureMatcher, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, term := range matcher {
+		idxPath := fldPath.Index(i)
+		if term.Feature == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("feature"), "feature name is required"))
+		} else if !strings.Contains(term.Feature, ".") {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("feature"), term.Feature,
+				"must be of the form <domain>.<feature>, e.g. cpu.cpuid"))
+		}
+
+		if term.MatchExpressions != nil {
+			for _, key := range sortedKeys(*term.MatchExpressions) {
+				keyPath := idxPath.Child("matchExpressions").Key(key)
+				if key == "" {
+					allErrs = append(allErrs, field.Invalid(keyPath, key, "element name may not be empty"))
+				}
+				allErrs = append(allErrs, validateMatchExpression((*term.MatchExpressions)[key], keyPath)...)
+			}
+		}
+		allErrs = append(allErrs, validateMatchExpression(term.MatchName, idxPath.Child("matchName"))...)
+	}
+
+	return allErrs
+}
+
+// validateMatchExpression checks that the number and the format of the values
+// of the expression fit its operator. A nil expression matches any value
+func validateMatchExpression(expr *nfdopenshiftiov1alpha1.MatchExpression, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if expr == nil {
+		return allErrs
+	}
+
+	valuePath := fldPath.Child("value")
+	switch expr.Op {
+	case nfdopenshiftiov1alpha1.MatchAny, nfdopenshiftiov1alpha1.MatchExists, nfdopenshiftiov1alpha1.MatchDoesNotExist,
+		nfdopenshiftiov1alpha1.MatchIsTrue, nfdopenshiftiov1alpha1.MatchIsFalse:
+		if len(expr.Value) != 0 {
+			allErrs = append(allErrs, field.Invalid(valuePath, expr.Value, fmt.Sprintf("must be empty for operator %q", expr.Op)))
+		}
+	case nfdopenshiftiov1alpha1.MatchIn, nfdopenshiftiov1alpha1.MatchNotIn:
+		if len(expr.Value) == 0 {
+			allErrs = append(allErrs, field.Required(valuePath, fmt.Sprintf("at least one value is required for operator %q", expr.Op)))
+		}
+	case nfdopenshiftiov1alpha1.MatchInRegexp:
+		if len(expr.Value) == 0 {
+			allErrs = append(allErrs, field.Required(valuePath, fmt.Sprintf("at least one value is required for operator %q", expr.Op)))
+		}
+		for i, value := range expr.Value {
+			if _, err := regexp.Compile(value); err != nil {
+				allErrs = append(allErrs, field.Invalid(valuePath.Index(i), value, fmt.Sprintf("must be a valid regular expression: %v", err)))
+			}
+		}
+	case nfdopenshiftiov1alpha1.MatchGt, nfdopenshiftiov1alpha1.MatchGe, nfdopenshiftiov1alpha1.MatchLt,
+		nfdopenshiftiov1alpha1.MatchLe:
+		if len(expr.Value) != 1 {
+			allErrs = append(allErrs, field.Invalid(valuePath, expr.Value, fmt.Sprintf("must have exactly one value for operator %q", expr.Op)))
+			break
+		}
+		allErrs = append(allErrs, validateIntegerValues(expr.Value, valuePath)...)
+	case nfdopenshiftiov1alpha1.MatchGtLt, nfdopenshiftiov1alpha1.MatchGeLe:
+		if len(expr.Value) != 2 {
+			allErrs = append(allErrs, field.Invalid(valuePath, expr.Value, fmt.Sprintf("must have exactly two values for operator %q", expr.Op)))
+			break
+		}
+		intErrs := validateIntegerValues(expr.Value, valuePath)
+		allErrs = append(allErrs, intErrs...)
+		if len(intErrs) == 0 {
+			lower, _ := strconv.Atoi(expr.Value[0])
+			upper, _ := strconv.Atoi(expr.Value[1])
+			if expr.Op == nfdopenshiftiov1alpha1.MatchGtLt && lower >= upper {
+				allErrs = append(allErrs, field.Invalid(valuePath, expr.Value, "the first value must be less than the second one"))
+			}
+			if expr.Op == nfdopenshiftiov1alpha1.MatchGeLe && lower > upper {
+				allErrs = append(allErrs, field.Invalid(valuePath, expr.Value, "the first value must not be greater than the second one"))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("op"), expr.Op, []string{
+			string(nfdopenshiftiov1alpha1.MatchIn), string(nfdopenshiftiov1alpha1.MatchNotIn),
+			string(nfdopenshiftiov1alpha1.MatchInRegexp), string(nfdopenshiftiov1alpha1.MatchExists),
+			string(nfdopenshiftiov1alpha1.MatchDoesNotExist), string(nfdopenshiftiov1alpha1.MatchGt),
+			string(nfdopenshiftiov1alpha1.MatchGe), string(nfdopenshiftiov1alpha1.MatchLt),
+			string(nfdopenshiftiov1alpha1.MatchLe), string(nfdopenshiftiov1alpha1.MatchGtLt),
+			string(nfdopenshiftiov1alpha1.MatchGeLe),
+			string(nfdopenshiftiov1alpha1.MatchIsTrue), string(nfdopenshiftiov1alpha1.MatchIsFalse),
+		}))
+	}
+
+	return allErrs
+}
+
+func validateIntegerValues(values nfdopenshiftiov1alpha1.MatchValue, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, value := range values {
+		if _, err := strconv.Atoi(value); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), value, "must be an integer"))
+		}
+	}
+	return allErrs
+}
+
+// validateTemplate checks that the template parses, the way nfd-master
+// parses it before expanding it
+func validateTemplate(tmpl string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if tmpl == "" {
+		return allErrs
+	}
+	if _, err := template.New("").Option("missingkey=error").Parse(tmpl); err != nil {
+		// the whole template is not echoed back, it can be arbitrarily long
+		allErrs = append(allErrs, field.Invalid(fldPath, "<template>", fmt.Sprintf("must be a valid Go template: %v", err)))
+	}
+	return allErrs
+}
+
+func validateTaint(taint *corev1.Taint, fldPath *field.Path) field.ErrorList {
+	allErrs := validateQualifiedName(taint.Key, fldPath.Child("key"))
+	if taint.Value != "" {
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("value"), taint.Value, msg))
+		}
+	}
+	if taint.Effect == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("effect"), "taint effect is required"))
+	} else if !slices.Contains(validTaintEffects, string(taint.Effect)) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("effect"), taint.Effect, validTaintEffects))
+	}
+	return allErrs
+}
+
+// validateLabelValue checks a static label value, values starting with "@"
+// are references to feature values and are expanded by nfd-master
+func validateLabelValue(value string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if strings.HasPrefix(value, "@") {
+		return allErrs
+	}
+	for _, msg := range validation.IsValidLabelValue(value) {
+		allErrs = append(allErrs, field.Invalid(fldPath, value, msg))
+	}
+	return allErrs
+}
+
+// validateExtendedResourceValue checks a static extended resource quantity,
+// values starting with "@" are references to feature values
+func validateExtendedResourceValue(value string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if strings.HasPrefix(value, "@") {
+		return allErrs
+	}
+	if _, err := resource.ParseQuantity(value); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, value, fmt.Sprintf("must be a valid quantity: %v", err)))
+	}
+	return allErrs
+}
+
+func validateQualifiedName(name string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsQualifiedName(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	return allErrs
+}
+
+// sortedKeys returns the keys of the map in a stable order, so that the
+// errors are always reported in the same order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
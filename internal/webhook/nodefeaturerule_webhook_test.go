@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	nfdk8ssigsiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1temp1"
+	"github.com/openshift/cluster-nfd-operator/internal/presets"
+)
+
+func matchExpressions(key string, op nfdopenshiftiov1alpha1.MatchOp, values ...string) *nfdopenshiftiov1alpha1.MatchExpressionSet {
+	return &nfdopenshiftiov1alpha1.MatchExpressionSet{
+		key: &nfdopenshiftiov1alpha1.MatchExpression{Op: op, Value: values},
+	}
+}
+
+var _ = Describe("validateNodeFeatureRuleSpec", func() {
+	fldPath := field.NewPath("spec")
+
+	It("valid rules are accepted", func() {
+		spec := nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdopenshiftiov1alpha1.Rule{
+				{
+					Name:              "avx512",
+					Labels:            map[string]string{"cpu-avx512": "true", "example.com/cpu-model": "@cpu.model.family"},
+					LabelsTemplate:    "{{ range .cpu.cpuid }}cpu-{{ .Name }}=true\n{{ end }}",
+					Annotations:       map[string]string{"example.com/note": "any value, even with spaces"},
+					Taints:            []corev1.Taint{{Key: "example.com/avx512", Effect: corev1.TaintEffectNoSchedule}},
+					ExtendedResources: map[string]string{"example.com/cpu-avx512": "1", "example.com/cores": "@cpu.topology.cores"},
+					MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+						{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("AVX512F", nfdopenshiftiov1alpha1.MatchExists)},
+						{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchGtLt, "4", "7")},
+						{Feature: "kernel.version", MatchExpressions: matchExpressions("minor", nfdopenshiftiov1alpha1.MatchGeLe, "5", "5")},
+						{Feature: "pci.device", MatchName: &nfdopenshiftiov1alpha1.MatchExpression{
+							Op: nfdopenshiftiov1alpha1.MatchInRegexp, Value: []string{"^0300_10de"}}},
+					},
+				},
+				{
+					Name: "nested",
+					MatchAny: []nfdopenshiftiov1alpha1.MatchAnyElem{
+						{MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+							{Feature: "rule.matched", MatchExpressions: &nfdopenshiftiov1alpha1.MatchExpressionSet{"cpu-avx512": nil}},
+						}},
+					},
+				},
+			},
+		}
+
+		Expect(validateNodeFeatureRuleSpec(&spec, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("invalid rules are rejected with the offending field path", func(rule nfdopenshiftiov1alpha1.Rule, expectedField string) {
+		spec := nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{Rules: []nfdopenshiftiov1alpha1.Rule{rule}}
+
+		errs := validateNodeFeatureRuleSpec(&spec, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", expectedField)))
+	},
+		Entry("unnamed rule",
+			nfdopenshiftiov1alpha1.Rule{},
+			"spec.rules[0].name"),
+		Entry("unknown operator",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("AVX", "Equals", "true")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[AVX].op"),
+		Entry("Exists with values",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "cpu.cpuid", MatchExpressions: matchExpressions("AVX", nfdopenshiftiov1alpha1.MatchExists, "true")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[AVX].value"),
+		Entry("In without values",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "cpu.model", MatchExpressions: matchExpressions("vendor_id", nfdopenshiftiov1alpha1.MatchIn)}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[vendor_id].value"),
+		Entry("Gt with two values",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchGt, "4", "5")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[major].value"),
+		Entry("Lt with a non integer value",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchLt, "five")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[major].value[0]"),
+		Entry("GtLt with an empty range",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchGtLt, "7", "4")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[major].value"),
+		Entry("Ge without value",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchGe)}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[major].value"),
+		Entry("GeLe with an empty range",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "kernel.version", MatchExpressions: matchExpressions("major", nfdopenshiftiov1alpha1.MatchGeLe, "7", "4")}}},
+			"spec.rules[0].matchFeatures[0].matchExpressions[major].value"),
+		Entry("InRegexp with an invalid regexp",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchAny: []nfdopenshiftiov1alpha1.MatchAnyElem{{MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{
+				{Feature: "pci.device", MatchName: &nfdopenshiftiov1alpha1.MatchExpression{
+					Op: nfdopenshiftiov1alpha1.MatchInRegexp, Value: []string{"^0300_(10de"}}}}}}},
+			"spec.rules[0].matchAny[0].matchFeatures[0].matchName.value[0]"),
+		Entry("feature without domain",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", MatchFeatures: nfdopenshiftiov1alpha1.FeatureMatcher{{Feature: "cpuid"}}},
+			"spec.rules[0].matchFeatures[0].feature"),
+		Entry("unparsable labels template",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", LabelsTemplate: "{{ range .cpu.cpuid }}cpu-{{ .Name }}=true"},
+			"spec.rules[0].labelsTemplate"),
+		Entry("unparsable vars template",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", VarsTemplate: "{{ .Name"},
+			"spec.rules[0].varsTemplate"),
+		Entry("invalid label key",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", Labels: map[string]string{"example.com/cpu model": "true"}},
+			"spec.rules[0].labels[example.com/cpu model]"),
+		Entry("invalid label value",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", Labels: map[string]string{"cpu-model": "not a label value"}},
+			"spec.rules[0].labels[cpu-model]"),
+		Entry("invalid taint key",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", Taints: []corev1.Taint{{Key: "example.com/", Effect: corev1.TaintEffectNoExecute}}},
+			"spec.rules[0].taints[0].key"),
+		Entry("unknown taint effect",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", Taints: []corev1.Taint{{Key: "example.com/gpu", Effect: "NoEntry"}}},
+			"spec.rules[0].taints[0].effect"),
+		Entry("invalid extended resource quantity",
+			nfdopenshiftiov1alpha1.Rule{Name: "r", ExtendedResources: map[string]string{"example.com/gpu": "two"}},
+			"spec.rules[0].extendedResources[example.com/gpu]"),
+	)
+
+	It("duplicated rule names are rejected", func() {
+		spec := nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+			Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "r"}, {Name: "r"}},
+		}
+
+		errs := validateNodeFeatureRuleSpec(&spec, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", "spec.rules[1].name")))
+	})
+
+	It("the rules of every preset of the catalog are accepted", func() {
+		for _, name := range presets.Names() {
+			preset, _ := presets.Get(name)
+			Expect(validateNodeFeatureRuleSpec(&preset.Spec, fldPath)).To(BeEmpty(), "preset %s", name)
+		}
+	})
+})
+
+var _ = Describe("nodeFeatureRuleWebhook", func() {
+	ctx := context.Background()
+	invalidSpec := nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+		Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "r", LabelsTemplate: "{{ .Name"}},
+	}
+
+	It("nfd.openshift.io rules are validated", func() {
+		nfr := nfdopenshiftiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rule", Namespace: "test-namespace"},
+			Spec:       invalidSpec,
+		}
+
+		_, err := NewNodeFeatureRuleWebhook().ValidateCreate(ctx, &nfr)
+		Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+		statusErr := err.(*k8serrors.StatusError)
+		Expect(statusErr.ErrStatus.Details.Group).To(Equal("nfd.openshift.io"))
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(HaveField("Field", "spec.rules[0].labelsTemplate")))
+	})
+
+	It("nfd.k8s-sigs.io rules are validated", func() {
+		nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rule", Namespace: "test-namespace"},
+			Spec:       invalidSpec,
+		}
+
+		_, err := NewNodeFeatureRuleWebhook().ValidateUpdate(ctx, &nfr, &nfr)
+		Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+		Expect(err.(*k8serrors.StatusError).ErrStatus.Details.Group).To(Equal("nfd.k8s-sigs.io"))
+	})
+
+	It("valid rules are admitted", func() {
+		nfr := nfdk8ssigsiov1alpha1.NodeFeatureRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-rule", Namespace: "test-namespace"},
+			Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleSpec{
+				Rules: []nfdopenshiftiov1alpha1.Rule{{Name: "r", Labels: map[string]string{"my-label": "true"}}},
+			},
+		}
+
+		_, err := NewNodeFeatureRuleWebhook().ValidateCreate(ctx, &nfr)
+		Expect(err).To(BeNil())
+	})
+
+	It("other objects are refused", func() {
+		_, err := NewNodeFeatureRuleWebhook().ValidateCreate(ctx, &corev1.ConfigMap{})
+		Expect(err).To(HaveOccurred())
+		Expect(k8serrors.IsInvalid(err)).To(BeFalse())
+	})
+})
@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	"github.com/openshift/cluster-nfd-operator/internal/ruletemplate"
+)
+
+// +kubebuilder:webhook:path=/validate-nfd-openshift-io-v1alpha1-nodefeatureruletemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=nfd.openshift.io,resources=nodefeatureruletemplates,verbs=create;update,versions=v1alpha1,name=vnodefeatureruletemplate.nfd.openshift.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-nfd-openshift-io-v1alpha1-nodefeatureruleinstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=nfd.openshift.io,resources=nodefeatureruleinstances,verbs=create;update,versions=v1alpha1,name=vnodefeatureruleinstance.nfd.openshift.io,admissionReviewVersions=v1
+
+// nodeFeatureRuleTemplateWebhook validates the NodeFeatureRuleTemplates and
+// the parameters of their NodeFeatureRuleInstances
+type nodeFeatureRuleTemplateWebhook struct {
+	client client.Client
+}
+
+func NewNodeFeatureRuleTemplateWebhook(client client.Client) *nodeFeatureRuleTemplateWebhook {
+	return &nodeFeatureRuleTemplateWebhook{
+		client: client,
+	}
+}
+
+// SetupWithManager registers the validating webhooks for
+// NodeFeatureRuleTemplate and NodeFeatureRuleInstance with the manager's
+// webhook server
+func (w *nodeFeatureRuleTemplateWebhook) SetupWithManager(mgr ctrl.Manager) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate{}).
+		WithValidator(w).
+		Complete()
+	if err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&nfdopenshiftiov1alpha1.NodeFeatureRuleInstance{}).
+		WithValidator(w).
+		Complete()
+}
+
+func (w *nodeFeatureRuleTemplateWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return w.validate(ctx, obj)
+}
+
+func (w *nodeFeatureRuleTemplateWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return w.validate(ctx, newObj)
+}
+
+func (w *nodeFeatureRuleTemplateWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w *nodeFeatureRuleTemplateWebhook) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	switch o := obj.(type) {
+	case *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate:
+		allErrs := ruletemplate.ValidateTemplate(&o.Spec, field.NewPath("spec"))
+		if len(allErrs) == 0 {
+			return nil, nil
+		}
+		return nil, k8serrors.NewInvalid(nfdopenshiftiov1alpha1.GroupVersion.WithKind("NodeFeatureRuleTemplate").GroupKind(), o.Name, allErrs)
+	case *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance:
+		return w.validateInstance(ctx, o)
+	default:
+		return nil, fmt.Errorf("expected a NodeFeatureRuleTemplate or a NodeFeatureRuleInstance but got a %T", obj)
+	}
+}
+
+// validateInstance checks the parameters of the instance against its
+// template. An instance of a template that does not exist yet is accepted
+// with a warning, it is rendered once the template is created
+func (w *nodeFeatureRuleTemplateWebhook) validateInstance(ctx context.Context,
+	nfri *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance) (admission.Warnings, error) {
+	nfrt := &nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate{}
+	err := w.client.Get(ctx, client.ObjectKey{Name: nfri.Spec.TemplateName}, nfrt)
+	if k8serrors.IsNotFound(err) {
+		return admission.Warnings{fmt.Sprintf("NodeFeatureRuleTemplate %s does not exist", nfri.Spec.TemplateName)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NodeFeatureRuleTemplate %s: %w", nfri.Spec.TemplateName, err)
+	}
+
+	allErrs := ruletemplate.ValidateParameters(nfrt.Spec.Parameters, nfri.Spec.Parameters, field.NewPath("spec", "parameters"))
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, k8serrors.NewInvalid(nfdopenshiftiov1alpha1.GroupVersion.WithKind("NodeFeatureRuleInstance").GroupKind(), nfri.Name, allErrs)
+}
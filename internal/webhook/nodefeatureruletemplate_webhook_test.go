@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdopenshiftiov1alpha1 "github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+var _ = Describe("NodeFeatureRuleTemplate webhook", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+		w    *nodeFeatureRuleTemplateWebhook
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+		w = NewNodeFeatureRuleTemplateWebhook(clnt)
+	})
+
+	ctx := context.Background()
+	nfrt := nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "kernel-module"},
+		Spec: nfdopenshiftiov1alpha1.NodeFeatureRuleTemplateSpec{
+			Parameters: []nfdopenshiftiov1alpha1.TemplateParameter{
+				{Name: "module", Type: nfdopenshiftiov1alpha1.ParameterTypeString, Required: true},
+			},
+			Rules: []nfdopenshiftiov1alpha1.EmbeddedRule{{Name: "$(module)", Labels: map[string]string{"kmod-$(module)": "true"}}},
+		},
+	}
+
+	getTemplate := func() *gomock.Call {
+		return clnt.EXPECT().Get(ctx, ctrlclient.ObjectKey{Name: "kernel-module"}, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ ctrlclient.ObjectKey, obj *nfdopenshiftiov1alpha1.NodeFeatureRuleTemplate, _ ...ctrlclient.GetOption) error {
+				nfrt.DeepCopyInto(obj)
+				return nil
+			})
+	}
+
+	newInstance := func(params map[string]string) *nfdopenshiftiov1alpha1.NodeFeatureRuleInstance {
+		return &nfdopenshiftiov1alpha1.NodeFeatureRuleInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: "vfio", Namespace: "team-a"},
+			Spec:       nfdopenshiftiov1alpha1.NodeFeatureRuleInstanceSpec{TemplateName: "kernel-module", Parameters: params},
+		}
+	}
+
+	It("a valid template is accepted", func() {
+		_, err := w.ValidateCreate(ctx, nfrt.DeepCopy())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("a template referencing an undeclared parameter is rejected", func() {
+		invalid := nfrt.DeepCopy()
+		invalid.Spec.Rules[0].Name = "$(module) $(version)"
+
+		_, err := w.ValidateUpdate(ctx, nfrt.DeepCopy(), invalid)
+		Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("undeclared parameter version")))
+	})
+
+	It("an instance with valid parameters is accepted", func() {
+		getTemplate()
+
+		warnings, err := w.ValidateCreate(ctx, newInstance(map[string]string{"module": "vfio_pci"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("an instance missing a required parameter is rejected", func() {
+		getTemplate()
+
+		_, err := w.ValidateCreate(ctx, newInstance(nil))
+		Expect(k8serrors.IsInvalid(err)).To(BeTrue())
+		Expect(err).To(MatchError(ContainSubstring("spec.parameters[module]")))
+	})
+
+	It("an instance of a missing template is accepted with a warning", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(k8serrors.NewNotFound(schema.GroupResource{}, "kernel-module"))
+
+		warnings, err := w.ValidateCreate(ctx, newInstance(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("NodeFeatureRuleTemplate kernel-module does not exist")))
+	})
+
+	It("error flow, failed to get the template", func() {
+		clnt.EXPECT().Get(ctx, gomock.Any(), gomock.Any()).Return(fmt.Errorf("some error"))
+
+		_, err := w.ValidateCreate(ctx, newInstance(nil))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+func validateTopologyUpdaterConfig(config *nfdv1.TopologyUpdaterConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config == nil {
+		return allErrs
+	}
+
+	if config.SleepInterval != "" {
+		interval, err := time.ParseDuration(config.SleepInterval)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sleepInterval"), config.SleepInterval,
+				fmt.Sprintf("must be a valid duration: %v", err)))
+		} else if interval <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sleepInterval"), config.SleepInterval, "must be positive"))
+		}
+	}
+
+	allErrs = append(allErrs, validateHostPath(config.KubeletStateDir, fldPath.Child("kubeletStateDir"))...)
+	allErrs = append(allErrs, validateHostPath(config.PodResourcesSocketPath, fldPath.Child("podResourcesSocketPath"))...)
+	allErrs = append(allErrs, validateHostPath(config.KubeletConfigFile, fldPath.Child("kubeletConfigFile"))...)
+
+	for _, node := range sortedKeys(config.ExcludeList) {
+		if strings.TrimSpace(node) == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("excludeList"), node, "node name may not be empty"))
+			continue
+		}
+		for i, resource := range config.ExcludeList[node] {
+			if strings.TrimSpace(resource) == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child("excludeList").Key(node).Index(i), "resource name may not be empty"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+func validateHostPath(hostPath string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if hostPath == "" {
+		return allErrs
+	}
+	if !path.IsAbs(hostPath) {
+		allErrs = append(allErrs, field.Invalid(fldPath, hostPath, "must be an absolute path"))
+	} else if path.Clean(hostPath) == "/" {
+		allErrs = append(allErrs, field.Invalid(fldPath, hostPath, "may not be the host root directory"))
+	}
+	return allErrs
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+var _ = Describe("validateTopologyUpdaterConfig", func() {
+	fldPath := field.NewPath("spec", "topologyUpdaterConfig")
+
+	It("valid config is accepted", func() {
+		config := nfdv1.TopologyUpdaterConfig{
+			SleepInterval:          "10s",
+			KubeletStateDir:        "/var/lib/kubelet",
+			PodResourcesSocketPath: "/var/lib/kubelet/pod-resources/kubelet.sock",
+			KubeletConfigFile:      "/etc/kubernetes/kubelet.conf",
+			ExcludeList:            map[string][]string{"*": {"memory"}, "node-1": {"hugepages-2Mi"}},
+		}
+
+		Expect(validateTopologyUpdaterConfig(&config, fldPath)).To(BeEmpty())
+		Expect(validateTopologyUpdaterConfig(nil, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("invalid config is rejected with the offending field path", func(config nfdv1.TopologyUpdaterConfig, expectedField string) {
+		errs := validateTopologyUpdaterConfig(&config, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", expectedField)))
+	},
+		Entry("sleep interval without unit",
+			nfdv1.TopologyUpdaterConfig{SleepInterval: "10"}, "spec.topologyUpdaterConfig.sleepInterval"),
+		Entry("negative sleep interval",
+			nfdv1.TopologyUpdaterConfig{SleepInterval: "-1s"}, "spec.topologyUpdaterConfig.sleepInterval"),
+		Entry("relative kubelet state dir",
+			nfdv1.TopologyUpdaterConfig{KubeletStateDir: "var/lib/kubelet"}, "spec.topologyUpdaterConfig.kubeletStateDir"),
+		Entry("host root as pod resources socket",
+			nfdv1.TopologyUpdaterConfig{PodResourcesSocketPath: "/"}, "spec.topologyUpdaterConfig.podResourcesSocketPath"),
+		Entry("relative kubelet config file",
+			nfdv1.TopologyUpdaterConfig{KubeletConfigFile: "kubelet.conf"}, "spec.topologyUpdaterConfig.kubeletConfigFile"),
+		Entry("empty exclude list node name",
+			nfdv1.TopologyUpdaterConfig{ExcludeList: map[string][]string{"": {"memory"}}}, "spec.topologyUpdaterConfig.excludeList"),
+		Entry("empty excluded resource name",
+			nfdv1.TopologyUpdaterConfig{ExcludeList: map[string][]string{"node-1": {"memory", ""}}}, "spec.topologyUpdaterConfig.excludeList[node-1][1]"),
+	)
+})
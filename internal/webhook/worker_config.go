@@ -0,0 +1,196 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+// knownSources lists the feature and label sources of nfd-worker
+var knownSources = []string{"all", "cpu", "custom", "kernel", "local", "memory", "network", "pci", "storage", "system", "usb"}
+
+var (
+	pciDeviceLabelFields = []string{"class", "vendor", "device", "subsystem_vendor", "subsystem_device"}
+	usbDeviceLabelFields = []string{"class", "vendor", "device", "serial"}
+
+	pciDeviceClassRegexp = regexp.MustCompile(`^[0-9a-fA-F]{2}([0-9a-fA-F]{2})?$`)
+	usbDeviceClassRegexp = regexp.MustCompile(`^[0-9a-fA-F]{2}$`)
+)
+
+func validateWorkerConfig(workerConfig *nfdv1.ConfigMap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if workerConfig.ConfigMapRef != nil {
+		if strings.TrimSpace(workerConfig.ConfigData) != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("configMapRef"), "may not be set together with configData"))
+		}
+		if workerConfig.Config != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("configMapRef"), "may not be set together with config"))
+		}
+		return append(allErrs, validateWorkerConfigMapRef(workerConfig.ConfigMapRef, fldPath.Child("configMapRef"))...)
+	}
+
+	if workerConfig.Config != nil {
+		if strings.TrimSpace(workerConfig.ConfigData) != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("config"), "may not be set together with configData"))
+		}
+		return append(allErrs, validateTypedWorkerConfig(workerConfig.Config, fldPath.Child("config"))...)
+	}
+
+	if strings.TrimSpace(workerConfig.ConfigData) == "" {
+		return allErrs
+	}
+
+	config := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(workerConfig.ConfigData), &config); err != nil {
+		// the whole config is not echoed back, it can be arbitrarily long
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("configData"), "<config data>",
+			fmt.Sprintf("must be valid nfd-worker YAML configuration: %v", err)))
+	}
+
+	return allErrs
+}
+
+func validateWorkerConfigMapRef(ref *corev1.ConfigMapKeySelector, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ref.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), ""))
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(ref.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("name"), ref.Name, msg))
+		}
+	}
+	if ref.Key == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("key"), ""))
+	} else {
+		for _, msg := range validation.IsConfigMapKey(ref.Key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("key"), ref.Key, msg))
+		}
+	}
+
+	return allErrs
+}
+
+func validateTypedWorkerConfig(config *nfdv1.WorkerConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if config.Core != nil {
+		allErrs = append(allErrs, validateWorkerCoreConfig(config.Core, fldPath.Child("core"))...)
+	}
+	if config.Sources != nil {
+		allErrs = append(allErrs, validateWorkerSourcesConfig(config.Sources, fldPath.Child("sources"))...)
+	}
+
+	return allErrs
+}
+
+func validateWorkerCoreConfig(core *nfdv1.WorkerCoreConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if core.SleepInterval != "" {
+		interval, err := time.ParseDuration(core.SleepInterval)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sleepInterval"), core.SleepInterval,
+				fmt.Sprintf("must be a valid duration: %v", err)))
+		} else if interval <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("sleepInterval"), core.SleepInterval, "must be positive"))
+		}
+	}
+
+	allErrs = append(allErrs, validateSourceNames(core.FeatureSources, fldPath.Child("featureSources"))...)
+	allErrs = append(allErrs, validateSourceNames(core.LabelSources, fldPath.Child("labelSources"))...)
+
+	if strings.TrimSpace(core.LabelWhiteList) != "" {
+		if _, err := regexp.Compile(core.LabelWhiteList); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("labelWhiteList"), core.LabelWhiteList,
+				fmt.Sprintf("must be a valid regular expression: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+// validateSourceNames checks a list of sources, where a source prefixed
+// with "-" is disabled
+func validateSourceNames(sources []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, source := range sources {
+		if !slices.Contains(knownSources, strings.TrimPrefix(source, "-")) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i), source, knownSources))
+		}
+	}
+
+	return allErrs
+}
+
+func validateWorkerSourcesConfig(sources *nfdv1.WorkerSourcesConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if sources.PCI != nil {
+		allErrs = append(allErrs, validateDeviceSourceConfig(sources.PCI, pciDeviceClassRegexp, pciDeviceLabelFields, fldPath.Child("pci"))...)
+	}
+	if sources.USB != nil {
+		allErrs = append(allErrs, validateDeviceSourceConfig(sources.USB, usbDeviceClassRegexp, usbDeviceLabelFields, fldPath.Child("usb"))...)
+	}
+
+	names := map[string]bool{}
+	for i, rule := range sources.Custom {
+		namePath := fldPath.Child("custom").Index(i).Child("name")
+		if rule.Name == "" {
+			allErrs = append(allErrs, field.Required(namePath, "custom rules must be named"))
+			continue
+		}
+		if names[rule.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, rule.Name))
+		}
+		names[rule.Name] = true
+	}
+
+	return allErrs
+}
+
+func validateDeviceSourceConfig(source *nfdv1.DeviceSourceConfig, classRegexp *regexp.Regexp, labelFields []string,
+	fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for i, class := range source.DeviceClassWhitelist {
+		if !classRegexp.MatchString(class) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("deviceClassWhitelist").Index(i), class,
+				fmt.Sprintf("must match %s", classRegexp.String())))
+		}
+	}
+	for i, labelField := range source.DeviceLabelFields {
+		if !slices.Contains(labelFields, labelField) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("deviceLabelFields").Index(i), labelField, labelFields))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/api/v1alpha1"
+)
+
+var _ = Describe("validateWorkerConfig", func() {
+	fldPath := field.NewPath("spec", "workerConfig")
+
+	It("valid typed config is accepted", func() {
+		workerConfig := nfdv1.ConfigMap{
+			Config: &nfdv1.WorkerConfig{
+				Core: &nfdv1.WorkerCoreConfig{
+					SleepInterval:  "60s",
+					LabelSources:   []string{"all", "-usb"},
+					LabelWhiteList: "^cpu-",
+				},
+				Sources: &nfdv1.WorkerSourcesConfig{
+					PCI: &nfdv1.DeviceSourceConfig{
+						DeviceClassWhitelist: []string{"0200", "03"},
+						DeviceLabelFields:    []string{"vendor", "subsystem_device"},
+					},
+					USB: &nfdv1.DeviceSourceConfig{
+						DeviceClassWhitelist: []string{"ef"},
+						DeviceLabelFields:    []string{"serial"},
+					},
+					Custom: []v1alpha1.EmbeddedRule{{Name: "rule-1"}, {Name: "rule-2"}},
+				},
+			},
+		}
+
+		Expect(validateWorkerConfig(&workerConfig, fldPath)).To(BeEmpty())
+	})
+
+	It("valid configmap reference is accepted", func() {
+		workerConfig := nfdv1.ConfigMap{
+			ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"},
+				Key:                  "nfd-worker.conf",
+			},
+		}
+
+		Expect(validateWorkerConfig(&workerConfig, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("invalid config is rejected with the offending field path", func(workerConfig nfdv1.ConfigMap, expectedField string) {
+		errs := validateWorkerConfig(&workerConfig, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", expectedField)))
+	},
+		Entry("raw and typed config both set",
+			nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 60s\n", Config: &nfdv1.WorkerConfig{}},
+			"spec.workerConfig.config"),
+		Entry("configmap reference and raw config both set",
+			nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 60s\n", ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"}, Key: "nfd-worker.conf"}},
+			"spec.workerConfig.configMapRef"),
+		Entry("configmap reference and typed config both set",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{}, ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"}, Key: "nfd-worker.conf"}},
+			"spec.workerConfig.configMapRef"),
+		Entry("configmap reference without a name",
+			nfdv1.ConfigMap{ConfigMapRef: &corev1.ConfigMapKeySelector{Key: "nfd-worker.conf"}},
+			"spec.workerConfig.configMapRef.name"),
+		Entry("configmap reference with an invalid name",
+			nfdv1.ConfigMap{ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "User_Config"}, Key: "nfd-worker.conf"}},
+			"spec.workerConfig.configMapRef.name"),
+		Entry("configmap reference without a key",
+			nfdv1.ConfigMap{ConfigMapRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "user-worker-config"}}},
+			"spec.workerConfig.configMapRef.key"),
+		Entry("unparsable sleep interval",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Core: &nfdv1.WorkerCoreConfig{SleepInterval: "60"}}},
+			"spec.workerConfig.config.core.sleepInterval"),
+		Entry("negative sleep interval",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Core: &nfdv1.WorkerCoreConfig{SleepInterval: "-1m"}}},
+			"spec.workerConfig.config.core.sleepInterval"),
+		Entry("unknown feature source",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Core: &nfdv1.WorkerCoreConfig{FeatureSources: []string{"cpu", "-gpu"}}}},
+			"spec.workerConfig.config.core.featureSources[1]"),
+		Entry("bad label whitelist regex",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Core: &nfdv1.WorkerCoreConfig{LabelWhiteList: "(["}}},
+			"spec.workerConfig.config.core.labelWhiteList"),
+		Entry("pci device class that is not hexadecimal",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Sources: &nfdv1.WorkerSourcesConfig{
+				PCI: &nfdv1.DeviceSourceConfig{DeviceClassWhitelist: []string{"03", "display"}}}}},
+			"spec.workerConfig.config.sources.pci.deviceClassWhitelist[1]"),
+		Entry("usb device class with a pci subclass",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Sources: &nfdv1.WorkerSourcesConfig{
+				USB: &nfdv1.DeviceSourceConfig{DeviceClassWhitelist: []string{"0e00"}}}}},
+			"spec.workerConfig.config.sources.usb.deviceClassWhitelist[0]"),
+		Entry("unknown pci device label field",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Sources: &nfdv1.WorkerSourcesConfig{
+				PCI: &nfdv1.DeviceSourceConfig{DeviceLabelFields: []string{"serial"}}}}},
+			"spec.workerConfig.config.sources.pci.deviceLabelFields[0]"),
+		Entry("unnamed custom rule",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Sources: &nfdv1.WorkerSourcesConfig{
+				Custom: []v1alpha1.EmbeddedRule{{Name: "rule-1"}, {}}}}},
+			"spec.workerConfig.config.sources.custom[1].name"),
+		Entry("duplicated custom rule name",
+			nfdv1.ConfigMap{Config: &nfdv1.WorkerConfig{Sources: &nfdv1.WorkerSourcesConfig{
+				Custom: []v1alpha1.EmbeddedRule{{Name: "rule-1"}, {Name: "rule-1"}}}}},
+			"spec.workerConfig.config.sources.custom[1].name"),
+	)
+})
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
+)
+
+func validateWorkerProfiles(spec *nfdv1.NodeFeatureDiscoverySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	// the profile name and the instance are both appended to "nfd-worker"
+	// to name the objects and the "app" label value of the profile
+	maxLen := validation.DNS1123LabelMaxLength - len("nfd-worker-")
+	if spec.Instance != "" {
+		maxLen -= len(spec.Instance) + 1
+	}
+
+	names := map[string]bool{}
+	for i, profile := range spec.WorkerProfiles {
+		idxPath := fldPath.Index(i)
+
+		switch {
+		case profile.Name == "":
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), ""))
+		case names[profile.Name]:
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), profile.Name))
+		default:
+			for _, msg := range validation.IsDNS1123Label(profile.Name) {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("name"), profile.Name, msg))
+			}
+			if len(profile.Name) > maxLen {
+				allErrs = append(allErrs, field.TooLong(idxPath.Child("name"), profile.Name, maxLen))
+			}
+		}
+		names[profile.Name] = true
+
+		if profile.WorkerConfig != nil {
+			allErrs = append(allErrs, validateWorkerConfig(profile.WorkerConfig, idxPath.Child("workerConfig"))...)
+		}
+		allErrs = append(allErrs, validateResources(profile.Resources, idxPath.Child("resources"))...)
+	}
+
+	return allErrs
+}
+
+// validateWorkerProfileOverlaps checks that no node of the cluster is selected
+// by more than one worker profile, since it would then run two workers. The
+// operator checks it again on every reconciliation, as node labels change
+func (w *nodeFeatureDiscoveryWebhook) validateWorkerProfileOverlaps(ctx context.Context, nfdInstance *nfdv1.NodeFeatureDiscovery,
+	fldPath *field.Path) (field.ErrorList, error) {
+	allErrs := field.ErrorList{}
+
+	if len(nfdInstance.Spec.WorkerProfiles) < 2 {
+		return allErrs, nil
+	}
+
+	nodes := corev1.NodeList{}
+	if err := w.client.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	if overlaps := daemonset.FindWorkerProfileOverlaps(nfdInstance.GetWorkerProfiles(), nodes.Items); len(overlaps) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath,
+			fmt.Sprintf("profiles must not select the same nodes: %s", strings.Join(overlaps, "; "))))
+	}
+
+	return allErrs, nil
+}
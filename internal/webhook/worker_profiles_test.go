@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+	"github.com/openshift/cluster-nfd-operator/internal/client"
+)
+
+var _ = Describe("validateWorkerProfiles", func() {
+	fldPath := field.NewPath("spec", "workerProfiles")
+
+	It("valid profiles are accepted", func() {
+		spec := nfdv1.NodeFeatureDiscoverySpec{
+			Instance: "team-a",
+			WorkerProfiles: []nfdv1.WorkerProfile{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "sriov", WorkerConfig: &nfdv1.ConfigMap{ConfigData: "core:\n  sleepInterval: 60s\n"}},
+			},
+		}
+
+		Expect(validateWorkerProfiles(&spec, fldPath)).To(BeEmpty())
+	})
+
+	DescribeTable("invalid profiles are rejected with the offending field path", func(spec nfdv1.NodeFeatureDiscoverySpec, expectedField string) {
+		errs := validateWorkerProfiles(&spec, fldPath)
+		Expect(errs).To(ContainElement(HaveField("Field", expectedField)))
+	},
+		Entry("unnamed profile",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{{Name: "gpu"}, {}}},
+			"spec.workerProfiles[1].name"),
+		Entry("duplicated profile name",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{{Name: "gpu"}, {Name: "gpu"}}},
+			"spec.workerProfiles[1].name"),
+		Entry("profile name that is not a DNS label",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{{Name: "GPU_Pool"}}},
+			"spec.workerProfiles[0].name"),
+		Entry("profile name too long once suffixed with the instance",
+			nfdv1.NodeFeatureDiscoverySpec{Instance: "team-a", WorkerProfiles: []nfdv1.WorkerProfile{{Name: strings.Repeat("a", 46)}}},
+			"spec.workerProfiles[0].name"),
+		Entry("invalid profile worker config",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{{Name: "gpu", WorkerConfig: &nfdv1.ConfigMap{
+				Config: &nfdv1.WorkerConfig{Core: &nfdv1.WorkerCoreConfig{SleepInterval: "60"}}}}}},
+			"spec.workerProfiles[0].workerConfig.config.core.sleepInterval"),
+		Entry("profile memory request above its limit",
+			nfdv1.NodeFeatureDiscoverySpec{WorkerProfiles: []nfdv1.WorkerProfile{{Name: "gpu", Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			}}}},
+			"spec.workerProfiles[0].resources.requests[memory]"),
+	)
+})
+
+var _ = Describe("validateWorkerProfileOverlaps", func() {
+	var (
+		ctrl *gomock.Controller
+		clnt *client.MockClient
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		clnt = client.NewMockClient(ctrl)
+	})
+
+	ctx := context.Background()
+	nfdCR := nfdv1.NodeFeatureDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfd-instance", Namespace: "test-namespace"},
+		Spec: nfdv1.NodeFeatureDiscoverySpec{
+			WorkerProfiles: []nfdv1.WorkerProfile{
+				{Name: "gpu", NodeSelector: map[string]string{"pool": "gpu"}},
+				{Name: "all"},
+			},
+		},
+	}
+	listNodes := func(nodeLabels map[string]string) func(_ interface{}, nodes *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+		return func(_ interface{}, nodes *corev1.NodeList, _ ...ctrlclient.ListOption) error {
+			nodeLabels["node-role.kubernetes.io/worker"] = ""
+			nodeLabels["kubernetes.io/os"] = "linux"
+			nodes.Items = []corev1.Node{{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: nodeLabels}}}
+			return nil
+		}
+	}
+
+	It("profiles selecting the same node are rejected", func() {
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).DoAndReturn(listNodes(map[string]string{"pool": "gpu"}))
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		statusErr, ok := err.(*k8serrors.StatusError)
+		Expect(ok).To(BeTrue())
+		Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(And(
+			HaveField("Field", "spec.workerProfiles"),
+			HaveField("Message", ContainSubstring("node node-1 is selected by profiles gpu, all")),
+		)))
+	})
+
+	It("profiles selecting distinct nodes are accepted", func() {
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).DoAndReturn(listNodes(map[string]string{"pool": "sriov"}))
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(BeNil())
+	})
+
+	It("failure to list the nodes", func() {
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&nfdv1.NodeFeatureDiscoveryList{})).Return(nil)
+		clnt.EXPECT().List(ctx, gomock.AssignableToTypeOf(&corev1.NodeList{})).Return(fmt.Errorf("some error"))
+
+		_, err := NewNodeFeatureDiscoveryWebhook(clnt).ValidateCreate(ctx, &nfdCR)
+		Expect(err).To(HaveOccurred())
+		Expect(k8serrors.IsInvalid(err)).To(BeFalse())
+	})
+})
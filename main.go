@@ -24,8 +24,11 @@ import (
 	"k8s.io/klog/v2/textlogger"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	securityscheme "github.com/openshift/client-go/security/clientset/versioned/scheme"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -43,10 +46,18 @@ import (
 	new_controllers "github.com/openshift/cluster-nfd-operator/internal/controllers"
 	"github.com/openshift/cluster-nfd-operator/internal/daemonset"
 	"github.com/openshift/cluster-nfd-operator/internal/deployment"
+	"github.com/openshift/cluster-nfd-operator/internal/featurefile"
 	"github.com/openshift/cluster-nfd-operator/internal/job"
+	"github.com/openshift/cluster-nfd-operator/internal/migration"
 	"github.com/openshift/cluster-nfd-operator/internal/networkpolicy"
+	"github.com/openshift/cluster-nfd-operator/internal/noderesourcetopology"
+	"github.com/openshift/cluster-nfd-operator/internal/poddisruptionbudget"
+	"github.com/openshift/cluster-nfd-operator/internal/presets"
+	"github.com/openshift/cluster-nfd-operator/internal/rules"
+	"github.com/openshift/cluster-nfd-operator/internal/rulestatus"
 	"github.com/openshift/cluster-nfd-operator/internal/scc"
 	"github.com/openshift/cluster-nfd-operator/internal/status"
+	nfdwebhook "github.com/openshift/cluster-nfd-operator/internal/webhook"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -60,6 +71,7 @@ const (
 	// ProgramName is the canonical name of this program
 	ProgramName          = "nfd-operator"
 	watchNamespaceEnvVar = "POD_NAMESPACE"
+	enableWebhooksEnvVar = "ENABLE_WEBHOOKS"
 )
 
 // operatorArgs holds command line arguments
@@ -68,11 +80,13 @@ type operatorArgs struct {
 	enableLeaderElection       bool
 	conversionManagerProbeAddr string
 	probeAddr                  string
+	mirrorAdoptionPolicy       string
 }
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(securityscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(nfdopenshiftv1.AddToScheme(scheme))
 	utilruntime.Must(nfdopenshiftiov1alpha1.AddToScheme(scheme))
@@ -87,6 +101,14 @@ func main() {
 	// the bind port under './config' and './manifests'
 	probeAddr := ":8081"
 
+	// The rules subcommand evaluates NodeFeatureRules offline, without a cluster
+	if len(os.Args) > 1 && os.Args[1] == "rules" {
+		os.Exit(rules.RunCommand(ProgramName, os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate-rules" {
+		os.Exit(migration.RunCommand(ctrl.SetupSignalHandler(), ProgramName, os.Args[2:], os.Stdout, os.Stderr, newClusterClient))
+	}
+
 	flags := flag.NewFlagSet(ProgramName, flag.ExitOnError)
 
 	printVersion := flags.Bool("version", false, "Print version and exit.")
@@ -111,6 +133,11 @@ func main() {
 		fmt.Println(ProgramName, version)
 		os.Exit(0)
 	}
+	adoptionPolicy := new_controllers.MirrorAdoptionPolicy(args.mirrorAdoptionPolicy)
+	if adoptionPolicy != new_controllers.MirrorAdoptionPolicyRefuse && adoptionPolicy != new_controllers.MirrorAdoptionPolicyAdopt {
+		setupLogger.Error(fmt.Errorf("invalid value %q", args.mirrorAdoptionPolicy), "invalid NodeFeatureRule adoption policy")
+		os.Exit(2)
+	}
 	watchNamespace, err := getWatchNamespace()
 	if err != nil {
 		setupLogger.Error(err, "WatchNamespaceEnvVar is not set")
@@ -139,6 +166,11 @@ func main() {
 				watchNamespace: cache.Config{},
 			},
 		},
+		// the events are only listed for the pod diagnostics of the status,
+		// with field selectors served by the API server
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Event{}}},
+		},
 	})
 
 	if err != nil {
@@ -149,13 +181,28 @@ func main() {
 	client := mgr.GetClient()
 	scheme := mgr.GetScheme()
 
+	// SCCs only exist on OpenShift, Pod Security Admission labels are used
+	// instead on other clusters
+	sccAvailable, err := scc.IsSCCAPIAvailable(mgr.GetRESTMapper())
+	if err != nil {
+		setupLogger.Error(err, "unable to discover the SecurityContextConstraints API")
+		os.Exit(1)
+	}
+	if !sccAvailable {
+		setupLogger.Info("SecurityContextConstraints API not available, operand namespaces are labelled for Pod Security Admission")
+	}
+
 	deploymentAPI := deployment.NewDeploymentAPI(client, scheme)
 	daemonsetAPI := daemonset.NewDaemonsetAPI(client, scheme)
 	configmapAPI := configmap.NewConfigMapAPI(client, scheme)
 	jobAPI := job.NewJobAPI(client, scheme)
 	sccAPI := scc.NewSccAPI(client, scheme)
 	networkPolicyAPI := networkpolicy.NewNetworkPolicyAPI(client, scheme)
-	statusAPI := status.NewStatusAPI(deploymentAPI, daemonsetAPI)
+	pdbAPI := poddisruptionbudget.NewPodDisruptionBudgetAPI(client, scheme)
+	nrtAPI := noderesourcetopology.NewNodeResourceTopologyAPI(client)
+	statusAPI := status.NewStatusAPI(client, deploymentAPI, daemonsetAPI, configmapAPI)
+	featureFileAPI := featurefile.NewFeatureFileAPI(client, scheme)
+	presetAPI := presets.NewPresetAPI(client, scheme)
 
 	recorder := mgr.GetEventRecorderFor("nodefeaturediscovery-controller")
 
@@ -166,14 +213,37 @@ func main() {
 		jobAPI,
 		sccAPI,
 		networkPolicyAPI,
+		pdbAPI,
+		nrtAPI,
 		statusAPI,
+		featureFileAPI,
+		presetAPI,
 		scheme,
-		recorder).SetupWithManager(mgr); err != nil {
+		recorder,
+		sccAvailable).SetupWithManager(mgr); err != nil {
 		setupLogger.Error(err, "unable to create controller", "controller", "NodeFeatureDiscovery")
 		os.Exit(1)
 	}
+
+	// Webhooks can be disabled when running the operator locally, where no
+	// serving certificates are available
+	if os.Getenv(enableWebhooksEnvVar) != "false" {
+		if err = nfdwebhook.NewNodeFeatureDiscoveryWebhook(mgr.GetAPIReader()).SetupWithManager(mgr); err != nil {
+			setupLogger.Error(err, "unable to create webhook", "webhook", "NodeFeatureDiscovery")
+			os.Exit(1)
+		}
+		if err = nfdwebhook.NewNodeFeatureRuleWebhook().SetupWithManager(mgr); err != nil {
+			setupLogger.Error(err, "unable to create webhook", "webhook", "NodeFeatureRule")
+			os.Exit(1)
+		}
+		if err = nfdwebhook.NewNodeFeatureRuleTemplateWebhook(client).SetupWithManager(mgr); err != nil {
+			setupLogger.Error(err, "unable to create webhook", "webhook", "NodeFeatureRuleTemplate")
+			os.Exit(1)
+		}
+	}
+
 	stopCh := ctrl.SetupSignalHandler()
-	conversionInitialization(args.conversionManagerProbeAddr, setupLogger, stopCh)
+	conversionInitialization(args.conversionManagerProbeAddr, watchNamespace, adoptionPolicy, setupLogger, stopCh)
 	// +kubebuilder:scaffold:builder
 
 	// Next, add a Healthz checker to the manager. Healthz is a health and liveness package
@@ -212,10 +282,23 @@ func initFlags(flagset *flag.FlagSet) *operatorArgs {
 	flagset.BoolVar(&args.enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flagset.StringVar(&args.mirrorAdoptionPolicy, "nodefeaturerule-adoption-policy", string(new_controllers.MirrorAdoptionPolicyRefuse),
+		"What to do when the nfd.k8s-sigs.io NodeFeatureRule or NodeFeatureGroup mirroring a nfd.openshift.io "+
+			"object already exists and is not owned by the operator: Refuse or Adopt.")
 
 	return &args
 }
 
+// newClusterClient returns a client to the cluster of the current kubeconfig,
+// for the subcommands run outside of the operator
+func newClusterClient() (client.Client, error) {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
+
 // getWatchNamespace returns the Namespace the operator should be watching for changes
 func getWatchNamespace() (string, error) {
 	value, present := os.LookupEnv(watchNamespaceEnvVar)
@@ -225,23 +308,40 @@ func getWatchNamespace() (string, error) {
 	return value, nil
 }
 
-func conversionInitialization(conversionManagerProbeAddr string, setupLogger logr.Logger, stopCh context.Context) {
+func conversionInitialization(conversionManagerProbeAddr, watchNamespace string, adoptionPolicy new_controllers.MirrorAdoptionPolicy,
+	setupLogger logr.Logger, stopCh context.Context) {
 	conversionMgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		HealthProbeBindAddress: conversionManagerProbeAddr,
 		Metrics:                metricsserver.Options{BindAddress: "0"},
 		LeaderElection:         false,
+		// the manager watches the whole cluster, the migration report is
+		// the only ConfigMap it reads
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.ConfigMap{}}},
+		},
 	})
 	if err != nil {
 		setupLogger.Error(err, "unable to start manager for NodeFeatureRule controller")
 		os.Exit(1)
 	}
 
-	if err = new_controllers.NewNodeFeatureRuleReconciler(conversionMgr.GetClient(), conversionMgr.GetScheme()).
-		SetupWithManager(conversionMgr); err != nil {
+	if err = new_controllers.NewNodeFeatureRuleReconciler(conversionMgr.GetClient(), conversionMgr.GetScheme(),
+		rulestatus.NewRuleStatusAPI(conversionMgr.GetClient()), migration.NewMigrationAPI(conversionMgr.GetClient(), watchNamespace),
+		conversionMgr.GetEventRecorderFor("nodefeaturerule-controller"), adoptionPolicy).SetupWithManager(conversionMgr); err != nil {
 		setupLogger.Error(err, "unable to create NodeFeatureRule controller")
 		os.Exit(1)
 	}
+	if err = new_controllers.NewNodeFeatureGroupReconciler(conversionMgr.GetClient(), conversionMgr.GetScheme(),
+		conversionMgr.GetEventRecorderFor("nodefeaturegroup-controller"), adoptionPolicy).SetupWithManager(conversionMgr); err != nil {
+		setupLogger.Error(err, "unable to create NodeFeatureGroup controller")
+		os.Exit(1)
+	}
+	if err = new_controllers.NewNodeFeatureRuleInstanceReconciler(conversionMgr.GetClient(), conversionMgr.GetScheme(),
+		conversionMgr.GetEventRecorderFor("nodefeatureruleinstance-controller")).SetupWithManager(conversionMgr); err != nil {
+		setupLogger.Error(err, "unable to create NodeFeatureRuleInstance controller")
+		os.Exit(1)
+	}
 	go func() {
 		if err := conversionMgr.Start(stopCh); err != nil {
 			setupLogger.Error(err, "problem running manager for NodeFeatureRule controller")
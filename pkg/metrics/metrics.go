@@ -18,13 +18,16 @@ package metrics
 import (
 	"github.com/prometheus/client_golang/prometheus"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
 )
 
 // When adding metric names, see https://prometheus.io/docs/practices/naming/#metric-names
 const (
-	degradedInfoQuery = "nfd_degraded_info"
-	buildInfoQuery    = "nfd_build_info"
-	instanceInfoQuery = "nfd_instance_info"
+	degradedInfoQuery        = "nfd_degraded_info"
+	buildInfoQuery           = "nfd_build_info"
+	instanceInfoQuery        = "nfd_instance_info"
+	managementStateInfoQuery = "nfd_management_state_info"
 )
 
 var (
@@ -49,6 +52,15 @@ var (
 		},
 		[]string{"version"},
 	)
+	managementStateInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: managementStateInfoQuery,
+			Help: "Management state of the NodeFeatureDiscovery instances, 1 for the current state and 0 for the others.",
+		},
+		[]string{"instance", "namespace", "state"},
+	)
+
+	managementStates = []nfdv1.ManagementState{nfdv1.Managed, nfdv1.Unmanaged, nfdv1.Removed}
 
 	instanceList = make(map[string]bool)
 )
@@ -77,12 +89,30 @@ func Degraded(deg bool) {
 	degradedState.Set(0)
 }
 
+// ManagementState sets the metric that reports the management state of the
+// instance, so that alerts can fire on instances left Unmanaged or Removed.
+func ManagementState(instance string, namespace string, state nfdv1.ManagementState) {
+	for _, s := range managementStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		managementStateInfo.WithLabelValues(instance, namespace, string(s)).Set(value)
+	}
+}
+
+// DeleteManagementState drops the management state of the deleted instance.
+func DeleteManagementState(instance string, namespace string) {
+	managementStateInfo.DeletePartialMatch(prometheus.Labels{"instance": instance, "namespace": namespace})
+}
+
 // Register custom metrics with the global prometheus registry
 func init() {
 	metrics.Registry.MustRegister(
 		degradedState,
 		buildInfo,
 		instanceInfo,
+		managementStateInfo,
 	)
 
 	registerVersion(version)
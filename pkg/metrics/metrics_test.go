@@ -0,0 +1,55 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+
+	nfdv1 "github.com/openshift/cluster-nfd-operator/api/v1"
+)
+
+func managementStateValue(instance string, namespace string, state nfdv1.ManagementState) float64 {
+	metric := dto.Metric{}
+	Expect(managementStateInfo.WithLabelValues(instance, namespace, string(state)).Write(&metric)).To(Succeed())
+	return metric.GetGauge().GetValue()
+}
+
+var _ = Describe("ManagementState", func() {
+	AfterEach(func() {
+		DeleteManagementState("nfd-instance", "test-namespace")
+	})
+
+	It("the current state is 1 and the other states are 0", func() {
+		ManagementState("nfd-instance", "test-namespace", nfdv1.Managed)
+
+		Expect(managementStateValue("nfd-instance", "test-namespace", nfdv1.Managed)).To(Equal(1.0))
+		Expect(managementStateValue("nfd-instance", "test-namespace", nfdv1.Unmanaged)).To(Equal(0.0))
+		Expect(managementStateValue("nfd-instance", "test-namespace", nfdv1.Removed)).To(Equal(0.0))
+	})
+
+	It("a change of state is reported and the series are deleted with the instance", func() {
+		ManagementState("nfd-instance", "test-namespace", nfdv1.Managed)
+		ManagementState("nfd-instance", "test-namespace", nfdv1.Unmanaged)
+
+		Expect(managementStateValue("nfd-instance", "test-namespace", nfdv1.Managed)).To(Equal(0.0))
+		Expect(managementStateValue("nfd-instance", "test-namespace", nfdv1.Unmanaged)).To(Equal(1.0))
+
+		DeleteManagementState("nfd-instance", "test-namespace")
+		Expect(managementStateInfo.DeletePartialMatch(map[string]string{"namespace": "test-namespace"})).To(Equal(0))
+	})
+})